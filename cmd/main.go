@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 
+	"cep-temperatura/internal/alerts"
+	"cep-temperatura/internal/apikeys"
 	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/graphql"
+	"cep-temperatura/internal/grpcserver"
 	"cep-temperatura/internal/handlers"
+	"cep-temperatura/internal/openapi"
 	"cep-temperatura/internal/services"
+	"cep-temperatura/internal/wshub"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,19 +34,172 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 
 	// Criar instâncias dos serviços
-	cepService := services.NewCEPService()
-	weatherService := services.NewWeatherService(cfg)
-	temperatureService := services.NewTemperatureService()
+	cepService, err := services.NewCEPService(cfg)
+	if err != nil {
+		log.Fatalf("Erro ao criar serviço de CEP: %v", err)
+	}
+	weatherService, err := services.NewWeatherService(cfg)
+	if err != nil {
+		log.Fatalf("Erro ao criar serviço de clima: %v", err)
+	}
+	temperatureService := services.NewTemperatureService(
+		services.WithRounding(services.Rounding{Mode: cfg.Temperature.Rounding, Decimals: cfg.Temperature.Decimals}),
+		services.WithLegacyKelvin(cfg.Temperature.LegacyKelvin),
+	)
+
+	// Consultas periódicas de clima, compartilhadas pelos streams SSE e gRPC e pelas assinaturas
+	// WebSocket do mesmo município
+	watcher := services.NewWeatherWatcher(
+		weatherService,
+		services.WithPollInterval(cfg.Stream.Interval),
+		services.WithPollTimeout(cfg.Server.RequestTimeout),
+	)
+
+	// Avaliação periódica das regras de alerta, com os webhooks entregues em segundo plano
+	evaluator := alerts.NewEvaluator(
+		weatherService,
+		temperatureService,
+		alerts.WithInterval(cfg.Alerts.Interval),
+		alerts.WithTimeout(cfg.Server.RequestTimeout),
+		alerts.WithMaxRules(cfg.Alerts.MaxRules),
+		alerts.WithRetry(cfg.Alerts.MaxAttempts, cfg.Alerts.Backoff, cfg.Alerts.MaxBackoff),
+		alerts.WithDeadLetterLimit(cfg.Alerts.DeadLetters),
+		alerts.WithPrivateNetworks(cfg.Alerts.AllowPrivateNetworks),
+	)
+	go evaluator.Run(context.Background())
+
+	// Chaves de API dos clientes, com o consumo gravado periodicamente em disco
+	keyOptions := []apikeys.Option{apikeys.WithFlushInterval(cfg.APIKeys.FlushInterval)}
+	for _, key := range cfg.APIKeys.Keys {
+		keyOptions = append(keyOptions, apikeys.WithConfigKey(key.Name, key.Hash, key.DailyQuota, key.MonthlyQuota))
+	}
+	keyStore, err := apikeys.NewStore(cfg.APIKeys.StorePath, keyOptions...)
+	if err != nil {
+		log.Fatalf("Erro ao carregar chaves de API: %v", err)
+	}
+	go keyStore.Run(context.Background())
+	keyAuth := handlers.NewAPIKeyAuth(
+		keyStore,
+		cfg.APIKeys.AdminToken,
+		handlers.WithDefaultQuotas(cfg.APIKeys.DailyQuota, cfg.APIKeys.MonthlyQuota),
+	)
+	if cfg.APIKeys.AdminToken == "" {
+		log.Printf("Aviso: API_KEYS_ADMIN_TOKEN vazio; /alerts/dead-letters e /admin/keys recusam todas as requisições")
+	}
 
 	// Criar handler
-	handler := handlers.NewTemperatureHandler(cepService, weatherService, temperatureService)
+	handler := handlers.NewTemperatureHandler(
+		cepService,
+		weatherService,
+		temperatureService,
+		handlers.WithRequestBudget(cfg.Server.RequestTimeout, cfg.Server.CEPBudgetShare),
+		handlers.WithBatchLimits(cfg.Batch.MaxCEPs, cfg.Batch.Workers),
+		handlers.WithWeatherWatcher(watcher),
+		handlers.WithStreamHeartbeat(cfg.Stream.Heartbeat),
+		handlers.WithAlertEvaluator(evaluator),
+	)
+
+	// Validador do contrato OpenAPI; em produção, apenas as requisições, quando ativado
+	validator, err := openapi.NewValidator(
+		openapi.WithRequestValidation(cfg.OpenAPI.ValidateRequests),
+		openapi.WithErrorWriter(handlers.WriteError),
+	)
+	if err != nil {
+		log.Fatalf("Erro ao carregar documento OpenAPI: %v", err)
+	}
 
 	// Configurar roteador
 	router := gin.Default()
+	router.Use(handlers.RequestID, validator.Middleware)
+	router.GET("/openapi.json", openapi.ServeSpec)
+	router.GET("/docs", openapi.ServeDocs)
+	router.GET("/problems/:code", handlers.GetProblemType)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
-	router.GET("/temperature/:cep", handler.GetTemperature)
+
+	// Rotas de consulta, que exigem uma chave de API com cota quando as chaves estão ativadas
+	metered := router.Group("")
+	if cfg.APIKeys.Enabled {
+		metered.Use(keyAuth.RequireKey)
+	}
+
+	// Rotas do handler de temperatura, com o formato da resposta negociado via Accept ou ?format=
+	// e os cabeçalhos de cache de cada rota GET
+	cache := handlers.NewResponseCache(cfg.Cache.MaxEntries, handlers.WithPrivateCache(cfg.APIKeys.Enabled))
+	api := metered.Group("", handlers.NegotiateFormat)
+	api.GET("/temperature/:cep", cache.Handle(cfg.Cache.Temperature), handler.GetTemperature)
+	api.POST("/temperature/batch", handler.GetTemperatureBatch)
+	api.GET("/forecast/:cep", cache.Handle(cfg.Cache.Forecast), handler.GetForecast)
+	api.GET("/history/:cep", cache.Handle(cfg.Cache.History), handler.GetHistory)
+	api.GET("/convert", cache.Handle(cfg.Cache.Convert), handler.Convert)
+	api.POST("/convert", handler.ConvertBatch)
+
+	// Stream da temperatura em Server-Sent Events, fora da negociação de formato
+	metered.GET("/temperature/:cep/stream", handler.GetTemperatureStream)
+
+	// Regras de alerta de temperatura, notificadas por webhook. Com as chaves ativadas, cada
+	// regra pertence à chave que a cadastrou; os webhooks não entregues ficam com a administração.
+	metered.POST("/alerts", handler.CreateAlert)
+	metered.GET("/alerts", handler.ListAlerts)
+	router.GET("/alerts/dead-letters", keyAuth.RequireAdmin, handler.ListDeadLetters)
+	metered.GET("/alerts/:id", handler.GetAlert)
+	metered.DELETE("/alerts/:id", handler.DeleteAlert)
+
+	// Hub WebSocket: vários CEPs por conexão, com as mesmas consultas periódicas dos streams
+	metered.GET("/ws", gin.WrapH(wshub.NewHub(
+		cepService,
+		weatherService,
+		temperatureService,
+		wshub.WithRequestTimeout(cfg.Server.RequestTimeout),
+		wshub.WithMaxSubscriptions(cfg.WebSocket.MaxSubscriptions),
+		wshub.WithWeatherWatcher(watcher),
+	)))
+
+	// GraphQL, com os mesmos serviços e limites de lote
+	metered.POST("/graphql", gin.WrapH(graphql.NewHandler(
+		cepService,
+		weatherService,
+		temperatureService,
+		graphql.WithRequestTimeout(cfg.Server.RequestTimeout),
+		graphql.WithBatchLimits(cfg.Batch.MaxCEPs, cfg.Batch.Workers),
+	)))
+
+	// Administração das chaves de API, com o token de administração
+	admin := router.Group("/admin", keyAuth.RequireAdmin)
+	admin.POST("/keys", keyAuth.CreateKey)
+	admin.GET("/keys", keyAuth.ListKeys)
+	admin.DELETE("/keys/:id", keyAuth.RevokeKey)
+
+	if reporter, ok := cepService.(services.ProviderStatsReporter); ok {
+		metered.GET("/stats/cep-providers", func(c *gin.Context) {
+			c.JSON(200, reporter.ProviderStats())
+		})
+	}
+
+	// Iniciar servidor gRPC ao lado do HTTP, com os mesmos serviços
+	// e, com as chaves ativadas, a mesma exigência de chave e cota
+	if cfg.GRPC.Port != "" {
+		grpcOptions := []grpcserver.Option{
+			grpcserver.WithRequestTimeout(cfg.Server.RequestTimeout),
+			grpcserver.WithBatchLimits(cfg.Batch.MaxCEPs, cfg.Batch.Workers),
+			grpcserver.WithWeatherWatcher(watcher),
+		}
+		if cfg.APIKeys.Enabled {
+			grpcOptions = append(grpcOptions, grpcserver.WithAPIKeys(keyStore))
+		}
+		grpcServer := grpcserver.NewServer(cepService, weatherService, temperatureService, grpcOptions...).Register()
+
+		grpcAddress := cfg.GetGRPCAddress()
+		listener, err := net.Listen("tcp", grpcAddress)
+		if err != nil {
+			log.Fatalf("Erro ao abrir porta gRPC: %v", err)
+		}
+		go func() {
+			log.Printf("Servidor gRPC iniciado em %s", grpcAddress)
+			log.Fatal(grpcServer.Serve(listener))
+		}()
+	}
 
 	// Iniciar servidor
 	address := cfg.GetServerAddress()
@@ -0,0 +1,382 @@
+// Package alerts avalia periodicamente regras de alerta de temperatura por CEP e notifica os
+// clientes por webhooks assinados com HMAC, com novas tentativas e uma lista de entregas
+// não realizadas.
+package alerts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+)
+
+// Erros do cadastro de regras
+var (
+	ErrInvalidRule  = errors.New("invalid alert rule")
+	ErrRuleNotFound = errors.New("alert rule not found")
+	ErrTooManyRules = errors.New("too many alert rules")
+)
+
+// Condições de uma regra na última avaliação
+const (
+	StateUnknown = "unknown"
+	StateNormal  = "normal"
+	StateAbove   = "above"
+	StateBelow   = "below"
+)
+
+// Valores padrão do avaliador
+const (
+	defaultInterval    = time.Minute
+	defaultTimeout     = 10 * time.Second
+	defaultMaxRules    = 1000
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Second
+	defaultMaxBackoff  = 5 * time.Minute
+	defaultDeadLetters = 100
+)
+
+// Rule é uma regra de alerta: notificar URL quando a temperatura do CEP passar de Above ou
+// ficar abaixo de Below, em Celsius. Location é a localização do CEP já resolvida; Owner
+// identifica quem cadastrou a regra, o único que a consulta e a remove.
+type Rule struct {
+	ID        string
+	Owner     string
+	CEP       string
+	URL       string
+	Above     *float64
+	Below     *float64
+	Secret    string
+	Location  *models.CEPResponse
+	State     string
+	CreatedAt time.Time
+}
+
+// Evaluator guarda as regras e as avalia a cada intervalo. Regras do mesmo município
+// compartilham uma consulta de clima; cada regra notifica apenas quando a temperatura cruza
+// um limite, e não a cada avaliação em que continua além dele.
+type Evaluator struct {
+	weatherService     services.WeatherService
+	temperatureService services.TemperatureService
+	client             *http.Client
+	allowPrivate       bool
+
+	interval    time.Duration
+	timeout     time.Duration
+	maxRules    int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	deadLimit   int
+
+	mu          sync.Mutex
+	rules       map[string]*Rule
+	deadLetters []models.DeadLetter
+
+	deliveries sync.WaitGroup
+}
+
+// Option personaliza o Evaluator
+type Option func(*Evaluator)
+
+// WithInterval define o intervalo entre as avaliações das regras
+func WithInterval(interval time.Duration) Option {
+	return func(e *Evaluator) {
+		e.interval = interval
+	}
+}
+
+// WithTimeout limita cada consulta de clima e cada tentativa de entrega; zero as deixa sem
+// prazo próprio
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *Evaluator) {
+		e.timeout = timeout
+	}
+}
+
+// WithMaxRules limita o número de regras cadastradas
+func WithMaxRules(maxRules int) Option {
+	return func(e *Evaluator) {
+		e.maxRules = maxRules
+	}
+}
+
+// WithRetry define o número máximo de tentativas de cada webhook e o intervalo entre elas,
+// que dobra a cada falha até maxBackoff
+func WithRetry(maxAttempts int, backoff, maxBackoff time.Duration) Option {
+	return func(e *Evaluator) {
+		e.maxAttempts = maxAttempts
+		e.backoff = backoff
+		e.maxBackoff = maxBackoff
+	}
+}
+
+// WithDeadLetterLimit limita quantos webhooks não entregues são guardados; os mais antigos
+// são descartados
+func WithDeadLetterLimit(limit int) Option {
+	return func(e *Evaluator) {
+		e.deadLimit = limit
+	}
+}
+
+// WithPrivateNetworks permite entregar webhooks a endereços de loopback, link-local e de
+// redes privadas, recusados por padrão; serve aos testes e aos receptores da rede interna
+func WithPrivateNetworks(allowed bool) Option {
+	return func(e *Evaluator) {
+		e.allowPrivate = allowed
+	}
+}
+
+// WithHTTPClient define o cliente HTTP das entregas, no lugar do cliente que recusa endereços
+// locais e privados
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Evaluator) {
+		e.client = client
+	}
+}
+
+// NewEvaluator cria o avaliador de regras de alerta
+func NewEvaluator(
+	weatherService services.WeatherService,
+	temperatureService services.TemperatureService,
+	opts ...Option,
+) *Evaluator {
+	e := &Evaluator{
+		weatherService:     weatherService,
+		temperatureService: temperatureService,
+		interval:           defaultInterval,
+		timeout:            defaultTimeout,
+		maxRules:           defaultMaxRules,
+		maxAttempts:        defaultMaxAttempts,
+		backoff:            defaultBackoff,
+		maxBackoff:         defaultMaxBackoff,
+		deadLimit:          defaultDeadLetters,
+		rules:              map[string]*Rule{},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.client == nil {
+		e.client = newWebhookClient(e.allowPrivate)
+	}
+	return e
+}
+
+// Add valida e cadastra a regra, gerando o ID e, quando não informado, o segredo
+func (e *Evaluator) Add(rule Rule) (Rule, error) {
+	if err := validateRule(rule); err != nil {
+		return Rule{}, err
+	}
+	if rule.Secret == "" {
+		rule.Secret = randomID()
+	}
+	rule.ID = randomID()
+	rule.State = StateUnknown
+	rule.CreatedAt = time.Now().UTC()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.rules) >= e.maxRules {
+		return Rule{}, ErrTooManyRules
+	}
+	e.rules[rule.ID] = &rule
+	return rule, nil
+}
+
+// Get devolve a regra do dono cadastrada com o ID. As regras de outros donos não são
+// encontradas.
+func (e *Evaluator) Get(owner, id string) (Rule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rule, ok := e.rules[id]
+	if !ok || rule.Owner != owner {
+		return Rule{}, ErrRuleNotFound
+	}
+	return *rule, nil
+}
+
+// Remove descadastra a regra do dono; as entregas em andamento continuam
+func (e *Evaluator) Remove(owner, id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if rule, ok := e.rules[id]; !ok || rule.Owner != owner {
+		return ErrRuleNotFound
+	}
+	delete(e.rules, id)
+	return nil
+}
+
+// RulesOf devolve as regras do dono, da mais antiga à mais recente
+func (e *Evaluator) RulesOf(owner string) []Rule {
+	return e.filterRules(func(rule *Rule) bool { return rule.Owner == owner })
+}
+
+// Rules devolve as regras de todos os donos, da mais antiga à mais recente
+func (e *Evaluator) Rules() []Rule {
+	return e.filterRules(func(*Rule) bool { return true })
+}
+
+func (e *Evaluator) filterRules(keep func(*Rule) bool) []Rule {
+	e.mu.Lock()
+	rules := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		if keep(rule) {
+			rules = append(rules, *rule)
+		}
+	}
+	e.mu.Unlock()
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].ID < rules[j].ID
+		}
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules
+}
+
+// DeadLetters devolve os webhooks que esgotaram as tentativas, do mais antigo ao mais recente
+func (e *Evaluator) DeadLetters() []models.DeadLetter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]models.DeadLetter{}, e.deadLetters...)
+}
+
+// Run avalia as regras a cada intervalo até o contexto ser cancelado, esperando as entregas
+// em andamento terminarem
+func (e *Evaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.deliveries.Wait()
+			return
+		case <-ticker.C:
+			e.evaluate(ctx)
+		}
+	}
+}
+
+// evaluate consulta o clima de cada município com regras e dispara os webhooks das regras
+// cuja condição mudou para acima ou abaixo do limite
+func (e *Evaluator) evaluate(ctx context.Context) {
+	groups := map[string][]Rule{}
+	for _, rule := range e.Rules() {
+		key := services.MunicipalityKey(rule.Location)
+		groups[key] = append(groups[key], rule)
+	}
+
+	for _, rules := range groups {
+		queryCtx, cancel := e.timeoutContext(ctx)
+		weather, err := e.weatherService.GetTemperature(queryCtx, services.WeatherQueryFor(rules[0].Location))
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Alertas: erro ao consultar o clima de %s: %v", rules[0].CEP, err)
+			continue
+		}
+		for _, rule := range rules {
+			e.check(ctx, rule, weather.TempC)
+		}
+	}
+}
+
+// check atualiza a condição da regra e notifica quando ela passa a estar acima ou abaixo do limite
+func (e *Evaluator) check(ctx context.Context, rule Rule, celsius float64) {
+	state, threshold := condition(rule, celsius)
+
+	e.mu.Lock()
+	current, ok := e.rules[rule.ID]
+	if !ok {
+		// Regra removida durante a avaliação
+		e.mu.Unlock()
+		return
+	}
+	previous := current.State
+	current.State = state
+	e.mu.Unlock()
+
+	if state == StateNormal || state == previous {
+		return
+	}
+
+	fahrenheit, kelvin := e.temperatureService.ConvertTemperatures(celsius)
+	event := models.AlertEvent{
+		ID:          randomID(),
+		AlertID:     rule.ID,
+		CEP:         rule.CEP,
+		Condition:   state,
+		Threshold:   threshold,
+		TempC:       celsius,
+		TempF:       fahrenheit,
+		TempK:       kelvin,
+		TriggeredAt: time.Now().UTC(),
+	}
+	e.deliveries.Add(1)
+	go func() {
+		defer e.deliveries.Done()
+		e.deliver(ctx, rule, event)
+	}()
+}
+
+// timeoutContext aplica o prazo de cada consulta e entrega, quando houver
+func (e *Evaluator) timeoutContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, e.timeout)
+}
+
+// condition classifica a temperatura em relação aos limites da regra
+func condition(rule Rule, celsius float64) (string, float64) {
+	switch {
+	case rule.Above != nil && celsius > *rule.Above:
+		return StateAbove, *rule.Above
+	case rule.Below != nil && celsius < *rule.Below:
+		return StateBelow, *rule.Below
+	default:
+		return StateNormal, 0
+	}
+}
+
+// validateRule exige uma URL HTTP(S) absoluta e ao menos um limite, com Below abaixo de Above
+func validateRule(rule Rule) error {
+	target, err := url.Parse(rule.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidRule)
+	}
+	if rule.Above == nil && rule.Below == nil {
+		return fmt.Errorf("%w: above or below is required", ErrInvalidRule)
+	}
+	for _, threshold := range []*float64{rule.Above, rule.Below} {
+		if threshold != nil && (math.IsNaN(*threshold) || math.IsInf(*threshold, 0)) {
+			return fmt.Errorf("%w: thresholds must be finite", ErrInvalidRule)
+		}
+	}
+	if rule.Above != nil && rule.Below != nil && *rule.Below >= *rule.Above {
+		return fmt.Errorf("%w: below must be lower than above", ErrInvalidRule)
+	}
+	return nil
+}
+
+// randomID gera um identificador aleatório de 16 bytes em hexadecimal
+func randomID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
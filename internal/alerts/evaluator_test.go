@@ -0,0 +1,289 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWeatherService é um mock do WeatherService
+type MockWeatherService struct {
+	mock.Mock
+}
+
+func (m *MockWeatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResult), args.Error(1)
+}
+
+func (m *MockWeatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options services.ForecastOptions) (*models.ForecastResult, error) {
+	return nil, services.ErrForecastUnsupported
+}
+
+func (m *MockWeatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options services.HistoryOptions) (*models.HistoryResult, error) {
+	return nil, services.ErrHistoryUnsupported
+}
+
+var (
+	paulista   = &models.CEPResponse{CEP: "01310-100", Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	se         = &models.CEPResponse{CEP: "01001-000", Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	copacabana = &models.CEPResponse{CEP: "22070-002", Localidade: "Rio de Janeiro", UF: "RJ", IBGE: "3304557"}
+)
+
+// delivery é um webhook recebido pelo receptor local
+type delivery struct {
+	header http.Header
+	body   []byte
+	event  models.AlertEvent
+}
+
+// receiver é um receptor local de webhooks. Responde com os status de statuses, na ordem, e
+// depois com 204.
+type receiver struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	statuses   []int
+	deliveries []delivery
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var event models.AlertEvent
+		_ = json.Unmarshal(body, &event)
+
+		r.mu.Lock()
+		r.deliveries = append(r.deliveries, delivery{header: req.Header.Clone(), body: body, event: event})
+		status := http.StatusNoContent
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]delivery{}, r.deliveries...)
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+// newTestEvaluator cria um avaliador que entrega aos receptores locais dos testes
+func newTestEvaluator(weatherService services.WeatherService, opts ...Option) *Evaluator {
+	opts = append([]Option{WithRetry(3, time.Millisecond, 4*time.Millisecond), WithPrivateNetworks(true)}, opts...)
+	return NewEvaluator(weatherService, services.NewTemperatureService(), opts...)
+}
+
+// evaluateAndWait avalia as regras e espera as entregas disparadas terminarem
+func evaluateAndWait(e *Evaluator) {
+	e.evaluate(context.Background())
+	e.deliveries.Wait()
+}
+
+func TestEvaluator_Add(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		error string
+	}{
+		{name: "acima", rule: Rule{URL: "https://example.com/hook", Above: float(35)}},
+		{name: "abaixo", rule: Rule{URL: "http://example.com/hook", Below: float(5)}},
+		{name: "faixa", rule: Rule{URL: "https://example.com/hook", Above: float(35), Below: float(5)}},
+		{name: "sem limite", rule: Rule{URL: "https://example.com/hook"}, error: "invalid alert rule: above or below is required"},
+		{name: "faixa invertida", rule: Rule{URL: "https://example.com/hook", Above: float(5), Below: float(35)}, error: "invalid alert rule: below must be lower than above"},
+		{name: "URL relativa", rule: Rule{URL: "/hook", Above: float(35)}, error: "invalid alert rule: url must be an absolute http or https URL"},
+		{name: "esquema inválido", rule: Rule{URL: "ftp://example.com/hook", Above: float(35)}, error: "invalid alert rule: url must be an absolute http or https URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEvaluator(new(MockWeatherService))
+			rule, err := e.Add(tt.rule)
+			if tt.error != "" {
+				assert.ErrorIs(t, err, ErrInvalidRule)
+				assert.EqualError(t, err, tt.error)
+				assert.Empty(t, e.Rules())
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, rule.ID, 32)
+			assert.Len(t, rule.Secret, 32)
+			assert.Equal(t, StateUnknown, rule.State)
+			assert.Equal(t, []Rule{rule}, e.Rules())
+		})
+	}
+}
+
+func TestEvaluator_AddKeepsSecret(t *testing.T) {
+	e := newTestEvaluator(new(MockWeatherService))
+	rule, err := e.Add(Rule{URL: "https://example.com/hook", Above: float(35), Secret: "s3cr3t"})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", rule.Secret)
+}
+
+func TestEvaluator_MaxRules(t *testing.T) {
+	e := newTestEvaluator(new(MockWeatherService), WithMaxRules(1))
+	_, err := e.Add(Rule{URL: "https://example.com/hook", Above: float(35)})
+	require.NoError(t, err)
+	_, err = e.Add(Rule{URL: "https://example.com/hook", Above: float(35)})
+	assert.ErrorIs(t, err, ErrTooManyRules)
+}
+
+func TestEvaluator_Remove(t *testing.T) {
+	e := newTestEvaluator(new(MockWeatherService))
+	rule, err := e.Add(Rule{URL: "https://example.com/hook", Above: float(35)})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Remove("", rule.ID))
+	_, err = e.Get("", rule.ID)
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+	assert.ErrorIs(t, e.Remove("", rule.ID), ErrRuleNotFound)
+}
+
+func TestEvaluator_Owner(t *testing.T) {
+	e := newTestEvaluator(new(MockWeatherService))
+	mine, err := e.Add(Rule{Owner: "cliente-a", URL: "https://example.com/hook", Above: float(35)})
+	require.NoError(t, err)
+	_, err = e.Add(Rule{Owner: "cliente-b", URL: "https://example.com/hook", Above: float(35)})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Rule{mine}, e.RulesOf("cliente-a"))
+	assert.Len(t, e.Rules(), 2)
+
+	// As regras de outro dono não são encontradas nem removidas
+	_, err = e.Get("cliente-b", mine.ID)
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+	assert.ErrorIs(t, e.Remove("cliente-b", mine.ID), ErrRuleNotFound)
+	_, err = e.Get("cliente-a", mine.ID)
+	assert.NoError(t, err)
+}
+
+func TestEvaluator_NotifiesOnCrossing(t *testing.T) {
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 36}, nil).Twice()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 20}, nil).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 4}, nil).Once()
+
+	hook := newReceiver(t)
+	e := newTestEvaluator(weatherService)
+	rule, err := e.Add(Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Below: float(5), Location: paulista})
+	require.NoError(t, err)
+
+	// A segunda avaliação acima do limite não notifica de novo
+	evaluateAndWait(e)
+	evaluateAndWait(e)
+	require.Len(t, hook.received(), 1)
+	current, _ := e.Get("", rule.ID)
+	assert.Equal(t, StateAbove, current.State)
+
+	evaluateAndWait(e)
+	require.Len(t, hook.received(), 1)
+	current, _ = e.Get("", rule.ID)
+	assert.Equal(t, StateNormal, current.State)
+
+	evaluateAndWait(e)
+	received := hook.received()
+	require.Len(t, received, 2)
+
+	above, below := received[0].event, received[1].event
+	assert.Equal(t, rule.ID, above.AlertID)
+	assert.Equal(t, "01310100", above.CEP)
+	assert.Equal(t, StateAbove, above.Condition)
+	assert.Equal(t, 35.0, above.Threshold)
+	assert.Equal(t, 36.0, above.TempC)
+	assert.Equal(t, 96.8, above.TempF)
+	assert.Equal(t, 309.15, above.TempK)
+	assert.Equal(t, StateBelow, below.Condition)
+	assert.Equal(t, 5.0, below.Threshold)
+	assert.NotEqual(t, above.ID, below.ID)
+}
+
+func TestEvaluator_SharesQueryPerMunicipality(t *testing.T) {
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(paulista)).Return(&models.WeatherResult{TempC: 36}, nil).Once()
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(copacabana)).Return(&models.WeatherResult{TempC: 30}, nil).Once()
+
+	hook := newReceiver(t)
+	e := newTestEvaluator(weatherService)
+	for _, rule := range []Rule{
+		{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista},
+		{CEP: "01001000", URL: hook.URL, Above: float(35), Location: se},
+		{CEP: "22070002", URL: hook.URL, Above: float(35), Location: copacabana},
+	} {
+		_, err := e.Add(rule)
+		require.NoError(t, err)
+	}
+
+	evaluateAndWait(e)
+	weatherService.AssertExpectations(t)
+	assert.Len(t, hook.received(), 2)
+}
+
+func TestEvaluator_WeatherError(t *testing.T) {
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(nil, services.ErrUpstreamTimeout).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 36}, nil).Once()
+
+	hook := newReceiver(t)
+	e := newTestEvaluator(weatherService)
+	rule, err := e.Add(Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+	require.NoError(t, err)
+
+	// Uma consulta que falha mantém a condição anterior
+	evaluateAndWait(e)
+	current, _ := e.Get("", rule.ID)
+	assert.Equal(t, StateUnknown, current.State)
+	assert.Empty(t, hook.received())
+
+	evaluateAndWait(e)
+	assert.Len(t, hook.received(), 1)
+}
+
+func TestEvaluator_Run(t *testing.T) {
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 36}, nil)
+
+	hook := newReceiver(t)
+	e := newTestEvaluator(weatherService, WithInterval(5*time.Millisecond))
+	_, err := e.Add(Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(hook.received()) == 1 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run não terminou após o cancelamento")
+	}
+}
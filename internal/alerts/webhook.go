@@ -0,0 +1,184 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"cep-temperatura/internal/models"
+)
+
+// Cabeçalhos dos webhooks
+const (
+	SignatureHeader = "X-Alert-Signature"
+	TimestampHeader = "X-Alert-Timestamp"
+	DeliveryHeader  = "X-Alert-Delivery"
+)
+
+// Erros das entregas
+var (
+	// errPermanent marca as respostas que uma nova tentativa não resolveria
+	errPermanent = errors.New("permanent webhook failure")
+	// errBlockedAddress recusa a conexão a um endereço local ou privado
+	errBlockedAddress = errors.New("webhook address not allowed")
+)
+
+// newWebhookClient cria o cliente das entregas. Sem allowPrivate, a conexão é recusada quando o
+// endereço, já resolvido pelo DNS, é de loopback, link-local, de rede privada, multicast ou não
+// especificado; a verificação a cada conexão vale também para nomes que passam a apontar para a
+// rede interna depois do cadastro. Os redirecionamentos não são seguidos, e os proxies do
+// ambiente são ignorados, para que a conexão verificada seja a do receptor.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+	if !allowPrivate {
+		dialer.Control = refuseBlockedAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// refuseBlockedAddress confere o endereço resolvido de cada conexão, antes de conectar
+func refuseBlockedAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBlockedAddress, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || blockedAddress(ip.Unmap()) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, host)
+	}
+	return nil
+}
+
+// blockedAddress indica se o endereço é de loopback, link-local, de rede privada, multicast ou
+// não especificado
+func blockedAddress(ip netip.Addr) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// Sign calcula a assinatura de um webhook: HMAC-SHA256 de "<timestamp>.<corpo>" com o segredo
+// da regra, em hexadecimal com o prefixo "sha256=". O receptor recalcula a assinatura com o
+// valor de X-Alert-Timestamp e a compara com X-Alert-Signature.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver envia o webhook, tentando de novo com intervalos crescentes. Quando as tentativas
+// se esgotam, ou o receptor recusa o webhook de forma definitiva, a entrega vai para a lista
+// de não entregues. Entregas interrompidas pelo encerramento do avaliador são descartadas.
+func (e *Evaluator) deliver(ctx context.Context, rule Rule, event models.AlertEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		e.deadLetter(rule, event, 0, err)
+		return
+	}
+
+	attempts := 0
+	for {
+		attempts++
+		err = e.send(ctx, rule, event.ID, body)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errPermanent) || attempts >= e.maxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(e.backoffFor(attempts))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+	e.deadLetter(rule, event, attempts, err)
+}
+
+// send faz uma tentativa de entrega. Respostas 2xx confirmam a entrega; 408, 429 e 5xx
+// permitem nova tentativa, e os demais status são falhas definitivas.
+func (e *Evaluator) send(ctx context.Context, rule Rule, deliveryID string, body []byte) error {
+	ctx, cancel := e.timeoutContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(rule.Secret, timestamp, body))
+
+	resp, err := e.client.Do(req)
+	if errors.Is(err, errBlockedAddress) {
+		return fmt.Errorf("%w: %w", errPermanent, errBlockedAddress)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: webhook responded with status %d", errPermanent, resp.StatusCode)
+	}
+}
+
+// backoffFor calcula a espera após a tentativa: o intervalo inicial, dobrado a cada falha
+// anterior, até o máximo
+func (e *Evaluator) backoffFor(attempt int) time.Duration {
+	wait := e.backoff
+	for i := 1; i < attempt && wait < e.maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, e.maxBackoff)
+}
+
+// deadLetter guarda a entrega que falhou, descartando as mais antigas além do limite
+func (e *Evaluator) deadLetter(rule Rule, event models.AlertEvent, attempts int, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.deadLetters = append(e.deadLetters, models.DeadLetter{
+		Event:    event,
+		URL:      rule.URL,
+		Attempts: attempts,
+		Error:    err.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+	if overflow := len(e.deadLetters) - e.deadLimit; overflow > 0 {
+		e.deadLetters = append([]models.DeadLetter{}, e.deadLetters[overflow:]...)
+	}
+}
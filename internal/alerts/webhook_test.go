@@ -0,0 +1,191 @@
+package alerts
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fire dispara uma entrega da regra e espera o resultado
+func fire(t *testing.T, e *Evaluator, rule Rule) models.AlertEvent {
+	t.Helper()
+	rule, err := e.Add(rule)
+	require.NoError(t, err)
+
+	event := models.AlertEvent{ID: "delivery-1", AlertID: rule.ID, CEP: rule.CEP, Condition: StateAbove, Threshold: 35, TempC: 36}
+	e.deliver(context.Background(), rule, event)
+	return event
+}
+
+func TestSign(t *testing.T) {
+	// Valor de referência: printf '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac s3cr3t
+	assert.Equal(t,
+		"sha256=482d731874034ab9787715174409d9a9ac267ec2590b2b5326e43ed9d7c11646",
+		Sign("s3cr3t", "1700000000", []byte(`{"id":"1"}`)),
+	)
+}
+
+func TestDeliver_Signed(t *testing.T) {
+	hook := newReceiver(t)
+	e := newTestEvaluator(new(MockWeatherService))
+	event := fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Secret: "s3cr3t", Location: paulista})
+
+	received := hook.received()
+	require.Len(t, received, 1)
+	header := received[0].header
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "delivery-1", header.Get(DeliveryHeader))
+	assert.Equal(t, Sign("s3cr3t", header.Get(TimestampHeader), received[0].body), header.Get(SignatureHeader))
+	assert.Equal(t, event, received[0].event)
+
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), time.Minute)
+	assert.Empty(t, e.DeadLetters())
+}
+
+func TestDeliver_RetriesUntilDelivered(t *testing.T) {
+	hook := newReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	e := newTestEvaluator(new(MockWeatherService))
+	fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+
+	// As novas tentativas repetem o ID da entrega, para o receptor descartar duplicatas
+	received := hook.received()
+	require.Len(t, received, 3)
+	for _, delivery := range received {
+		assert.Equal(t, "delivery-1", delivery.header.Get(DeliveryHeader))
+	}
+	assert.Empty(t, e.DeadLetters())
+}
+
+func TestDeliver_DeadLetter(t *testing.T) {
+	hook := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable)
+	e := newTestEvaluator(new(MockWeatherService))
+	event := fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+
+	assert.Len(t, hook.received(), 3)
+	deadLetters := e.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, event, deadLetters[0].Event)
+	assert.Equal(t, hook.URL, deadLetters[0].URL)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, "webhook responded with status 503", deadLetters[0].Error)
+}
+
+func TestDeliver_PermanentFailure(t *testing.T) {
+	hook := newReceiver(t, http.StatusGone)
+	e := newTestEvaluator(new(MockWeatherService))
+	fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+
+	// Um 4xx definitivo não é tentado de novo
+	assert.Len(t, hook.received(), 1)
+	deadLetters := e.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+	assert.Equal(t, "permanent webhook failure: webhook responded with status 410", deadLetters[0].Error)
+}
+
+func TestDeliver_Unreachable(t *testing.T) {
+	hook := newReceiver(t)
+	hook.Close()
+	e := newTestEvaluator(new(MockWeatherService))
+	fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+
+	deadLetters := e.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+}
+
+func TestDeliver_BlockedAddress(t *testing.T) {
+	hook := newReceiver(t)
+	// Sem WithPrivateNetworks, o receptor local é recusado antes da conexão
+	e := NewEvaluator(new(MockWeatherService), nil, WithRetry(3, time.Millisecond, time.Millisecond))
+	fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+
+	assert.Empty(t, hook.received())
+	deadLetters := e.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+	assert.Equal(t, "permanent webhook failure: webhook address not allowed", deadLetters[0].Error)
+}
+
+func TestDeliver_NoRedirects(t *testing.T) {
+	target := newReceiver(t)
+	hook := newReceiver(t)
+	hook.Config.Handler = http.RedirectHandler(target.URL, http.StatusFound)
+	e := newTestEvaluator(new(MockWeatherService))
+	fire(t, e, Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+
+	// O redirecionamento não é seguido: o destino poderia ser um endereço recusado
+	assert.Empty(t, target.received())
+	deadLetters := e.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, "permanent webhook failure: webhook responded with status 302", deadLetters[0].Error)
+}
+
+func TestBlockedAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.0.0.8", true},
+		{"172.16.0.1", true},
+		{"192.168.1.10", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"::ffff:127.0.0.1", true},
+		{"200.160.2.3", false},
+		{"2804:214::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := refuseBlockedAddress("tcp", net.JoinHostPort(tt.address, "443"), nil)
+			if tt.blocked {
+				assert.ErrorIs(t, err, errBlockedAddress)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeadLetterLimit(t *testing.T) {
+	hook := newReceiver(t, http.StatusGone, http.StatusGone, http.StatusGone)
+	e := newTestEvaluator(new(MockWeatherService), WithDeadLetterLimit(2))
+	rule, err := e.Add(Rule{CEP: "01310100", URL: hook.URL, Above: float(35), Location: paulista})
+	require.NoError(t, err)
+
+	for _, id := range []string{"1", "2", "3"} {
+		e.deliver(context.Background(), rule, models.AlertEvent{ID: id})
+	}
+
+	deadLetters := e.DeadLetters()
+	require.Len(t, deadLetters, 2)
+	assert.Equal(t, "2", deadLetters[0].Event.ID)
+	assert.Equal(t, "3", deadLetters[1].Event.ID)
+}
+
+func TestBackoffFor(t *testing.T) {
+	e := NewEvaluator(new(MockWeatherService), nil, WithRetry(10, time.Second, 10*time.Second))
+
+	assert.Equal(t, time.Second, e.backoffFor(1))
+	assert.Equal(t, 2*time.Second, e.backoffFor(2))
+	assert.Equal(t, 4*time.Second, e.backoffFor(3))
+	assert.Equal(t, 8*time.Second, e.backoffFor(4))
+	assert.Equal(t, 10*time.Second, e.backoffFor(5))
+	assert.Equal(t, 10*time.Second, e.backoffFor(9))
+}
@@ -0,0 +1,439 @@
+// Package apikeys guarda as chaves de API dos clientes, com cotas diárias e mensais por
+// chave. As chaves são guardadas apenas como hash SHA-256, e o consumo é gravado em disco
+// para sobreviver a reinícios.
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Erros do cadastro e do consumo das chaves
+var (
+	ErrInvalidKey    = errors.New("invalid api key")
+	ErrKeyNotFound   = errors.New("api key not found")
+	ErrInvalidQuota  = errors.New("invalid api key quota")
+	ErrQuotaExceeded = errors.New("api key quota exceeded")
+)
+
+// Valores padrão do Store
+const (
+	defaultFlushInterval = 10 * time.Second
+	keyPrefix            = "ct_"
+	keyBytes             = 24
+	visiblePrefix        = len(keyPrefix) + 6
+)
+
+// Formatos das janelas de consumo, em UTC
+const (
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+// Key é uma chave cadastrada. Hash é o SHA-256 da chave em hexadecimal; a chave em si só é
+// conhecida no cadastro. Cotas zeradas não limitam o consumo. Config marca as chaves da
+// configuração, que deixam de valer quando são retiradas dela.
+type Key struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Hash         string     `json:"hash"`
+	Prefix       string     `json:"prefix,omitempty"`
+	DailyQuota   int64      `json:"daily_quota"`
+	MonthlyQuota int64      `json:"monthly_quota"`
+	Usage        Usage      `json:"usage"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	Config       bool       `json:"config,omitempty"`
+}
+
+// Usage é o consumo da chave no dia e no mês correntes
+type Usage struct {
+	Day        string `json:"day"`
+	DayCount   int64  `json:"day_count"`
+	Month      string `json:"month"`
+	MonthCount int64  `json:"month_count"`
+}
+
+// Quota descreve a janela de consumo mais próxima do limite após uma requisição: o limite,
+// quantas requisições restam e quando a janela recomeça. Limit zero indica uma chave sem cotas.
+type Quota struct {
+	Limit     int64
+	Remaining int64
+	Reset     time.Time
+}
+
+// Store guarda as chaves e o consumo. As mudanças no cadastro são gravadas de imediato; o
+// consumo é gravado a cada intervalo por Run.
+type Store struct {
+	path          string
+	flushInterval time.Duration
+	configKeys    []Key
+
+	mu     sync.Mutex
+	keys   map[string]*Key
+	hashes map[string]string
+	dirty  bool
+
+	// saveMu ordena as gravações, feitas fora de mu
+	saveMu sync.Mutex
+}
+
+// Option personaliza o Store
+type Option func(*Store)
+
+// WithFlushInterval define o intervalo entre as gravações do consumo
+func WithFlushInterval(interval time.Duration) Option {
+	return func(s *Store) {
+		s.flushInterval = interval
+	}
+}
+
+// WithConfigKey cadastra uma chave da configuração, informada pelo hash SHA-256 em
+// hexadecimal. O ID é derivado do hash, para que o consumo e a revogação gravados continuem
+// valendo após um reinício; as cotas da configuração prevalecem sobre as gravadas.
+func WithConfigKey(name, hash string, dailyQuota, monthlyQuota int64) Option {
+	return func(s *Store) {
+		s.configKeys = append(s.configKeys, Key{
+			Name:         name,
+			Hash:         hash,
+			DailyQuota:   dailyQuota,
+			MonthlyQuota: monthlyQuota,
+		})
+	}
+}
+
+// NewStore cria o Store gravado em path, carregando as chaves e o consumo já gravados. Com
+// path vazio, nada é gravado.
+func NewStore(path string, opts ...Option) (*Store, error) {
+	s := &Store{
+		path:          path,
+		flushInterval: defaultFlushInterval,
+		keys:          map[string]*Key{},
+		hashes:        map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	configured := map[string]bool{}
+	for _, key := range s.configKeys {
+		configured[strings.ToLower(key.Hash)] = true
+	}
+	for id, key := range s.keys {
+		if key.Config && !configured[key.Hash] {
+			delete(s.keys, id)
+			delete(s.hashes, key.Hash)
+		}
+	}
+	for _, key := range s.configKeys {
+		if err := s.addConfigKey(key); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Create cadastra uma chave com as cotas e devolve o cadastro e a chave, que não é guardada
+func (s *Store) Create(name string, dailyQuota, monthlyQuota int64) (Key, string, error) {
+	if dailyQuota < 0 || monthlyQuota < 0 {
+		return Key{}, "", ErrInvalidQuota
+	}
+
+	secret := keyPrefix + randomHex(keyBytes)
+	key := &Key{
+		ID:           randomHex(8),
+		Name:         name,
+		Hash:         HashKey(secret),
+		Prefix:       secret[:visiblePrefix],
+		DailyQuota:   dailyQuota,
+		MonthlyQuota: monthlyQuota,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	s.keys[key.ID] = key
+	s.hashes[key.Hash] = key.ID
+	created := *key
+	s.mu.Unlock()
+
+	if err := s.Flush(); err != nil {
+		return Key{}, "", err
+	}
+	return created, secret, nil
+}
+
+// Revoke revoga a chave; o cadastro continua listado, com o horário da revogação
+func (s *Store) Revoke(id string) error {
+	s.mu.Lock()
+	key, ok := s.keys[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		revokedAt := time.Now().UTC()
+		key.RevokedAt = &revokedAt
+	}
+	s.mu.Unlock()
+
+	return s.Flush()
+}
+
+// List devolve as chaves cadastradas, da mais antiga à mais recente
+func (s *Store) List() []Key {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedKeys()
+}
+
+// Consume conta uma requisição da chave em now e devolve o ID da chave, que identifica o
+// cliente. Uma chave desconhecida ou revogada resulta em ErrInvalidKey; uma chave sem cota
+// restante resulta em ErrQuotaExceeded, sem contar a requisição. Em ambos os casos com chave
+// válida, a Quota descreve a janela mais restrita.
+func (s *Store) Consume(secret string, now time.Time) (string, Quota, error) {
+	hash := HashKey(secret)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.hashes[hash]
+	if !ok {
+		return "", Quota{}, ErrInvalidKey
+	}
+	key := s.keys[id]
+	if key.RevokedAt != nil {
+		return "", Quota{}, ErrInvalidKey
+	}
+
+	now = now.UTC()
+	key.Usage.roll(now)
+	if exceeded(key.DailyQuota, key.Usage.DayCount) || exceeded(key.MonthlyQuota, key.Usage.MonthCount) {
+		return key.ID, key.quota(now), ErrQuotaExceeded
+	}
+	key.Usage.DayCount++
+	key.Usage.MonthCount++
+	s.dirty = true
+	return key.ID, key.quota(now), nil
+}
+
+// Run grava o consumo a cada intervalo, até o contexto ser cancelado, com uma última
+// gravação no encerramento
+func (s *Store) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil {
+				log.Printf("Chaves de API: erro ao gravar o consumo: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if !s.pending() {
+				continue
+			}
+			if err := s.Flush(); err != nil {
+				log.Printf("Chaves de API: erro ao gravar o consumo: %v", err)
+			}
+		}
+	}
+}
+
+// Flush grava as chaves e o consumo em um arquivo temporário e o renomeia sobre o arquivo
+// do Store, para que uma gravação interrompida não corrompa o anterior
+func (s *Store) Flush() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	data, err := json.MarshalIndent(storeFile{Keys: s.sortedKeys()}, "", "  ")
+	s.dirty = false
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode api keys: %w", err)
+	}
+
+	if err := s.write(data); err != nil {
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// pending indica se há consumo ainda não gravado
+func (s *Store) pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dirty
+}
+
+// HashKey devolve o hash SHA-256 da chave em hexadecimal, a forma em que ela é guardada
+func HashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// storeFile é o conteúdo do arquivo do Store
+type storeFile struct {
+	Keys []Key `json:"keys"`
+}
+
+// load carrega o arquivo do Store, se existir
+func (s *Store) load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read api keys: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("decode api keys %s: %w", s.path, err)
+	}
+	for i := range file.Keys {
+		key := file.Keys[i]
+		s.keys[key.ID] = &key
+		s.hashes[key.Hash] = key.ID
+	}
+	return nil
+}
+
+// addConfigKey cadastra a chave da configuração, preservando o consumo e a revogação gravados
+func (s *Store) addConfigKey(key Key) error {
+	if !validHash(key.Hash) {
+		return fmt.Errorf("api key %q: hash must be a hex-encoded SHA-256", key.Name)
+	}
+	if key.DailyQuota < 0 || key.MonthlyQuota < 0 {
+		return fmt.Errorf("api key %q: %w", key.Name, ErrInvalidQuota)
+	}
+
+	key.Hash = strings.ToLower(key.Hash)
+	key.ID = key.Hash[:16]
+	key.Config = true
+	if stored, ok := s.keys[key.ID]; ok {
+		key.Usage = stored.Usage
+		key.CreatedAt = stored.CreatedAt
+		key.RevokedAt = stored.RevokedAt
+	} else {
+		key.CreatedAt = time.Now().UTC()
+	}
+	s.keys[key.ID] = &key
+	s.hashes[key.Hash] = key.ID
+	return nil
+}
+
+// write grava os dados no arquivo do Store, via arquivo temporário no mesmo diretório
+func (s *Store) write(data []byte) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create api keys directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write api keys: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write api keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write api keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write api keys: %w", err)
+	}
+	return nil
+}
+
+// sortedKeys copia as chaves em ordem de cadastro; exige mu
+func (s *Store) sortedKeys() []Key {
+	keys := make([]Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// roll zera os contadores ao mudar o dia ou o mês
+func (u *Usage) roll(now time.Time) {
+	if day := now.Format(dayLayout); u.Day != day {
+		u.Day = day
+		u.DayCount = 0
+	}
+	if month := now.Format(monthLayout); u.Month != month {
+		u.Month = month
+		u.MonthCount = 0
+	}
+}
+
+// quota descreve a janela com menos requisições restantes entre as limitadas
+func (k *Key) quota(now time.Time) Quota {
+	var quota Quota
+	windows := []struct {
+		limit int64
+		count int64
+		reset time.Time
+	}{
+		{k.DailyQuota, k.Usage.DayCount, time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)},
+		{k.MonthlyQuota, k.Usage.MonthCount, time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, window := range windows {
+		if window.limit == 0 {
+			continue
+		}
+		remaining := max(window.limit-window.count, 0)
+		if quota.Limit == 0 || remaining < quota.Remaining {
+			quota = Quota{Limit: window.limit, Remaining: remaining, Reset: window.reset}
+		}
+	}
+	return quota
+}
+
+func exceeded(limit, count int64) bool {
+	return limit > 0 && count >= limit
+}
+
+func validHash(hash string) bool {
+	decoded, err := hex.DecodeString(hash)
+	return err == nil && len(decoded) == sha256.Size
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,190 @@
+package apikeys
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var noon = time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func TestHashKey(t *testing.T) {
+	// echo -n "chave" | sha256sum
+	assert.Equal(t, "bb5d3680c0d90478ba469ff4a12b09b59df7525cd54b2e1235ddcd85bf2a5047", HashKey("chave"))
+	assert.NotEqual(t, HashKey("chave"), HashKey("Chave"))
+}
+
+func TestStore_Create(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := NewStore(path)
+	require.NoError(t, err)
+
+	key, secret, err := store.Create("parceiro", 100, 1000)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, keyPrefix))
+	assert.True(t, strings.HasPrefix(secret, key.Prefix))
+	assert.Equal(t, HashKey(secret), key.Hash)
+	assert.Equal(t, []Key{key}, store.List())
+
+	// Só o hash é gravado
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), secret)
+	assert.Contains(t, string(data), key.Hash)
+
+	_, _, err = store.Create("parceiro", -1, 0)
+	assert.ErrorIs(t, err, ErrInvalidQuota)
+}
+
+func TestStore_Consume(t *testing.T) {
+	store, err := NewStore("")
+	require.NoError(t, err)
+	key, secret, err := store.Create("parceiro", 2, 10)
+	require.NoError(t, err)
+
+	id, quota, err := store.Consume(secret, noon)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, id)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 1, Reset: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)}, quota)
+
+	_, quota, err = store.Consume(secret, noon)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), quota.Remaining)
+
+	// Além da cota diária, a requisição é recusada e não é contada
+	_, quota, err = store.Consume(secret, noon)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, int64(0), quota.Remaining)
+	assert.Equal(t, int64(2), store.List()[0].Usage.DayCount)
+
+	// No dia seguinte, a cota diária recomeça e a mensal continua
+	_, quota, err = store.Consume(secret, noon.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 1, Reset: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)}, quota)
+	assert.Equal(t, Usage{Day: "2025-01-11", DayCount: 1, Month: "2025-01", MonthCount: 3}, store.List()[0].Usage)
+}
+
+func TestStore_Consume_MonthlyQuota(t *testing.T) {
+	store, err := NewStore("")
+	require.NoError(t, err)
+	_, secret, err := store.Create("parceiro", 10, 2)
+	require.NoError(t, err)
+
+	// A janela mensal é a mais restrita
+	_, quota, err := store.Consume(secret, noon)
+	require.NoError(t, err)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 1, Reset: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}, quota)
+
+	_, _, err = store.Consume(secret, noon.Add(24*time.Hour))
+	require.NoError(t, err)
+	_, _, err = store.Consume(secret, noon.Add(48*time.Hour))
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	_, _, err = store.Consume(secret, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+}
+
+func TestStore_Consume_Unlimited(t *testing.T) {
+	store, err := NewStore("")
+	require.NoError(t, err)
+	_, secret, err := store.Create("interno", 0, 0)
+	require.NoError(t, err)
+
+	for range 5 {
+		_, quota, err := store.Consume(secret, noon)
+		require.NoError(t, err)
+		assert.Equal(t, Quota{}, quota)
+	}
+}
+
+func TestStore_InvalidKey(t *testing.T) {
+	store, err := NewStore("")
+	require.NoError(t, err)
+	key, secret, err := store.Create("parceiro", 10, 100)
+	require.NoError(t, err)
+
+	_, _, err = store.Consume("ct_desconhecida", noon)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	require.NoError(t, store.Revoke(key.ID))
+	_, _, err = store.Consume(secret, noon)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	assert.NotNil(t, store.List()[0].RevokedAt)
+
+	assert.ErrorIs(t, store.Revoke("inexistente"), ErrKeyNotFound)
+}
+
+func TestStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := NewStore(path, WithFlushInterval(time.Millisecond))
+	require.NoError(t, err)
+	key, secret, err := store.Create("parceiro", 10, 100)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.Run(ctx)
+		close(done)
+	}()
+	_, _, err = store.Consume(secret, noon)
+	require.NoError(t, err)
+	_, _, err = store.Consume(secret, noon)
+	require.NoError(t, err)
+	cancel()
+	<-done
+
+	// O consumo gravado continua valendo após o reinício
+	reopened, err := NewStore(path)
+	require.NoError(t, err)
+	require.Len(t, reopened.List(), 1)
+	assert.Equal(t, key.ID, reopened.List()[0].ID)
+	assert.Equal(t, int64(2), reopened.List()[0].Usage.DayCount)
+
+	_, quota, err := reopened.Consume(secret, noon)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), quota.Remaining)
+}
+
+func TestStore_ConfigKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	hash := HashKey("chave-da-configuracao")
+
+	store, err := NewStore(path, WithConfigKey("config", strings.ToUpper(hash), 1, 0))
+	require.NoError(t, err)
+	_, _, err = store.Consume("chave-da-configuracao", noon)
+	require.NoError(t, err)
+	require.NoError(t, store.Flush())
+
+	// As cotas da configuração prevalecem, e o consumo gravado é mantido
+	reopened, err := NewStore(path, WithConfigKey("config", hash, 5, 0))
+	require.NoError(t, err)
+	_, quota, err := reopened.Consume("chave-da-configuracao", noon)
+	require.NoError(t, err)
+	assert.Equal(t, Quota{Limit: 5, Remaining: 3, Reset: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)}, quota)
+	require.NoError(t, reopened.Flush())
+
+	// Retirada da configuração, a chave deixa de valer
+	withoutKey, err := NewStore(path)
+	require.NoError(t, err)
+	assert.Empty(t, withoutKey.List())
+	_, _, err = withoutKey.Consume("chave-da-configuracao", noon)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewStore("", WithConfigKey("config", "abc", 1, 0))
+	assert.Error(t, err)
+}
+
+func TestStore_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := NewStore(path)
+	assert.Error(t, err)
+}
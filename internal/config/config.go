@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -10,23 +11,189 @@ import (
 
 // Config holds all configuration for our application
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Weather  WeatherConfig  `mapstructure:"weather"`
-	Database DatabaseConfig `mapstructure:"database"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Weather     WeatherConfig     `mapstructure:"weather"`
+	CEP         CEPConfig         `mapstructure:"cep"`
+	Temperature TemperatureConfig `mapstructure:"temperature"`
+	Batch       BatchConfig       `mapstructure:"batch"`
+	GRPC        GRPCConfig        `mapstructure:"grpc"`
+	OpenAPI     OpenAPIConfig     `mapstructure:"openapi"`
+	Stream      StreamConfig      `mapstructure:"stream"`
+	WebSocket   WebSocketConfig   `mapstructure:"websocket"`
+	Alerts      AlertsConfig      `mapstructure:"alerts"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	APIKeys     APIKeysConfig     `mapstructure:"api_keys"`
+	Database    DatabaseConfig    `mapstructure:"database"`
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
-	Host string `mapstructure:"host"`
+	Port           string        `mapstructure:"port"`
+	Host           string        `mapstructure:"host"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	CEPBudgetShare float64       `mapstructure:"cep_budget_share"`
 }
 
 // WeatherConfig holds weather API configuration
 type WeatherConfig struct {
-	APIKey  string `mapstructure:"api_key"`
-	BaseURL string `mapstructure:"base_url"`
+	Provider              string `mapstructure:"provider"`
+	APIKey                string `mapstructure:"api_key"`
+	BaseURL               string `mapstructure:"base_url"`
+	OpenMeteoURL          string `mapstructure:"open_meteo_url"`
+	OpenMeteoGeocodingURL string `mapstructure:"open_meteo_geocoding_url"`
+	OpenMeteoArchiveURL   string `mapstructure:"open_meteo_archive_url"`
+	OpenWeatherMapURL     string `mapstructure:"openweathermap_url"`
+	OpenWeatherMapAPIKey  string `mapstructure:"openweathermap_api_key"`
+	INMETURL              string `mapstructure:"inmet_url"`
+	CentroidsPath         string `mapstructure:"centroids_path"`
+	MismatchPolicy        string `mapstructure:"mismatch_policy"`
+}
+
+// Supported weather providers
+const (
+	WeatherProviderWeatherAPI     = "weatherapi"
+	WeatherProviderOpenMeteo      = "openmeteo"
+	WeatherProviderOpenWeatherMap = "openweathermap"
+	WeatherProviderINMET          = "inmet"
+)
+
+// Policies applied when the weather location does not match the CEP location
+const (
+	MismatchPolicyIgnore = "ignore"
+	MismatchPolicyFlag   = "flag"
+	MismatchPolicyRetry  = "retry"
+	MismatchPolicyReject = "reject"
+)
+
+// TemperatureConfig holds how converted temperatures are rounded
+type TemperatureConfig struct {
+	Rounding     string `mapstructure:"rounding"`
+	Decimals     int    `mapstructure:"decimals"`
+	LegacyKelvin bool   `mapstructure:"legacy_kelvin"`
+}
+
+// Supported rounding modes for converted temperatures
+const (
+	RoundingNone     = "none"
+	RoundingHalfEven = "half_even"
+	RoundingHalfUp   = "half_up"
+	RoundingTruncate = "truncate"
+)
+
+// BatchConfig holds the limits of batch temperature lookups
+type BatchConfig struct {
+	MaxCEPs int `mapstructure:"max_ceps"`
+	Workers int `mapstructure:"workers"`
+}
+
+// GRPCConfig holds the gRPC server configuration. An empty port disables the server.
+type GRPCConfig struct {
+	Port string `mapstructure:"port"`
+}
+
+// OpenAPIConfig holds the validation of requests against the OpenAPI document
+type OpenAPIConfig struct {
+	ValidateRequests bool `mapstructure:"validate_requests"`
+}
+
+// StreamConfig holds the live temperature streams configuration. Streams of the same
+// municipality share one weather poller, refreshed every Interval.
+type StreamConfig struct {
+	Interval  time.Duration `mapstructure:"interval"`
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+}
+
+// WebSocketConfig holds the per-connection limits of the WebSocket hub. Subscriptions
+// share the stream weather pollers.
+type WebSocketConfig struct {
+	MaxSubscriptions int `mapstructure:"max_subscriptions"`
+}
+
+// CacheConfig holds the Cache-Control max-age of each cacheable route (zero disables the
+// caching headers of the route) and how many responses keep their validators for 304s.
+type CacheConfig struct {
+	Temperature time.Duration `mapstructure:"temperature"`
+	Forecast    time.Duration `mapstructure:"forecast"`
+	History     time.Duration `mapstructure:"history"`
+	Convert     time.Duration `mapstructure:"convert"`
+	MaxEntries  int           `mapstructure:"max_entries"`
+}
+
+// APIKeysConfig holds the client API keys configuration. When enabled, the lookup routes
+// require a key in X-API-Key. Keys are created through the admin API or listed in Keys by
+// their SHA-256 hash; usage is flushed to StorePath every FlushInterval. Zero quotas are
+// unlimited.
+type APIKeysConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	StorePath     string        `mapstructure:"store_path"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	AdminToken    string        `mapstructure:"admin_token"`
+	DailyQuota    int64         `mapstructure:"daily_quota"`
+	MonthlyQuota  int64         `mapstructure:"monthly_quota"`
+	Keys          []APIKey      `mapstructure:"keys"`
+}
+
+// APIKey is a client API key declared in the configuration
+type APIKey struct {
+	Name         string `mapstructure:"name"`
+	Hash         string `mapstructure:"hash"`
+	DailyQuota   int64  `mapstructure:"daily_quota"`
+	MonthlyQuota int64  `mapstructure:"monthly_quota"`
+}
+
+// AlertsConfig holds the threshold alerts configuration: how often rules are evaluated,
+// how failed webhooks are retried before landing in the dead-letter list, and whether
+// webhooks may target loopback and private network addresses.
+type AlertsConfig struct {
+	Interval             time.Duration `mapstructure:"interval"`
+	MaxRules             int           `mapstructure:"max_rules"`
+	MaxAttempts          int           `mapstructure:"max_attempts"`
+	Backoff              time.Duration `mapstructure:"backoff"`
+	MaxBackoff           time.Duration `mapstructure:"max_backoff"`
+	DeadLetters          int           `mapstructure:"dead_letters"`
+	AllowPrivateNetworks bool          `mapstructure:"allow_private_networks"`
+}
+
+// CEPConfig holds CEP providers configuration
+type CEPConfig struct {
+	Providers     []string      `mapstructure:"providers"`
+	Mode          string        `mapstructure:"mode"`
+	HedgeDelay    time.Duration `mapstructure:"hedge_delay"`
+	ViaCEPURL     string        `mapstructure:"viacep_url"`
+	BrasilAPIURL  string        `mapstructure:"brasilapi_url"`
+	OpenCEPURL    string        `mapstructure:"opencep_url"`
+	AwesomeAPIURL string        `mapstructure:"awesomeapi_url"`
+	Dataset       CEPDataset    `mapstructure:"dataset"`
 }
 
+// CEPDataset holds the offline CEP dataset configuration
+type CEPDataset struct {
+	Path     string `mapstructure:"path"`
+	Format   string `mapstructure:"format"`
+	Fallback bool   `mapstructure:"fallback"`
+}
+
+// Supported CEP providers
+const (
+	CEPProviderViaCEP     = "viacep"
+	CEPProviderBrasilAPI  = "brasilapi"
+	CEPProviderOpenCEP    = "opencep"
+	CEPProviderAwesomeAPI = "awesomeapi"
+	CEPProviderOffline    = "offline"
+)
+
+// Supported offline CEP dataset formats
+const (
+	CEPDatasetCSV    = "csv"
+	CEPDatasetNDJSON = "ndjson"
+)
+
+// CEP lookup modes
+const (
+	CEPModeSequential = "sequential"
+	CEPModeRace       = "race"
+)
+
 // DatabaseConfig holds database configuration (for future use)
 type DatabaseConfig struct {
 	Host     string `mapstructure:"host"`
@@ -78,8 +245,62 @@ func LoadConfig() (*Config, error) {
 func setDefaults() {
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.host", "0.0.0.0")
+	viper.SetDefault("server.request_timeout", "10s")
+	viper.SetDefault("server.cep_budget_share", 0.4)
+	viper.SetDefault("weather.provider", WeatherProviderWeatherAPI)
 	viper.SetDefault("weather.base_url", "http://api.weatherapi.com/v1")
 	viper.SetDefault("weather.api_key", "")
+	viper.SetDefault("weather.open_meteo_url", "https://api.open-meteo.com/v1")
+	viper.SetDefault("weather.open_meteo_geocoding_url", "https://geocoding-api.open-meteo.com/v1")
+	viper.SetDefault("weather.open_meteo_archive_url", "https://archive-api.open-meteo.com/v1")
+	viper.SetDefault("weather.openweathermap_url", "https://api.openweathermap.org/data/2.5")
+	viper.SetDefault("weather.openweathermap_api_key", "")
+	viper.SetDefault("weather.inmet_url", "https://apitempo.inmet.gov.br")
+	viper.SetDefault("weather.centroids_path", "")
+	viper.SetDefault("weather.mismatch_policy", MismatchPolicyFlag)
+	viper.SetDefault("cep.providers", []string{
+		CEPProviderViaCEP,
+		CEPProviderBrasilAPI,
+		CEPProviderOpenCEP,
+		CEPProviderAwesomeAPI,
+	})
+	viper.SetDefault("cep.mode", CEPModeSequential)
+	viper.SetDefault("cep.hedge_delay", "300ms")
+	viper.SetDefault("cep.viacep_url", "https://viacep.com.br/ws")
+	viper.SetDefault("cep.brasilapi_url", "https://brasilapi.com.br/api/cep/v1")
+	viper.SetDefault("cep.opencep_url", "https://opencep.com/v1")
+	viper.SetDefault("cep.awesomeapi_url", "https://cep.awesomeapi.com.br/json")
+	viper.SetDefault("cep.dataset.path", "")
+	viper.SetDefault("cep.dataset.format", "")
+	viper.SetDefault("cep.dataset.fallback", false)
+	viper.SetDefault("temperature.rounding", RoundingHalfEven)
+	viper.SetDefault("temperature.decimals", 2)
+	viper.SetDefault("temperature.legacy_kelvin", false)
+	viper.SetDefault("batch.max_ceps", 100)
+	viper.SetDefault("batch.workers", 8)
+	viper.SetDefault("grpc.port", "9090")
+	viper.SetDefault("openapi.validate_requests", false)
+	viper.SetDefault("stream.interval", "30s")
+	viper.SetDefault("stream.heartbeat", "15s")
+	viper.SetDefault("websocket.max_subscriptions", 50)
+	viper.SetDefault("alerts.interval", "60s")
+	viper.SetDefault("alerts.max_rules", 1000)
+	viper.SetDefault("alerts.max_attempts", 5)
+	viper.SetDefault("alerts.backoff", "1s")
+	viper.SetDefault("alerts.max_backoff", "5m")
+	viper.SetDefault("alerts.dead_letters", 100)
+	viper.SetDefault("alerts.allow_private_networks", false)
+	viper.SetDefault("cache.temperature", "60s")
+	viper.SetDefault("cache.forecast", "10m")
+	viper.SetDefault("cache.history", "1h")
+	viper.SetDefault("cache.convert", "24h")
+	viper.SetDefault("cache.max_entries", 10000)
+	viper.SetDefault("api_keys.enabled", false)
+	viper.SetDefault("api_keys.store_path", "data/api_keys.json")
+	viper.SetDefault("api_keys.flush_interval", "10s")
+	viper.SetDefault("api_keys.admin_token", "")
+	viper.SetDefault("api_keys.daily_quota", 1000)
+	viper.SetDefault("api_keys.monthly_quota", 20000)
 }
 
 // bindEnvVars binds environment variables to configuration keys
@@ -87,10 +308,79 @@ func bindEnvVars() {
 	// Server configuration
 	viper.BindEnv("server.port", "PORT")
 	viper.BindEnv("server.host", "HOST")
+	viper.BindEnv("server.request_timeout", "REQUEST_TIMEOUT")
+	viper.BindEnv("server.cep_budget_share", "CEP_BUDGET_SHARE")
 
 	// Weather API configuration
+	viper.BindEnv("weather.provider", "WEATHER_PROVIDER")
 	viper.BindEnv("weather.api_key", "WEATHER_API_KEY")
 	viper.BindEnv("weather.base_url", "WEATHER_BASE_URL")
+	viper.BindEnv("weather.open_meteo_url", "OPEN_METEO_URL")
+	viper.BindEnv("weather.open_meteo_geocoding_url", "OPEN_METEO_GEOCODING_URL")
+	viper.BindEnv("weather.open_meteo_archive_url", "OPEN_METEO_ARCHIVE_URL")
+	viper.BindEnv("weather.openweathermap_url", "OPENWEATHERMAP_URL")
+	viper.BindEnv("weather.openweathermap_api_key", "OPENWEATHERMAP_API_KEY")
+	viper.BindEnv("weather.inmet_url", "INMET_URL")
+	viper.BindEnv("weather.centroids_path", "WEATHER_CENTROIDS_PATH")
+	viper.BindEnv("weather.mismatch_policy", "WEATHER_MISMATCH_POLICY")
+
+	// CEP providers configuration
+	viper.BindEnv("cep.providers", "CEP_PROVIDERS")
+	viper.BindEnv("cep.mode", "CEP_MODE")
+	viper.BindEnv("cep.hedge_delay", "CEP_HEDGE_DELAY")
+	viper.BindEnv("cep.viacep_url", "VIACEP_URL")
+	viper.BindEnv("cep.brasilapi_url", "BRASILAPI_URL")
+	viper.BindEnv("cep.opencep_url", "OPENCEP_URL")
+	viper.BindEnv("cep.awesomeapi_url", "AWESOMEAPI_URL")
+	viper.BindEnv("cep.dataset.path", "CEP_DATASET_PATH")
+	viper.BindEnv("cep.dataset.format", "CEP_DATASET_FORMAT")
+	viper.BindEnv("cep.dataset.fallback", "CEP_DATASET_FALLBACK")
+
+	// Temperature conversion configuration
+	viper.BindEnv("temperature.rounding", "TEMPERATURE_ROUNDING")
+	viper.BindEnv("temperature.decimals", "TEMPERATURE_DECIMALS")
+	viper.BindEnv("temperature.legacy_kelvin", "TEMPERATURE_LEGACY_KELVIN")
+
+	// Batch lookup configuration
+	viper.BindEnv("batch.max_ceps", "BATCH_MAX_CEPS")
+	viper.BindEnv("batch.workers", "BATCH_WORKERS")
+
+	// gRPC server configuration
+	viper.BindEnv("grpc.port", "GRPC_PORT")
+
+	// OpenAPI validation configuration
+	viper.BindEnv("openapi.validate_requests", "OPENAPI_VALIDATE_REQUESTS")
+
+	// Live temperature streams configuration
+	viper.BindEnv("stream.interval", "STREAM_INTERVAL")
+	viper.BindEnv("stream.heartbeat", "STREAM_HEARTBEAT")
+
+	// WebSocket hub configuration
+	viper.BindEnv("websocket.max_subscriptions", "WS_MAX_SUBSCRIPTIONS")
+
+	// Threshold alerts configuration
+	viper.BindEnv("alerts.interval", "ALERTS_INTERVAL")
+	viper.BindEnv("alerts.max_rules", "ALERTS_MAX_RULES")
+	viper.BindEnv("alerts.max_attempts", "ALERTS_MAX_ATTEMPTS")
+	viper.BindEnv("alerts.backoff", "ALERTS_BACKOFF")
+	viper.BindEnv("alerts.max_backoff", "ALERTS_MAX_BACKOFF")
+	viper.BindEnv("alerts.dead_letters", "ALERTS_DEAD_LETTERS")
+	viper.BindEnv("alerts.allow_private_networks", "ALERTS_ALLOW_PRIVATE_NETWORKS")
+
+	// HTTP caching configuration
+	viper.BindEnv("cache.temperature", "CACHE_MAX_AGE_TEMPERATURE")
+	viper.BindEnv("cache.forecast", "CACHE_MAX_AGE_FORECAST")
+	viper.BindEnv("cache.history", "CACHE_MAX_AGE_HISTORY")
+	viper.BindEnv("cache.convert", "CACHE_MAX_AGE_CONVERT")
+	viper.BindEnv("cache.max_entries", "CACHE_MAX_ENTRIES")
+
+	// Client API keys configuration
+	viper.BindEnv("api_keys.enabled", "API_KEYS_ENABLED")
+	viper.BindEnv("api_keys.store_path", "API_KEYS_STORE_PATH")
+	viper.BindEnv("api_keys.flush_interval", "API_KEYS_FLUSH_INTERVAL")
+	viper.BindEnv("api_keys.admin_token", "API_KEYS_ADMIN_TOKEN")
+	viper.BindEnv("api_keys.daily_quota", "API_KEYS_DAILY_QUOTA")
+	viper.BindEnv("api_keys.monthly_quota", "API_KEYS_MONTHLY_QUOTA")
 }
 
 // GetServerAddress returns the server address
@@ -98,15 +388,152 @@ func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.Port)
 }
 
+// GetGRPCAddress returns the gRPC server address
+func (c *Config) GetGRPCAddress() string {
+	return fmt.Sprintf("%s:%s", c.Server.Host, c.GRPC.Port)
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.Weather.APIKey == "" {
-		return fmt.Errorf("weather API key is required")
+	switch c.Weather.Provider {
+	case WeatherProviderWeatherAPI:
+		if c.Weather.APIKey == "" {
+			return fmt.Errorf("weather API key is required")
+		}
+	case WeatherProviderOpenWeatherMap:
+		if c.Weather.OpenWeatherMapAPIKey == "" {
+			return fmt.Errorf("OpenWeatherMap API key is required")
+		}
+	case WeatherProviderOpenMeteo, WeatherProviderINMET:
+	default:
+		return fmt.Errorf("unknown weather provider: %s", c.Weather.Provider)
 	}
 
 	if c.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
 
+	switch c.Weather.MismatchPolicy {
+	case MismatchPolicyIgnore, MismatchPolicyFlag, MismatchPolicyRetry, MismatchPolicyReject:
+	default:
+		return fmt.Errorf("unknown weather mismatch policy: %s", c.Weather.MismatchPolicy)
+	}
+
+	if c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server request timeout must not be negative")
+	}
+
+	if c.Server.CEPBudgetShare <= 0 || c.Server.CEPBudgetShare >= 1 {
+		return fmt.Errorf("CEP budget share must be between 0 and 1")
+	}
+
+	if len(c.CEP.Providers) == 0 {
+		return fmt.Errorf("at least one CEP provider is required")
+	}
+
+	for _, provider := range c.CEP.Providers {
+		switch provider {
+		case CEPProviderViaCEP, CEPProviderBrasilAPI, CEPProviderOpenCEP, CEPProviderAwesomeAPI, CEPProviderOffline:
+		default:
+			return fmt.Errorf("unknown CEP provider: %s", provider)
+		}
+	}
+
+	switch c.CEP.Mode {
+	case CEPModeSequential, CEPModeRace:
+	default:
+		return fmt.Errorf("unknown CEP mode: %s", c.CEP.Mode)
+	}
+
+	if c.CEP.Mode == CEPModeRace && c.CEP.HedgeDelay < 0 {
+		return fmt.Errorf("CEP hedge delay must not be negative")
+	}
+
+	switch c.CEP.Dataset.Format {
+	case "", CEPDatasetCSV, CEPDatasetNDJSON:
+	default:
+		return fmt.Errorf("unknown CEP dataset format: %s", c.CEP.Dataset.Format)
+	}
+
+	switch c.Temperature.Rounding {
+	case RoundingNone, RoundingHalfEven, RoundingHalfUp, RoundingTruncate:
+	default:
+		return fmt.Errorf("unknown temperature rounding: %s", c.Temperature.Rounding)
+	}
+
+	if c.Temperature.Decimals < 0 || c.Temperature.Decimals > 10 {
+		return fmt.Errorf("temperature decimals must be between 0 and 10")
+	}
+
+	if c.Batch.MaxCEPs < 1 {
+		return fmt.Errorf("batch max CEPs must be at least 1")
+	}
+
+	if c.Batch.Workers < 1 {
+		return fmt.Errorf("batch workers must be at least 1")
+	}
+
+	if c.GRPC.Port != "" && c.GRPC.Port == c.Server.Port {
+		return fmt.Errorf("gRPC port must differ from server port")
+	}
+
+	if c.Stream.Interval < time.Second {
+		return fmt.Errorf("stream interval must be at least 1s")
+	}
+
+	if c.Stream.Heartbeat <= 0 {
+		return fmt.Errorf("stream heartbeat must be positive")
+	}
+
+	if c.WebSocket.MaxSubscriptions <= 0 {
+		return fmt.Errorf("WebSocket max subscriptions must be positive")
+	}
+
+	if c.Alerts.Interval < time.Second {
+		return fmt.Errorf("alerts interval must be at least 1s")
+	}
+
+	if c.Alerts.MaxRules <= 0 {
+		return fmt.Errorf("alerts max rules must be positive")
+	}
+
+	if c.Alerts.MaxAttempts <= 0 {
+		return fmt.Errorf("alerts max attempts must be positive")
+	}
+
+	if c.Alerts.Backoff <= 0 || c.Alerts.MaxBackoff < c.Alerts.Backoff {
+		return fmt.Errorf("alerts backoff must be positive and not above the max backoff")
+	}
+
+	if c.Alerts.DeadLetters <= 0 {
+		return fmt.Errorf("alerts dead letters must be positive")
+	}
+
+	if c.Cache.Temperature < 0 || c.Cache.Forecast < 0 || c.Cache.History < 0 || c.Cache.Convert < 0 {
+		return fmt.Errorf("cache max-age cannot be negative")
+	}
+
+	if c.Cache.MaxEntries <= 0 {
+		return fmt.Errorf("cache max entries must be positive")
+	}
+
+	if c.APIKeys.FlushInterval < time.Second {
+		return fmt.Errorf("API keys flush interval must be at least 1s")
+	}
+
+	if c.APIKeys.DailyQuota < 0 || c.APIKeys.MonthlyQuota < 0 {
+		return fmt.Errorf("API key quotas cannot be negative")
+	}
+
+	for _, key := range c.APIKeys.Keys {
+		if key.DailyQuota < 0 || key.MonthlyQuota < 0 {
+			return fmt.Errorf("API key %q quotas cannot be negative", key.Name)
+		}
+	}
+
+	if c.APIKeys.Enabled && c.APIKeys.AdminToken == "" && len(c.APIKeys.Keys) == 0 {
+		return fmt.Errorf("API keys require an admin token or configured keys")
+	}
+
 	return nil
 }
@@ -0,0 +1,41 @@
+package graphql
+
+import (
+	"errors"
+
+	"cep-temperatura/internal/services"
+)
+
+// errTooManyCEPs recusa as consultas com CEPs além do limite
+var errTooManyCEPs = errors.New("too many ceps")
+
+// queryError expõe o erro com a mesma mensagem da API HTTP e um código em extensions.code
+type queryError struct {
+	message string
+	code    string
+	err     error
+}
+
+func (e *queryError) Error() string { return e.message }
+
+func (e *queryError) Unwrap() error { return e.err }
+
+// Extensions implementa a interface de erros com extensões do graphql-go
+func (e *queryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// resolverError mapeia os erros dos serviços para a mensagem e o código da resposta GraphQL
+func resolverError(err error) error {
+	code, message := errorCode(err)
+	return &queryError{message: message, code: code, err: err}
+}
+
+// errorCode devolve a classe e a mensagem do erro pelo catálogo dos serviços, como no gRPC
+func errorCode(err error) (string, string) {
+	if errors.Is(err, errTooManyCEPs) {
+		return services.ClassInvalidArgument, "too many ceps"
+	}
+	info := services.DescribeError(err)
+	return info.Class(), info.Message
+}
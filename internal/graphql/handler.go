@@ -0,0 +1,92 @@
+// Package graphql expõe CEPs, municípios e clima via GraphQL, com os mesmos serviços da API HTTP.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"time"
+
+	"cep-temperatura/internal/services"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// Limites padrão das consultas
+const (
+	defaultMaxCEPs = 100
+	defaultWorkers = 8
+	maxQueryDepth  = 6
+)
+
+// Handler responde às queries GraphQL em POST /graphql
+type Handler struct {
+	cepService         services.CEPService
+	weatherService     services.WeatherService
+	temperatureService services.TemperatureService
+
+	requestTimeout time.Duration
+	maxCEPs        int
+	workers        int
+
+	relay *relay.Handler
+}
+
+// Option personaliza o Handler
+type Option func(*Handler)
+
+// WithRequestTimeout limita cada consulta de CEP e clima
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		h.requestTimeout = timeout
+	}
+}
+
+// WithBatchLimits define quantos CEPs distintos uma requisição consulta, somando todos os
+// campos cep e ceps, e quantas consultas correm em paralelo
+func WithBatchLimits(maxCEPs, workers int) Option {
+	return func(h *Handler) {
+		h.maxCEPs = maxCEPs
+		h.workers = workers
+	}
+}
+
+// NewHandler cria o handler GraphQL. O esquema é validado na criação.
+func NewHandler(
+	cepService services.CEPService,
+	weatherService services.WeatherService,
+	temperatureService services.TemperatureService,
+	opts ...Option,
+) *Handler {
+	h := &Handler{
+		cepService:         cepService,
+		weatherService:     weatherService,
+		temperatureService: temperatureService,
+		maxCEPs:            defaultMaxCEPs,
+		workers:            defaultWorkers,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	parsed := gql.MustParseSchema(schema, &queryResolver{h: h}, gql.MaxDepth(maxQueryDepth))
+	h.relay = &relay.Handler{Schema: parsed}
+	return h
+}
+
+// ServeHTTP executa a query com loaders novos, para que o cache de consultas dure só a requisição
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.relay.ServeHTTP(w, r.WithContext(withLoaders(r.Context(), h.newLoaders())))
+}
+
+// requestContext aplica o prazo de cada consulta sobre o contexto da requisição
+func (h *Handler) requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if h.requestTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, h.requestTimeout)
+}
@@ -0,0 +1,238 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCEPService é um mock do CEPService
+type MockCEPService struct {
+	mock.Mock
+}
+
+func (m *MockCEPService) ValidateCEP(cep string) bool {
+	args := m.Called(cep)
+	return args.Bool(0)
+}
+
+func (m *MockCEPService) GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CEPResponse), args.Error(1)
+}
+
+// MockWeatherService é um mock do WeatherService
+type MockWeatherService struct {
+	mock.Mock
+}
+
+func (m *MockWeatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResult), args.Error(1)
+}
+
+func (m *MockWeatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options services.ForecastOptions) (*models.ForecastResult, error) {
+	return nil, services.ErrForecastUnsupported
+}
+
+func (m *MockWeatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options services.HistoryOptions) (*models.HistoryResult, error) {
+	return nil, services.ErrHistoryUnsupported
+}
+
+var (
+	paulista = &models.CEPResponse{CEP: "01310-100", Logradouro: "Avenida Paulista", Complemento: "de 612 a 1510 - lado par",
+		Bairro: "Bela Vista", Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	se = &models.CEPResponse{CEP: "01001-000", Logradouro: "Praça da Sé", Complemento: "lado ímpar",
+		Bairro: "Sé", Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+)
+
+func newTestHandler(opts ...Option) (*Handler, *MockCEPService, *MockWeatherService) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+
+	cepService.On("ValidateCEP", mock.MatchedBy(func(cep string) bool { return cep != "123" })).Return(true)
+	cepService.On("ValidateCEP", "123").Return(false)
+	cepService.On("GetLocation", mock.Anything, "01310100").Return(paulista, nil)
+	cepService.On("GetLocation", mock.Anything, "01001000").Return(se, nil)
+	cepService.On("GetLocation", mock.Anything, "99999999").Return(nil, fmt.Errorf("viacep: %w", services.ErrCEPNotFound))
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(paulista)).Return(&models.WeatherResult{
+		TempC: 25,
+		Conditions: models.Conditions{
+			Text:        "Partly cloudy",
+			Humidity:    floatPtr(65),
+			FeelsLikeC:  floatPtr(30),
+			LastUpdated: "2025-01-10 15:00",
+		},
+		Location: models.WeatherLocation{Name: "São Paulo", Region: "Sao Paulo", Country: "Brazil"},
+		Match:    models.LocationMatch{Status: models.LocationMatched},
+	}, nil)
+
+	return NewHandler(cepService, weatherService, services.NewTemperatureService(), opts...), cepService, weatherService
+}
+
+func floatPtr(value float64) *float64 { return &value }
+
+func performQuery(handler http.Handler, query string) map[string]any {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var response map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return response
+}
+
+func TestHandler_Cep(t *testing.T) {
+	handler, _, _ := newTestHandler()
+
+	response := performQuery(handler, `{
+		cep(code: "01310-100") {
+			code street neighborhood
+			municipality { name state ibge }
+			weather {
+				celsius: temperature
+				fahrenheit: temperature(unit: "f")
+				temperatures(units: ["K", "Re"]) { unit value }
+				feelsLike(unit: "K")
+				humidity condition lastUpdated
+				location { name match }
+			}
+		}
+	}`)
+
+	assert.Nil(t, response["errors"])
+	expected := `{"cep": {
+		"code": "01310-100", "street": "Avenida Paulista", "neighborhood": "Bela Vista",
+		"municipality": {"name": "São Paulo", "state": "SP", "ibge": "3550308"},
+		"weather": {
+			"celsius": 25, "fahrenheit": 77,
+			"temperatures": [{"unit": "K", "value": 298.15}, {"unit": "Re", "value": 20}],
+			"feelsLike": 303.15,
+			"humidity": 65, "condition": "Partly cloudy", "lastUpdated": "2025-01-10 15:00",
+			"location": {"name": "São Paulo", "match": "matched"}
+		}
+	}}`
+	data, _ := json.Marshal(response["data"])
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestHandler_Cep_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+		code    string
+	}{
+		{"CEP inválido", `{ cep(code: "123") { street } }`, "invalid zipcode", "INVALID_ARGUMENT"},
+		{"CEP não encontrado", `{ cep(code: "99999999") { street } }`, "can not find zipcode", "NOT_FOUND"},
+		{"escala desconhecida", `{ cep(code: "01310100") { weather { temperature(unit: "X") } } }`, "invalid unit", "INVALID_ARGUMENT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, _ := newTestHandler()
+			response := performQuery(handler, tt.query)
+
+			errors := response["errors"].([]any)
+			assert.Len(t, errors, 1)
+			first := errors[0].(map[string]any)
+			assert.Equal(t, tt.message, first["message"])
+			assert.Equal(t, tt.code, first["extensions"].(map[string]any)["code"])
+		})
+	}
+}
+
+func TestHandler_Ceps_Dataloader(t *testing.T) {
+	handler, cepService, weatherService := newTestHandler()
+
+	response := performQuery(handler, `{
+		ceps(codes: ["01310100", "99999999", "01001000", "01310-100"]) {
+			street
+			weather { temperature }
+		}
+		again: cep(code: "01310100") { municipality { name } }
+	}`)
+
+	data, _ := json.Marshal(response["data"])
+	assert.JSONEq(t, `{
+		"ceps": [
+			{"street": "Avenida Paulista", "weather": {"temperature": 25}},
+			null,
+			{"street": "Praça da Sé", "weather": {"temperature": 25}},
+			{"street": "Avenida Paulista", "weather": {"temperature": 25}}
+		],
+		"again": {"municipality": {"name": "São Paulo"}}
+	}`, string(data))
+
+	// O CEP não encontrado anula o item, com um erro por campo pedido
+	errors := response["errors"].([]any)
+	assert.Len(t, errors, 2)
+	for _, err := range errors {
+		assert.Equal(t, "NOT_FOUND", err.(map[string]any)["extensions"].(map[string]any)["code"])
+		assert.Equal(t, []any{"ceps", float64(1)}, err.(map[string]any)["path"].([]any)[:2])
+	}
+
+	// Cada CEP e cada município é consultado uma única vez na requisição
+	cepService.AssertNumberOfCalls(t, "GetLocation", 3)
+	weatherService.AssertNumberOfCalls(t, "GetTemperature", 1)
+}
+
+func TestHandler_Ceps_TooMany(t *testing.T) {
+	handler := NewHandler(new(MockCEPService), new(MockWeatherService), services.NewTemperatureService(), WithBatchLimits(2, 1))
+
+	response := performQuery(handler, `{ ceps(codes: ["01310100", "01001000", "20040020"]) { street } }`)
+
+	errors := response["errors"].([]any)
+	assert.Equal(t, "too many ceps", errors[0].(map[string]any)["message"])
+}
+
+func TestHandler_Ceps_TooManyWithAliases(t *testing.T) {
+	handler, cepService, _ := newTestHandler(WithBatchLimits(2, 1))
+
+	// Cada campo fica dentro do limite, mas a operação passa dele. Os campos correm em paralelo,
+	// então qualquer um deles pode ser o recusado.
+	response := performQuery(handler, `{
+		a: ceps(codes: ["01310100", "01310-100"]) { street }
+		b: cep(code: "01001000") { street }
+		c: ceps(codes: ["01001-000", "99999999"]) { street }
+	}`)
+
+	tooMany := 0
+	for _, err := range response["errors"].([]any) {
+		if err.(map[string]any)["message"] == "too many ceps" {
+			tooMany++
+		}
+	}
+	assert.GreaterOrEqual(t, tooMany, 1)
+	// Sem o limite por operação, os três CEPs distintos seriam consultados
+	assert.LessOrEqual(t, countCalls(cepService, "GetLocation"), 2)
+}
+
+// countCalls conta as chamadas do método no mock
+func countCalls(m *MockCEPService, method string) int {
+	count := 0
+	for _, call := range m.Calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
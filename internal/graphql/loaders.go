@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type loadersKey struct{}
+
+// loaders agrupa, por requisição, as consultas de CEP e de clima. Cada CEP e cada município é
+// consultado uma única vez por requisição, mesmo que apareça em vários pontos da query.
+type loaders struct {
+	locations *dataloader.Loader[string, *models.CEPResponse]
+	weather   *dataloader.Loader[models.WeatherQuery, *models.WeatherResult]
+
+	mu      sync.Mutex
+	maxCEPs int
+	ceps    map[string]struct{}
+}
+
+func (h *Handler) newLoaders() *loaders {
+	return &loaders{
+		locations: dataloader.NewBatchedLoader(h.loadLocations),
+		weather:   dataloader.NewBatchedLoader(h.loadWeather),
+		maxCEPs:   h.maxCEPs,
+		ceps:      make(map[string]struct{}),
+	}
+}
+
+// reserve conta os CEPs de um campo no limite da requisição. O limite vale para a operação
+// inteira, somando cep e ceps de todos os aliases; CEPs repetidos contam uma única vez.
+func (l *loaders) reserve(codes ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	added := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		key := services.NormalizeCEP(code)
+		if _, ok := l.ceps[key]; !ok {
+			added[key] = struct{}{}
+		}
+	}
+	if len(l.ceps)+len(added) > l.maxCEPs {
+		return errTooManyCEPs
+	}
+	for key := range added {
+		l.ceps[key] = struct{}{}
+	}
+	return nil
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// loadLocations busca a localização dos CEPs do lote, com no máximo Workers consultas simultâneas
+func (h *Handler) loadLocations(ctx context.Context, ceps []string) []*dataloader.Result[*models.CEPResponse] {
+	results := make([]*dataloader.Result[*models.CEPResponse], len(ceps))
+	h.fanOut(len(ceps), func(i int) {
+		queryCtx, cancel := h.requestContext(ctx)
+		defer cancel()
+
+		location, err := h.cepService.GetLocation(queryCtx, ceps[i])
+		results[i] = &dataloader.Result[*models.CEPResponse]{Data: location, Error: err}
+	})
+	return results
+}
+
+// loadWeather busca o clima dos municípios do lote, com no máximo Workers consultas simultâneas
+func (h *Handler) loadWeather(ctx context.Context, queries []models.WeatherQuery) []*dataloader.Result[*models.WeatherResult] {
+	results := make([]*dataloader.Result[*models.WeatherResult], len(queries))
+	h.fanOut(len(queries), func(i int) {
+		queryCtx, cancel := h.requestContext(ctx)
+		defer cancel()
+
+		weather, err := h.weatherService.GetTemperature(queryCtx, queries[i])
+		results[i] = &dataloader.Result[*models.WeatherResult]{Data: weather, Error: err}
+	})
+	return results
+}
+
+// fanOut executa fn para cada índice de 0 a n-1 com no máximo Workers execuções simultâneas
+func (h *Handler) fanOut(n int, fn func(i int)) {
+	slots := make(chan struct{}, max(h.workers, 1))
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() { <-slots; wg.Done() }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// location valida o CEP e o carrega pelo loader da requisição
+func location(ctx context.Context, cepService services.CEPService, code string) (*models.CEPResponse, error) {
+	if !cepService.ValidateCEP(code) {
+		return nil, services.ErrInvalidCEP
+	}
+	return loadersFrom(ctx).locations.Load(ctx, services.NormalizeCEP(code))()
+}
@@ -0,0 +1,215 @@
+package graphql
+
+import (
+	"context"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+)
+
+// queryResolver resolve os campos de Query
+type queryResolver struct {
+	h *Handler
+}
+
+// Cep resolve cep(code). A consulta só acontece quando algum campo do CEP é pedido.
+func (q *queryResolver) Cep(ctx context.Context, args struct{ Code string }) (*cepResolver, error) {
+	if err := loadersFrom(ctx).reserve(args.Code); err != nil {
+		return nil, resolverError(err)
+	}
+	return &cepResolver{h: q.h, code: args.Code}, nil
+}
+
+// Ceps resolve ceps(codes), na ordem recebida
+func (q *queryResolver) Ceps(ctx context.Context, args struct{ Codes []string }) ([]*cepResolver, error) {
+	if err := loadersFrom(ctx).reserve(args.Codes...); err != nil {
+		return nil, resolverError(err)
+	}
+	resolvers := make([]*cepResolver, len(args.Codes))
+	for i, code := range args.Codes {
+		resolvers[i] = &cepResolver{h: q.h, code: code}
+	}
+	return resolvers, nil
+}
+
+// cepResolver resolve os campos de CEP a partir da localização carregada pelo loader
+type cepResolver struct {
+	h    *Handler
+	code string
+}
+
+func (r *cepResolver) location(ctx context.Context) (*models.CEPResponse, error) {
+	location, err := location(ctx, r.h.cepService, r.code)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return location, nil
+}
+
+func (r *cepResolver) Code(ctx context.Context) (string, error) {
+	location, err := r.location(ctx)
+	if err != nil {
+		return "", err
+	}
+	return location.CEP, nil
+}
+
+func (r *cepResolver) Street(ctx context.Context) (string, error) {
+	location, err := r.location(ctx)
+	if err != nil {
+		return "", err
+	}
+	return location.Logradouro, nil
+}
+
+func (r *cepResolver) Complement(ctx context.Context) (string, error) {
+	location, err := r.location(ctx)
+	if err != nil {
+		return "", err
+	}
+	return location.Complemento, nil
+}
+
+func (r *cepResolver) Neighborhood(ctx context.Context) (string, error) {
+	location, err := r.location(ctx)
+	if err != nil {
+		return "", err
+	}
+	return location.Bairro, nil
+}
+
+func (r *cepResolver) Municipality(ctx context.Context) (*municipalityResolver, error) {
+	location, err := r.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &municipalityResolver{location: location}, nil
+}
+
+// Weather carrega o clima do município pelo loader, compartilhado pelos CEPs do mesmo município
+func (r *cepResolver) Weather(ctx context.Context) (*weatherResolver, error) {
+	location, err := r.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+	weather, err := loadersFrom(ctx).weather.Load(ctx, services.WeatherQueryFor(location))()
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &weatherResolver{h: r.h, weather: weather}, nil
+}
+
+// municipalityResolver resolve os campos de Municipality
+type municipalityResolver struct {
+	location *models.CEPResponse
+}
+
+func (r *municipalityResolver) Name() string  { return r.location.Localidade }
+func (r *municipalityResolver) State() string { return r.location.UF }
+
+func (r *municipalityResolver) Ibge() *string {
+	return optionalString(r.location.IBGE)
+}
+
+// weatherResolver resolve os campos de Weather, convertendo as temperaturas pelo TemperatureService
+type weatherResolver struct {
+	h       *Handler
+	weather *models.WeatherResult
+}
+
+type unitArgs struct {
+	Unit string
+}
+
+type unitsArgs struct {
+	Units []string
+}
+
+func (r *weatherResolver) Temperature(args unitArgs) (float64, error) {
+	return r.convert(r.weather.TempC, args.Unit)
+}
+
+func (r *weatherResolver) Temperatures(args unitsArgs) ([]*readingResolver, error) {
+	symbols := []string{services.UnitCelsius.Symbol, services.UnitFahrenheit.Symbol, services.UnitKelvin.Symbol}
+	if args.Units != nil {
+		symbols = args.Units
+	}
+
+	readings := make([]*readingResolver, 0, len(symbols))
+	for _, symbol := range symbols {
+		unit, err := services.ParseUnit(symbol)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		readings = append(readings, &readingResolver{
+			unit:  unit.Symbol,
+			value: r.h.temperatureService.Convert(r.weather.TempC, services.UnitCelsius, unit),
+		})
+	}
+	return readings, nil
+}
+
+func (r *weatherResolver) FeelsLike(args unitArgs) (*float64, error) {
+	if r.weather.Conditions.FeelsLikeC == nil {
+		return nil, nil
+	}
+	value, err := r.convert(*r.weather.Conditions.FeelsLikeC, args.Unit)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func (r *weatherResolver) Humidity() *float64 { return r.weather.Conditions.Humidity }
+func (r *weatherResolver) WindKph() *float64  { return r.weather.Conditions.WindKph }
+
+func (r *weatherResolver) Condition() *string {
+	return optionalString(r.weather.Conditions.Text)
+}
+
+func (r *weatherResolver) LastUpdated() *string {
+	return optionalString(r.weather.Conditions.LastUpdated)
+}
+
+func (r *weatherResolver) Location() *weatherLocationResolver {
+	return &weatherLocationResolver{location: r.weather.Location, match: r.weather.Match}
+}
+
+// convert expressa uma temperatura em Celsius na escala pedida
+func (r *weatherResolver) convert(celsius float64, symbol string) (float64, error) {
+	unit, err := services.ParseUnit(symbol)
+	if err != nil {
+		return 0, resolverError(err)
+	}
+	return r.h.temperatureService.Convert(celsius, services.UnitCelsius, unit), nil
+}
+
+// readingResolver resolve os campos de TemperatureReading
+type readingResolver struct {
+	unit  string
+	value float64
+}
+
+func (r *readingResolver) Unit() string   { return r.unit }
+func (r *readingResolver) Value() float64 { return r.value }
+
+// weatherLocationResolver resolve os campos de WeatherLocation
+type weatherLocationResolver struct {
+	location models.WeatherLocation
+	match    models.LocationMatch
+}
+
+func (r *weatherLocationResolver) Name() string    { return r.location.Name }
+func (r *weatherLocationResolver) Region() string  { return r.location.Region }
+func (r *weatherLocationResolver) Country() string { return r.location.Country }
+
+func (r *weatherLocationResolver) Match() *string {
+	return optionalString(r.match.Status)
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
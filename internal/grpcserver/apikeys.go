@@ -0,0 +1,86 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"cep-temperatura/internal/apikeys"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadados das chaves de API e das cotas, com os mesmos nomes dos cabeçalhos HTTP
+const (
+	apiKeyMetadata             = "x-api-key"
+	rateLimitLimitMetadata     = "x-ratelimit-limit"
+	rateLimitRemainingMetadata = "x-ratelimit-remaining"
+	rateLimitResetMetadata     = "x-ratelimit-reset"
+)
+
+// errMissingAPIKey é a chamada sem x-api-key
+var errMissingAPIKey = errors.New("missing api key")
+
+// WithAPIKeys exige em cada chamada uma chave de API do store no metadado x-api-key, contada
+// na cota como uma requisição HTTP; um stream conta uma vez, na abertura
+func WithAPIKeys(store *apikeys.Store) Option {
+	return func(s *Server) {
+		s.keyStore = store
+	}
+}
+
+// unaryAPIKey conta a chamada na cota da chave antes do handler
+func (s *Server) unaryAPIKey(ctx context.Context, request any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	quota, err := s.consumeKey(ctx)
+	if quota.Limit > 0 {
+		_ = grpc.SetHeader(ctx, rateLimitMetadata(quota))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+// streamAPIKey conta o stream na cota da chave antes de abri-lo
+func (s *Server) streamAPIKey(server any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	quota, err := s.consumeKey(stream.Context())
+	if quota.Limit > 0 {
+		_ = stream.SetHeader(rateLimitMetadata(quota))
+	}
+	if err != nil {
+		return err
+	}
+	return handler(server, stream)
+}
+
+// consumeKey conta a chamada na cota da chave de x-api-key. Sem chave válida, a chamada é
+// recusada com Unauthenticated; além da cota, com ResourceExhausted.
+func (s *Server) consumeKey(ctx context.Context) (apikeys.Quota, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(apiKeyMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return apikeys.Quota{}, status.Error(codes.Unauthenticated, errMissingAPIKey.Error())
+	}
+
+	_, quota, err := s.keyStore.Consume(values[0], time.Now())
+	switch {
+	case errors.Is(err, apikeys.ErrQuotaExceeded):
+		return quota, status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		return quota, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return quota, nil
+}
+
+// rateLimitMetadata expõe a janela mais restrita da chave, como os cabeçalhos X-RateLimit-*
+func rateLimitMetadata(quota apikeys.Quota) metadata.MD {
+	return metadata.Pairs(
+		rateLimitLimitMetadata, strconv.FormatInt(quota.Limit, 10),
+		rateLimitRemainingMetadata, strconv.FormatInt(quota.Remaining, 10),
+		rateLimitResetMetadata, strconv.FormatInt(quota.Reset.Unix(), 10),
+	)
+}
@@ -0,0 +1,86 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/apikeys"
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/pb"
+	"cep-temperatura/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, key)
+}
+
+func TestServer_APIKeys(t *testing.T) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+	cepService.On("ValidateCEP", "01310100").Return(true)
+	cepService.On("GetLocation", mock.Anything, "01310100").Return(saoPaulo, nil)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	store, err := apikeys.NewStore("")
+	require.NoError(t, err)
+	revoked, revokedSecret, err := store.Create("revogada", 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.Revoke(revoked.ID))
+	_, secret, err := store.Create("parceiro", 2, 10)
+	require.NoError(t, err)
+
+	client := dial(t, NewServer(cepService, weatherService, services.NewTemperatureService(), WithAPIKeys(store)))
+	request := &pb.GetTemperatureRequest{Cep: "01310100"}
+
+	var header metadata.MD
+	_, err = client.GetTemperature(withKey(secret), request, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, header.Get(rateLimitLimitMetadata))
+	assert.Equal(t, []string{"1"}, header.Get(rateLimitRemainingMetadata))
+
+	// O stream conta uma vez, na abertura
+	ctx, cancel := context.WithTimeout(withKey(secret), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchTemperature(ctx, &pb.WatchTemperatureRequest{Cep: "01310100"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	cancel()
+
+	// Além da cota diária
+	_, err = client.GetTemperature(withKey(secret), request)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "api key quota exceeded", status.Convert(err).Message())
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		message string
+	}{
+		{"sem chave", context.Background(), "missing api key"},
+		{"chave desconhecida", withKey("ct_desconhecida"), "invalid api key"},
+		{"chave revogada", withKey(revokedSecret), "invalid api key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetTemperature(tt.ctx, request)
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			assert.Equal(t, tt.message, status.Convert(err).Message())
+
+			stream, err := client.WatchTemperature(tt.ctx, &pb.WatchTemperatureRequest{Cep: "01310100"})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// classCodes traduz as classes dos erros dos serviços para os códigos gRPC
+var classCodes = map[string]codes.Code{
+	services.ClassInvalidArgument:  codes.InvalidArgument,
+	services.ClassNotFound:         codes.NotFound,
+	services.ClassUnimplemented:    codes.Unimplemented,
+	services.ClassUnavailable:      codes.Unavailable,
+	services.ClassDeadlineExceeded: codes.DeadlineExceeded,
+	services.ClassInternal:         codes.Internal,
+}
+
+// statusError mapeia os erros dos serviços para códigos gRPC pelo catálogo dos serviços, com
+// as mesmas mensagens da API HTTP
+func statusError(err error) error {
+	code, message := statusCode(err)
+	return status.Error(code, message)
+}
+
+func statusCode(err error) (codes.Code, string) {
+	if errors.Is(err, context.Canceled) {
+		return codes.Canceled, "request canceled"
+	}
+	info := services.DescribeError(err)
+	return classCodes[info.Class()], info.Message
+}
+
+// batchStatus classifica o erro de um CEP do lote como em POST /temperature/batch
+func batchStatus(err error) string {
+	switch {
+	case errors.Is(err, services.ErrInvalidCEP):
+		return models.BatchStatusInvalid
+	case errors.Is(err, services.ErrCEPNotFound):
+		return models.BatchStatusNotFound
+	default:
+		return models.BatchStatusUpstreamError
+	}
+}
@@ -0,0 +1,229 @@
+// Package grpcserver expõe o serviço de temperatura via gRPC, com os mesmos serviços de CEP,
+// clima e temperatura da API HTTP.
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"cep-temperatura/internal/apikeys"
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/pb"
+	"cep-temperatura/internal/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limites padrão dos lotes
+const (
+	defaultBatchMaxCEPs = 100
+	defaultBatchWorkers = 8
+)
+
+// Server implementa pb.TemperatureServiceServer
+type Server struct {
+	pb.UnimplementedTemperatureServiceServer
+
+	cepService         services.CEPService
+	weatherService     services.WeatherService
+	temperatureService services.TemperatureService
+
+	requestTimeout time.Duration
+	batchMaxCEPs   int
+	batchWorkers   int
+	watcher        *services.WeatherWatcher
+	keyStore       *apikeys.Store
+}
+
+// Option personaliza o Server
+type Option func(*Server)
+
+// WithRequestTimeout limita cada consulta de CEP e clima, além do prazo definido pelo cliente
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.requestTimeout = timeout
+	}
+}
+
+// WithBatchLimits define quantos CEPs um lote aceita e quantas consultas dele correm em paralelo
+func WithBatchLimits(maxCEPs, workers int) Option {
+	return func(s *Server) {
+		s.batchMaxCEPs = maxCEPs
+		s.batchWorkers = workers
+	}
+}
+
+// WithWeatherWatcher define o agendador compartilhado das consultas de clima de
+// WatchTemperature, o mesmo dos streams SSE e do hub WebSocket
+func WithWeatherWatcher(watcher *services.WeatherWatcher) Option {
+	return func(s *Server) {
+		s.watcher = watcher
+	}
+}
+
+// NewServer cria o servidor gRPC de temperatura
+func NewServer(
+	cepService services.CEPService,
+	weatherService services.WeatherService,
+	temperatureService services.TemperatureService,
+	opts ...Option,
+) *Server {
+	s := &Server{
+		cepService:         cepService,
+		weatherService:     weatherService,
+		temperatureService: temperatureService,
+		batchMaxCEPs:       defaultBatchMaxCEPs,
+		batchWorkers:       defaultBatchWorkers,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.watcher == nil {
+		s.watcher = services.NewWeatherWatcher(weatherService)
+	}
+	return s
+}
+
+// Register cria um grpc.Server com o serviço de temperatura registrado e, com WithAPIKeys,
+// a exigência da chave de API em todas as chamadas
+func (s *Server) Register(opts ...grpc.ServerOption) *grpc.Server {
+	if s.keyStore != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.unaryAPIKey), grpc.ChainStreamInterceptor(s.streamAPIKey))
+	}
+	server := grpc.NewServer(opts...)
+	pb.RegisterTemperatureServiceServer(server, s)
+	return server
+}
+
+// GetTemperature busca a temperatura atual de um CEP
+func (s *Server) GetTemperature(ctx context.Context, request *pb.GetTemperatureRequest) (*pb.TemperatureResponse, error) {
+	location, err := s.lookupLocation(ctx, request.GetCep())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	weather, err := s.currentWeather(ctx, location)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return s.temperature(weather.TempC), nil
+}
+
+// BatchGetTemperature busca a temperatura de vários CEPs, com as mesmas regras de deduplicação
+// de POST /temperature/batch. A falha de um CEP não afeta os demais.
+func (s *Server) BatchGetTemperature(ctx context.Context, request *pb.BatchGetTemperatureRequest) (*pb.BatchGetTemperatureResponse, error) {
+	if len(request.GetCeps()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ceps is required")
+	}
+	if len(request.GetCeps()) > s.batchMaxCEPs {
+		return nil, status.Error(codes.InvalidArgument, "too many ceps")
+	}
+
+	lookup := services.BatchLookup{
+		CEPService:     s.cepService,
+		WeatherService: s.weatherService,
+		Workers:        s.batchWorkers,
+		CEPContext:     s.requestContext,
+		WeatherContext: s.requestContext,
+	}
+	batch := lookup.Lookup(ctx, request.GetCeps())
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	response := &pb.BatchGetTemperatureResponse{Results: make([]*pb.BatchTemperatureResult, len(batch))}
+	for i, item := range batch {
+		result := &pb.BatchTemperatureResult{Cep: item.CEP, Status: models.BatchStatusOK}
+		if item.Err != nil {
+			result.Status = batchStatus(item.Err)
+			result.Message = status.Convert(statusError(item.Err)).Message()
+		} else {
+			result.Temperature = s.temperature(item.Weather.TempC)
+		}
+		response.Results[i] = result
+	}
+	return response, nil
+}
+
+// WatchTemperature envia o clima do CEP na primeira consulta e sempre que a temperatura muda.
+// As consultas são as do WeatherWatcher, compartilhadas por município com os demais streams;
+// interval_seconds é ignorado. Uma falha na primeira consulta encerra o stream; as falhas
+// seguintes são ignoradas até a próxima consulta.
+func (s *Server) WatchTemperature(request *pb.WatchTemperatureRequest, stream grpc.ServerStreamingServer[pb.TemperatureUpdate]) error {
+	ctx := stream.Context()
+
+	location, err := s.lookupLocation(ctx, request.GetCep())
+	if err != nil {
+		return statusError(err)
+	}
+
+	updates, unsubscribe := s.watcher.Subscribe(location)
+	defer unsubscribe()
+
+	var last *float64
+	for {
+		var update services.WeatherUpdate
+		select {
+		case <-ctx.Done():
+			return nil
+		case update = <-updates:
+		}
+
+		if update.Err != nil {
+			if last == nil {
+				return statusError(update.Err)
+			}
+			continue
+		}
+		if last != nil && update.Weather.TempC == *last {
+			continue
+		}
+		last = &update.Weather.TempC
+		if err := stream.Send(s.update(request.GetCep(), update.Weather)); err != nil {
+			return err
+		}
+	}
+}
+
+// lookupLocation valida o CEP e busca a sua localização
+func (s *Server) lookupLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	if !s.cepService.ValidateCEP(cep) {
+		return nil, services.ErrInvalidCEP
+	}
+
+	cepCtx, cancel := s.requestContext(ctx)
+	defer cancel()
+	return s.cepService.GetLocation(cepCtx, cep)
+}
+
+// currentWeather busca o clima atual do município do CEP
+func (s *Server) currentWeather(ctx context.Context, location *models.CEPResponse) (*models.WeatherResult, error) {
+	weatherCtx, cancel := s.requestContext(ctx)
+	defer cancel()
+	return s.weatherService.GetTemperature(weatherCtx, services.WeatherQueryFor(location))
+}
+
+// requestContext aplica o prazo de cada consulta sobre o contexto da chamada
+func (s *Server) requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if s.requestTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, s.requestTimeout)
+}
+
+// temperature expressa uma temperatura em Celsius nas três escalas
+func (s *Server) temperature(celsius float64) *pb.TemperatureResponse {
+	fahrenheit, kelvin := s.temperatureService.ConvertTemperatures(celsius)
+	return &pb.TemperatureResponse{Temp_C: celsius, Temp_F: fahrenheit, Temp_K: kelvin}
+}
+
+func (s *Server) update(cep string, weather *models.WeatherResult) *pb.TemperatureUpdate {
+	return &pb.TemperatureUpdate{
+		Cep:         cep,
+		Temperature: s.temperature(weather.TempC),
+		LastUpdated: weather.Conditions.LastUpdated,
+	}
+}
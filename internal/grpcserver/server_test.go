@@ -0,0 +1,250 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/pb"
+	"cep-temperatura/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MockCEPService é um mock do CEPService
+type MockCEPService struct {
+	mock.Mock
+}
+
+func (m *MockCEPService) ValidateCEP(cep string) bool {
+	args := m.Called(cep)
+	return args.Bool(0)
+}
+
+func (m *MockCEPService) GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CEPResponse), args.Error(1)
+}
+
+// MockWeatherService é um mock do WeatherService
+type MockWeatherService struct {
+	mock.Mock
+}
+
+func (m *MockWeatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResult), args.Error(1)
+}
+
+func (m *MockWeatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options services.ForecastOptions) (*models.ForecastResult, error) {
+	return nil, services.ErrForecastUnsupported
+}
+
+func (m *MockWeatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options services.HistoryOptions) (*models.HistoryResult, error) {
+	return nil, services.ErrHistoryUnsupported
+}
+
+var saoPaulo = &models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+
+// dial sobe o servidor em memória e devolve um cliente conectado a ele
+func dial(t *testing.T, server *Server) pb.TemperatureServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.Register()
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewTemperatureServiceClient(conn)
+}
+
+func TestServer_GetTemperature(t *testing.T) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+	cepService.On("ValidateCEP", "01310100").Return(true)
+	cepService.On("ValidateCEP", "123").Return(false)
+	cepService.On("ValidateCEP", "99999999").Return(true)
+	cepService.On("ValidateCEP", "20040020").Return(true)
+	cepService.On("ValidateCEP", "69900000").Return(true)
+	cepService.On("GetLocation", mock.Anything, "01310100").Return(saoPaulo, nil)
+	cepService.On("GetLocation", mock.Anything, "99999999").Return(nil, fmt.Errorf("viacep: %w", services.ErrCEPNotFound))
+	cepService.On("GetLocation", mock.Anything, "20040020").Return(&models.CEPResponse{Localidade: "Rio de Janeiro", UF: "RJ"}, nil)
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(saoPaulo)).Return(&models.WeatherResult{TempC: 25}, nil)
+	weatherService.On("GetTemperature", mock.Anything, models.WeatherQuery{City: "Rio de Janeiro", State: "RJ"}).
+		Return(nil, fmt.Errorf("clima: %w", services.ErrUpstreamUnavailable))
+	cepService.On("GetLocation", mock.Anything, "69900000").Return(&models.CEPResponse{Localidade: "Rio Branco", UF: "AC"}, nil)
+	weatherService.On("GetTemperature", mock.Anything, models.WeatherQuery{City: "Rio Branco", State: "AC"}).
+		Return(nil, fmt.Errorf("clima: %w", services.ErrWeatherLocationNotFound))
+
+	client := dial(t, NewServer(cepService, weatherService, services.NewTemperatureService()))
+
+	t.Run("sucesso", func(t *testing.T) {
+		response, err := client.GetTemperature(context.Background(), &pb.GetTemperatureRequest{Cep: "01310100"})
+		require.NoError(t, err)
+		assert.Equal(t, 25.0, response.GetTemp_C())
+		assert.Equal(t, 77.0, response.GetTemp_F())
+		assert.Equal(t, 298.15, response.GetTemp_K())
+	})
+
+	tests := []struct {
+		name    string
+		cep     string
+		code    codes.Code
+		message string
+	}{
+		{"CEP inválido", "123", codes.InvalidArgument, "invalid zipcode"},
+		{"CEP não encontrado", "99999999", codes.NotFound, "can not find zipcode"},
+		{"provedor de clima indisponível", "20040020", codes.Unavailable, "upstream unavailable"},
+		// Como o 502 da API HTTP
+		{"local do clima não encontrado", "69900000", codes.Unavailable, "can not resolve weather location"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetTemperature(context.Background(), &pb.GetTemperatureRequest{Cep: tt.cep})
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.message, status.Convert(err).Message())
+		})
+	}
+}
+
+func TestServer_BatchGetTemperature(t *testing.T) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+	cepService.On("ValidateCEP", "01310100").Return(true)
+	cepService.On("ValidateCEP", "01001000").Return(true)
+	cepService.On("ValidateCEP", "99999999").Return(true)
+	cepService.On("ValidateCEP", "123").Return(false)
+	cepService.On("GetLocation", mock.Anything, "01310100").Return(saoPaulo, nil).Once()
+	cepService.On("GetLocation", mock.Anything, "01001000").Return(saoPaulo, nil).Once()
+	cepService.On("GetLocation", mock.Anything, "99999999").Return(nil, services.ErrCEPNotFound).Once()
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(saoPaulo)).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+
+	client := dial(t, NewServer(cepService, weatherService, services.NewTemperatureService(), WithBatchLimits(5, 2)))
+
+	response, err := client.BatchGetTemperature(context.Background(), &pb.BatchGetTemperatureRequest{
+		Ceps: []string{"01310100", "123", "99999999", "01001000", "01310100"},
+	})
+	require.NoError(t, err)
+
+	results := response.GetResults()
+	require.Len(t, results, 5)
+	assert.Equal(t, models.BatchStatusOK, results[0].GetStatus())
+	assert.Equal(t, 25.0, results[0].GetTemperature().GetTemp_C())
+	assert.Equal(t, models.BatchStatusInvalid, results[1].GetStatus())
+	assert.Equal(t, "invalid zipcode", results[1].GetMessage())
+	assert.Equal(t, models.BatchStatusNotFound, results[2].GetStatus())
+	assert.Equal(t, models.BatchStatusOK, results[3].GetStatus())
+	assert.Equal(t, "01310100", results[4].GetCep())
+	cepService.AssertExpectations(t)
+	weatherService.AssertExpectations(t)
+
+	t.Run("lote acima do limite", func(t *testing.T) {
+		_, err := client.BatchGetTemperature(context.Background(), &pb.BatchGetTemperatureRequest{
+			Ceps: []string{"1", "2", "3", "4", "5", "6"},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("lote vazio", func(t *testing.T) {
+		_, err := client.BatchGetTemperature(context.Background(), &pb.BatchGetTemperatureRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_WatchTemperature(t *testing.T) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+	cepService.On("ValidateCEP", "01310100").Return(true)
+	cepService.On("ValidateCEP", "123").Return(false)
+	cepService.On("GetLocation", mock.Anything, "01310100").Return(saoPaulo, nil).Once()
+
+	// A temperatura sobe de 25 para 26 na quarta consulta; repetições e falhas não geram atualização
+	query := services.WeatherQueryFor(saoPaulo)
+	before := &models.WeatherResult{TempC: 25, Conditions: models.Conditions{LastUpdated: "2025-01-10 15:00"}}
+	after := &models.WeatherResult{TempC: 26, Conditions: models.Conditions{LastUpdated: "2025-01-10 15:15"}}
+	weatherService.On("GetTemperature", mock.Anything, query).Return(before, nil).Twice()
+	weatherService.On("GetTemperature", mock.Anything, query).Return(nil, services.ErrUpstreamUnavailable).Once()
+	weatherService.On("GetTemperature", mock.Anything, query).Return(after, nil)
+
+	watcher := services.NewWeatherWatcher(weatherService, services.WithPollInterval(10*time.Millisecond))
+	client := dial(t, NewServer(cepService, weatherService, services.NewTemperatureService(), WithWeatherWatcher(watcher)))
+
+	t.Run("atualizações a cada mudança", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		stream, err := client.WatchTemperature(ctx, &pb.WatchTemperatureRequest{Cep: "01310100"})
+		require.NoError(t, err)
+
+		first, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "01310100", first.GetCep())
+		assert.Equal(t, 25.0, first.GetTemperature().GetTemp_C())
+		assert.Equal(t, "2025-01-10 15:00", first.GetLastUpdated())
+
+		second, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, 26.0, second.GetTemperature().GetTemp_C())
+		assert.Equal(t, "2025-01-10 15:15", second.GetLastUpdated())
+
+		// A localização do CEP é consultada uma única vez por stream
+		cepService.AssertNumberOfCalls(t, "GetLocation", 1)
+	})
+
+	t.Run("CEP inválido encerra o stream", func(t *testing.T) {
+		stream, err := client.WatchTemperature(context.Background(), &pb.WatchTemperatureRequest{Cep: "123"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_WatchTemperature_SharedPoller(t *testing.T) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+	cepService.On("ValidateCEP", mock.Anything).Return(true)
+	cepService.On("GetLocation", mock.Anything, mock.Anything).Return(saoPaulo, nil)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	watcher := services.NewWeatherWatcher(weatherService, services.WithPollInterval(time.Hour))
+	client := dial(t, NewServer(cepService, weatherService, services.NewTemperatureService(), WithWeatherWatcher(watcher)))
+
+	// Streams de CEPs do mesmo município compartilham uma única consulta periódica
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, cep := range []string{"01310100", "01310200", "01310100"} {
+		stream, err := client.WatchTemperature(ctx, &pb.WatchTemperatureRequest{Cep: cep})
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, 25.0, update.GetTemperature().GetTemp_C())
+	}
+	assert.Equal(t, 1, watcher.Pollers())
+	weatherService.AssertNumberOfCalls(t, "GetTemperature", 1)
+
+	// A consulta para quando o último stream fecha
+	cancel()
+	assert.Eventually(t, func() bool { return watcher.Pollers() == 0 }, time.Second, 10*time.Millisecond)
+}
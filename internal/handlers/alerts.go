@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cep-temperatura/internal/alerts"
+	"cep-temperatura/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientIDKey é a chave do contexto com o identificador do cliente da requisição, dono das
+// regras de alerta que ele cadastra
+const clientIDKey = "client_id"
+
+// alertOwner identifica o dono das regras de alerta da requisição. Sem identificador no
+// contexto, as regras pertencem a um único dono anônimo.
+func alertOwner(c *gin.Context) string {
+	return c.GetString(clientIDKey)
+}
+
+// CreateAlert cadastra uma regra de alerta para o CEP. A localização é resolvida no cadastro,
+// de modo que um CEP inexistente é recusado de imediato; o segredo que assina os webhooks só
+// é devolvido nesta resposta.
+func (h *TemperatureHandler) CreateAlert(c *gin.Context) {
+	var request models.AlertRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	if !h.cepService.ValidateCEP(request.CEP) {
+		writeError(c, invalidCEP(request.CEP))
+		return
+	}
+
+	ctx, cancel := h.budget.requestContext(c.Request.Context())
+	defer cancel()
+
+	location, ok := h.lookupLocation(c, ctx, request.CEP)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alerts.Add(alerts.Rule{
+		Owner:    alertOwner(c),
+		CEP:      request.CEP,
+		URL:      request.URL,
+		Above:    request.Above,
+		Below:    request.Below,
+		Secret:   request.Secret,
+		Location: location,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response := alertResponse(rule)
+	response.Secret = rule.Secret
+	c.Header("Location", "/alerts/"+rule.ID)
+	writeResponse(c, http.StatusCreated, response)
+}
+
+// ListAlerts lista as regras de alerta cadastradas pelo cliente
+func (h *TemperatureHandler) ListAlerts(c *gin.Context) {
+	rules := h.alerts.RulesOf(alertOwner(c))
+	response := models.AlertListResponse{Alerts: make([]models.AlertResponse, len(rules))}
+	for i, rule := range rules {
+		response.Alerts[i] = alertResponse(rule)
+	}
+	writeResponse(c, http.StatusOK, response)
+}
+
+// GetAlert devolve uma regra de alerta do cliente, com a condição da última avaliação
+func (h *TemperatureHandler) GetAlert(c *gin.Context) {
+	rule, err := h.alerts.Get(alertOwner(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeResponse(c, http.StatusOK, alertResponse(rule))
+}
+
+// DeleteAlert descadastra uma regra de alerta do cliente
+func (h *TemperatureHandler) DeleteAlert(c *gin.Context) {
+	if err := h.alerts.Remove(alertOwner(c), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeadLetters lista os webhooks de todos os clientes que esgotaram as tentativas de
+// entrega; a rota fica entre as de administração
+func (h *TemperatureHandler) ListDeadLetters(c *gin.Context) {
+	writeResponse(c, http.StatusOK, models.DeadLetterListResponse{DeadLetters: h.alerts.DeadLetters()})
+}
+
+// alertResponse expõe a regra sem o segredo
+func alertResponse(rule alerts.Rule) models.AlertResponse {
+	return models.AlertResponse{
+		ID:        rule.ID,
+		CEP:       rule.CEP,
+		URL:       rule.URL,
+		Above:     rule.Above,
+		Below:     rule.Below,
+		State:     rule.State,
+		CreatedAt: rule.CreatedAt,
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/alerts"
+	"cep-temperatura/internal/apikeys"
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlertHandler(opts ...alerts.Option) *TemperatureHandler {
+	mockCEPService := new(MockCEPService)
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("ValidateCEP", "99999999").Return(true)
+	mockCEPService.On("ValidateCEP", "123").Return(false)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo", UF: "SP", IBGE: "3550308",
+	}, nil)
+	mockCEPService.On("GetLocation", mock.Anything, "99999999").Return(nil, fmt.Errorf("viacep: %w", services.ErrCEPNotFound))
+
+	weatherService := new(MockWeatherService)
+	temperatureService := services.NewTemperatureService()
+	return NewTemperatureHandler(
+		mockCEPService,
+		weatherService,
+		temperatureService,
+		WithAlertEvaluator(alerts.NewEvaluator(weatherService, temperatureService, opts...)),
+	)
+}
+
+// testClientHeader identifica o cliente nos testes, no lugar da chave de API
+const testClientHeader = "X-Test-Client"
+
+// alertRouter registra as rotas de alertas como em cmd/main.go
+func alertRouter(handler *TemperatureHandler) *gin.Engine {
+	return alertRouterWithToken(handler, adminToken)
+}
+
+// alertRouterWithToken registra as rotas de alertas com o token de administração informado
+func alertRouterWithToken(handler *TemperatureHandler, token string) *gin.Engine {
+	store, err := apikeys.NewStore("")
+	if err != nil {
+		panic(err)
+	}
+	auth := NewAPIKeyAuth(store, token)
+
+	router := contractRouter()
+	router.GET("/alerts/dead-letters", auth.RequireAdmin, handler.ListDeadLetters)
+	clients := router.Group("", func(c *gin.Context) {
+		if client := c.GetHeader(testClientHeader); client != "" {
+			c.Set(clientIDKey, client)
+		}
+	})
+	clients.POST("/alerts", handler.CreateAlert)
+	clients.GET("/alerts", handler.ListAlerts)
+	clients.GET("/alerts/:id", handler.GetAlert)
+	clients.DELETE("/alerts/:id", handler.DeleteAlert)
+	return router
+}
+
+func performAlert(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	return performAlertAs(router, "", method, path, body)
+}
+
+func performAlertAs(router *gin.Engine, client, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if client != "" {
+		req.Header.Set(testClientHeader, client)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTemperatureHandler_Alerts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := alertRouter(newAlertHandler())
+
+	w := performAlert(router, "POST", "/alerts", `{"cep":"01310100","url":"https://example.com/hook","above":35,"below":5}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.AlertResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "/alerts/"+created.ID, w.Header().Get("Location"))
+	assert.Equal(t, "01310100", created.CEP)
+	assert.Equal(t, 35.0, *created.Above)
+	assert.Equal(t, 5.0, *created.Below)
+	assert.Equal(t, alerts.StateUnknown, created.State)
+	assert.NotEmpty(t, created.Secret)
+
+	// O segredo aparece apenas no cadastro
+	w = performAlert(router, "GET", "/alerts/"+created.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var fetched models.AlertResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Empty(t, fetched.Secret)
+	assert.Equal(t, created.ID, fetched.ID)
+
+	w = performAlert(router, "GET", "/alerts", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.AlertListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, []models.AlertResponse{fetched}, list.Alerts)
+
+	w = performAlert(router, "DELETE", "/alerts/"+created.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = performAlert(router, "GET", "/alerts/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "can not find alert", errorMessage(t, w))
+
+	w = performAlert(router, "GET", "/alerts", "")
+	assert.JSONEq(t, `{"alerts":[]}`, w.Body.String())
+}
+
+func TestTemperatureHandler_CreateAlert_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		message string
+	}{
+		{"CEP inválido", `{"cep":"123","url":"https://example.com/hook","above":35}`, http.StatusUnprocessableEntity, "invalid zipcode"},
+		{"CEP não encontrado", `{"cep":"99999999","url":"https://example.com/hook","above":35}`, http.StatusNotFound, "can not find zipcode"},
+		{"sem limite", `{"cep":"01310100","url":"https://example.com/hook"}`, http.StatusBadRequest, "invalid alert rule"},
+		{"faixa invertida", `{"cep":"01310100","url":"https://example.com/hook","above":5,"below":35}`, http.StatusBadRequest, "invalid alert rule"},
+		{"URL relativa", `{"cep":"01310100","url":"/hook","above":35}`, http.StatusBadRequest, "invalid alert rule"},
+		{"corpo inválido", `{"cep":`, http.StatusBadRequest, "invalid request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performAlert(alertRouter(newAlertHandler()), "POST", "/alerts", tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.message, errorMessage(t, w))
+		})
+	}
+}
+
+func TestTemperatureHandler_CreateAlert_Limit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := alertRouter(newAlertHandler(alerts.WithMaxRules(1)))
+
+	body := `{"cep":"01310100","url":"https://example.com/hook","above":35}`
+	assert.Equal(t, http.StatusCreated, performAlert(router, "POST", "/alerts", body).Code)
+
+	w := performAlert(router, "POST", "/alerts", body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "alert limit reached", errorMessage(t, w))
+}
+
+func TestTemperatureHandler_Alerts_Owner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := alertRouter(newAlertHandler())
+
+	w := performAlertAs(router, "cliente-a", "POST", "/alerts", `{"cep":"01310100","url":"https://example.com/hook","above":35}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.AlertResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	// Outro cliente não vê nem remove a regra
+	w = performAlertAs(router, "cliente-b", "GET", "/alerts", "")
+	assert.JSONEq(t, `{"alerts":[]}`, w.Body.String())
+	w = performAlertAs(router, "cliente-b", "GET", "/alerts/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = performAlertAs(router, "cliente-b", "DELETE", "/alerts/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performAlertAs(router, "cliente-a", "GET", "/alerts", "")
+	var list models.AlertListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Alerts, 1)
+	assert.Equal(t, created.ID, list.Alerts[0].ID)
+	w = performAlertAs(router, "cliente-a", "DELETE", "/alerts/"+created.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestTemperatureHandler_ListDeadLetters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := alertRouter(newAlertHandler())
+
+	w := performAdmin(router, "GET", "/alerts/dead-letters", adminToken, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"dead_letters":[]}`, w.Body.String())
+
+	// Os webhooks não entregues de todos os clientes ficam com a administração
+	w = performAlert(router, "GET", "/alerts/dead-letters", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = performAdmin(router, "GET", "/alerts/dead-letters", "outro-token", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Sem token configurado, o padrão, a rota fica fechada para qualquer requisição
+	closed := alertRouterWithToken(newAlertHandler(), "")
+	for _, token := range []string{"", adminToken} {
+		w = performAdmin(closed, "GET", "/alerts/dead-letters", token, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer realm="admin"`, w.Header().Get("WWW-Authenticate"))
+	}
+}
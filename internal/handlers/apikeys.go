@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cep-temperatura/internal/apikeys"
+	"cep-temperatura/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cabeçalhos das chaves de API e das cotas
+const (
+	apiKeyHeader             = "X-API-Key"
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// Origens das chaves de API
+const (
+	apiKeySourceConfig = "config"
+	apiKeySourceAdmin  = "admin"
+)
+
+// Erros de autenticação dos clientes e da administração das chaves
+var (
+	errMissingAPIKey     = errors.New("missing api key")
+	errAdminUnauthorized = errors.New("admin unauthorized")
+)
+
+// APIKeyAuth exige uma chave de API válida e com cota nas rotas dos clientes e administra as
+// chaves nas rotas protegidas pelo token de administração
+type APIKeyAuth struct {
+	store        *apikeys.Store
+	adminToken   string
+	dailyQuota   int64
+	monthlyQuota int64
+}
+
+// APIKeyOption personaliza o APIKeyAuth
+type APIKeyOption func(*APIKeyAuth)
+
+// WithDefaultQuotas define as cotas das chaves cadastradas sem cotas informadas
+func WithDefaultQuotas(dailyQuota, monthlyQuota int64) APIKeyOption {
+	return func(a *APIKeyAuth) {
+		a.dailyQuota = dailyQuota
+		a.monthlyQuota = monthlyQuota
+	}
+}
+
+// NewAPIKeyAuth cria a autenticação por chaves de API do store. Sem token de administração,
+// as rotas de administração recusam todas as requisições.
+func NewAPIKeyAuth(store *apikeys.Store, adminToken string, opts ...APIKeyOption) *APIKeyAuth {
+	a := &APIKeyAuth{store: store, adminToken: adminToken}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// RequireKey conta a requisição na cota da chave de X-API-Key antes do handler e identifica
+// o cliente pelo ID da chave. A resposta leva os cabeçalhos X-RateLimit-* da janela mais
+// restrita da chave; além da cota, a requisição é recusada com 429 e Retry-After.
+func (a *APIKeyAuth) RequireKey(c *gin.Context) {
+	secret := c.GetHeader(apiKeyHeader)
+	if secret == "" {
+		writeError(c, errMissingAPIKey)
+		c.Abort()
+		return
+	}
+
+	now := time.Now()
+	id, quota, err := a.store.Consume(secret, now)
+	setRateLimitHeaders(c, quota)
+	if err != nil {
+		if errors.Is(err, apikeys.ErrQuotaExceeded) {
+			c.Header("Retry-After", strconv.Itoa(int(quota.Reset.Sub(now).Seconds())+1))
+		}
+		writeError(c, err)
+		c.Abort()
+		return
+	}
+	c.Set(clientIDKey, id)
+	c.Next()
+}
+
+// RequireAdmin exige o token de administração em Authorization: Bearer
+func (a *APIKeyAuth) RequireAdmin(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || a.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) != 1 {
+		c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+		writeError(c, errAdminUnauthorized)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// CreateKey cadastra uma chave de API. A chave só é devolvida nesta resposta; o store guarda
+// apenas o hash.
+func (a *APIKeyAuth) CreateKey(c *gin.Context) {
+	var request models.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	dailyQuota, monthlyQuota := a.dailyQuota, a.monthlyQuota
+	if request.DailyQuota != nil {
+		dailyQuota = *request.DailyQuota
+	}
+	if request.MonthlyQuota != nil {
+		monthlyQuota = *request.MonthlyQuota
+	}
+
+	key, secret, err := a.store.Create(request.Name, dailyQuota, monthlyQuota)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response := apiKeyResponse(key)
+	response.Key = secret
+	writeResponse(c, http.StatusCreated, response)
+}
+
+// ListKeys lista as chaves de API, com o consumo e as revogadas
+func (a *APIKeyAuth) ListKeys(c *gin.Context) {
+	keys := a.store.List()
+	response := models.APIKeyListResponse{Keys: make([]models.APIKeyResponse, len(keys))}
+	for i, key := range keys {
+		response.Keys[i] = apiKeyResponse(key)
+	}
+	writeResponse(c, http.StatusOK, response)
+}
+
+// RevokeKey revoga uma chave de API; as requisições seguintes com ela recebem 401
+func (a *APIKeyAuth) RevokeKey(c *gin.Context) {
+	if err := a.store.Revoke(c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// setRateLimitHeaders expõe a janela mais restrita da chave; chaves sem cotas não levam os
+// cabeçalhos
+func setRateLimitHeaders(c *gin.Context, quota apikeys.Quota) {
+	if quota.Limit == 0 {
+		return
+	}
+	c.Header(rateLimitLimitHeader, strconv.FormatInt(quota.Limit, 10))
+	c.Header(rateLimitRemainingHeader, strconv.FormatInt(quota.Remaining, 10))
+	c.Header(rateLimitResetHeader, strconv.FormatInt(quota.Reset.Unix(), 10))
+}
+
+// apiKeyResponse expõe o cadastro da chave sem o hash
+func apiKeyResponse(key apikeys.Key) models.APIKeyResponse {
+	source := apiKeySourceAdmin
+	if key.Config {
+		source = apiKeySourceConfig
+	}
+	return models.APIKeyResponse{
+		ID:           key.ID,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		Source:       source,
+		DailyQuota:   key.DailyQuota,
+		MonthlyQuota: key.MonthlyQuota,
+		Usage: models.APIKeyUsage{
+			Day:        key.Usage.Day,
+			DayCount:   key.Usage.DayCount,
+			Month:      key.Usage.Month,
+			MonthCount: key.Usage.MonthCount,
+		},
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/apikeys"
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const adminToken = "token-de-administracao"
+
+// apiKeyRouter registra as rotas com chave de API e as de administração como em cmd/main.go
+func apiKeyRouter(t *testing.T, auth *APIKeyAuth) *gin.Engine {
+	t.Helper()
+	mockCEPService := new(MockCEPService)
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo", UF: "SP", IBGE: "3550308",
+	}, nil)
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+	handler := NewTemperatureHandler(mockCEPService, weatherService, services.NewTemperatureService())
+
+	router := contractRouter()
+	metered := router.Group("", auth.RequireKey)
+	api := metered.Group("", NegotiateFormat)
+	api.GET("/temperature/:cep", handler.GetTemperature)
+
+	admin := router.Group("/admin", auth.RequireAdmin)
+	admin.POST("/keys", auth.CreateKey)
+	admin.GET("/keys", auth.ListKeys)
+	admin.DELETE("/keys/:id", auth.RevokeKey)
+	return router
+}
+
+func newAPIKeyAuth(t *testing.T, opts ...apikeys.Option) (*APIKeyAuth, *apikeys.Store) {
+	t.Helper()
+	store, err := apikeys.NewStore("", opts...)
+	require.NoError(t, err)
+	return NewAPIKeyAuth(store, adminToken, WithDefaultQuotas(100, 1000)), store
+}
+
+func performWithKey(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/temperature/01310100", nil)
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func performAdmin(router *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuth_RequireKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, store := newAPIKeyAuth(t)
+	_, secret, err := store.Create("parceiro", 2, 10)
+	require.NoError(t, err)
+	router := apiKeyRouter(t, auth)
+
+	w := performWithKey(router, secret)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().UTC().Truncate(24*time.Hour).Add(24*time.Hour), time.Unix(reset, 0), time.Second)
+
+	w = performWithKey(router, secret)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Além da cota diária
+	w = performWithKey(router, secret)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "api key quota exceeded", errorMessage(t, w))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestAPIKeyAuth_RequireKey_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, store := newAPIKeyAuth(t)
+	key, secret, err := store.Create("parceiro", 10, 100)
+	require.NoError(t, err)
+	require.NoError(t, store.Revoke(key.ID))
+	router := apiKeyRouter(t, auth)
+
+	tests := []struct {
+		name    string
+		key     string
+		message string
+	}{
+		{"sem chave", "", "missing api key"},
+		{"chave desconhecida", "ct_desconhecida", "invalid api key"},
+		{"chave revogada", secret, "invalid api key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performWithKey(router, tt.key)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, tt.message, errorMessage(t, w))
+			assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+		})
+	}
+}
+
+func TestAPIKeyAuth_RequireKey_ConfigKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, _ := newAPIKeyAuth(t, apikeys.WithConfigKey("interno", apikeys.HashKey("chave-interna"), 0, 0))
+	router := apiKeyRouter(t, auth)
+
+	// Sem cotas, a resposta não leva os cabeçalhos X-RateLimit-*
+	w := performWithKey(router, "chave-interna")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestAPIKeyAuth_RequireKey_ClientID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, store := newAPIKeyAuth(t)
+	key, secret, err := store.Create("parceiro", 0, 0)
+	require.NoError(t, err)
+
+	// O ID da chave identifica o cliente, dono das regras de alerta
+	router := gin.New()
+	router.GET("/temperature/:cep", auth.RequireKey, func(c *gin.Context) {
+		c.String(http.StatusOK, alertOwner(c))
+	})
+	w := performWithKey(router, secret)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, key.ID, w.Body.String())
+}
+
+func TestAPIKeyAuth_Admin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, _ := newAPIKeyAuth(t)
+	router := apiKeyRouter(t, auth)
+
+	w := performAdmin(router, "POST", "/admin/keys", adminToken, `{"name":"parceiro","daily_quota":5}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.APIKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "parceiro", created.Name)
+	assert.Equal(t, "admin", created.Source)
+	assert.Equal(t, int64(5), created.DailyQuota)
+	assert.Equal(t, int64(1000), created.MonthlyQuota, "cota mensal padrão")
+	assert.NotEmpty(t, created.Key)
+	assert.NotContains(t, w.Body.String(), apikeys.HashKey(created.Key))
+
+	// A chave cadastrada já é aceita
+	w = performWithKey(router, created.Key)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "4", w.Header().Get("X-RateLimit-Remaining"))
+
+	// A listagem traz o consumo, sem a chave
+	w = performAdmin(router, "GET", "/admin/keys", adminToken, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.APIKeyListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Keys, 1)
+	assert.Empty(t, list.Keys[0].Key)
+	assert.Equal(t, created.Prefix, list.Keys[0].Prefix)
+	assert.Equal(t, int64(1), list.Keys[0].Usage.DayCount)
+
+	w = performAdmin(router, "DELETE", "/admin/keys/"+created.ID, adminToken, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, performWithKey(router, created.Key).Code)
+
+	w = performAdmin(router, "GET", "/admin/keys", adminToken, "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.NotNil(t, list.Keys[0].RevokedAt)
+
+	w = performAdmin(router, "DELETE", "/admin/keys/inexistente", adminToken, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "can not find api key", errorMessage(t, w))
+}
+
+func TestAPIKeyAuth_CreateKey_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		message string
+	}{
+		{"cota negativa", `{"name":"parceiro","daily_quota":-1}`, http.StatusBadRequest, "invalid quota"},
+		{"corpo inválido", `{"name":`, http.StatusBadRequest, "invalid request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, _ := newAPIKeyAuth(t)
+			w := performAdmin(apiKeyRouter(t, auth), "POST", "/admin/keys", adminToken, tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.message, errorMessage(t, w))
+		})
+	}
+}
+
+func TestAPIKeyAuth_RequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, _ := newAPIKeyAuth(t)
+	router := apiKeyRouter(t, auth)
+
+	for _, token := range []string{"", "outro-token"} {
+		w := performAdmin(router, "GET", "/admin/keys", token, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "invalid admin token", errorMessage(t, w))
+		assert.Equal(t, `Bearer realm="admin"`, w.Header().Get("WWW-Authenticate"))
+	}
+
+	// Sem token configurado, a administração fica fechada
+	store, err := apikeys.NewStore("")
+	require.NoError(t, err)
+	w := performAdmin(apiKeyRouter(t, NewAPIKeyAuth(store, "")), "GET", "/admin/keys", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
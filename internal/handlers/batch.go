@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limites padrão da consulta em lote
+const (
+	defaultBatchMaxCEPs = 100
+	defaultBatchWorkers = 8
+)
+
+// BatchLimits limita o tamanho de um lote e o número de consultas simultâneas
+type BatchLimits struct {
+	MaxCEPs int
+	Workers int
+}
+
+// GetTemperatureBatch busca a temperatura de vários CEPs. CEPs repetidos são consultados uma
+// única vez, e CEPs do mesmo município compartilham a consulta de clima. Cada consulta tem o
+// mesmo prazo de uma consulta individual; a falha de um CEP não afeta os demais.
+func (h *TemperatureHandler) GetTemperatureBatch(c *gin.Context) {
+	var request models.BatchTemperatureRequest
+	if err := c.ShouldBindJSON(&request); err != nil || len(request.CEPs) == 0 {
+		writeError(c, errInvalidBody)
+		return
+	}
+	if len(request.CEPs) > h.batch.MaxCEPs {
+		writeError(c, withDetail(errCEPBatchTooLong, "got %d ceps, maximum is %d", len(request.CEPs), h.batch.MaxCEPs))
+		return
+	}
+
+	lookup := services.BatchLookup{
+		CEPService:     h.cepService,
+		WeatherService: h.weatherService,
+		Workers:        h.batch.Workers,
+		CEPContext:     h.budget.cepContext,
+		WeatherContext: h.budget.requestContext,
+	}
+	batch := lookup.Lookup(c.Request.Context(), request.CEPs)
+
+	if c.Request.Context().Err() != nil {
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+
+	results := make([]models.BatchTemperatureResult, len(batch))
+	for i, item := range batch {
+		results[i].CEP = item.CEP
+		if item.Err != nil {
+			results[i].Message, results[i].Status = batchError(item.Err)
+			continue
+		}
+		temperature := h.convert(item.Weather.TempC)
+		results[i].Status = models.BatchStatusOK
+		results[i].TemperatureResponse = &temperature
+	}
+
+	writeResponse(c, http.StatusOK, models.BatchTemperatureResponse{Results: results})
+}
+
+// batchError classifica o erro de um CEP do lote, com a mesma mensagem da consulta individual
+func batchError(err error) (message, status string) {
+	_, message = errorResponse(err)
+	switch {
+	case errors.Is(err, services.ErrInvalidCEP):
+		return message, models.BatchStatusInvalid
+	case errors.Is(err, services.ErrCEPNotFound):
+		return message, models.BatchStatusNotFound
+	default:
+		return message, models.BatchStatusUpstreamError
+	}
+}
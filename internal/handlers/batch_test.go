@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func performBatch(handler *TemperatureHandler, body string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.POST("/temperature/batch", handler.GetTemperatureBatch)
+
+	req, _ := http.NewRequest("POST", "/temperature/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTemperatureHandler_GetTemperatureBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	saoPaulo := models.WeatherQuery{City: "São Paulo", State: "SP", IBGE: "3550308"}
+	rio := models.WeatherQuery{City: "Rio de Janeiro", State: "RJ", IBGE: "3304557"}
+	beloHorizonte := models.WeatherQuery{City: "Belo Horizonte", State: "MG", IBGE: "3106200"}
+
+	for _, cep := range []string{"01310100", "01310-100", "01001000", "20040020", "99999999", "30130010"} {
+		mockCEPService.On("ValidateCEP", cep).Return(true)
+	}
+	mockCEPService.On("ValidateCEP", "123").Return(false)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}, nil).Once()
+	mockCEPService.On("GetLocation", mock.Anything, "01001000").Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}, nil).Once()
+	mockCEPService.On("GetLocation", mock.Anything, "20040020").Return(&models.CEPResponse{Localidade: "Rio de Janeiro", UF: "RJ", IBGE: "3304557"}, nil).Once()
+	mockCEPService.On("GetLocation", mock.Anything, "99999999").Return(nil, fmt.Errorf("viacep: %w", services.ErrCEPNotFound)).Once()
+	mockCEPService.On("GetLocation", mock.Anything, "30130010").Return(&models.CEPResponse{Localidade: "Belo Horizonte", UF: "MG", IBGE: "3106200"}, nil).Once()
+	mockWeatherService.On("GetTemperature", mock.Anything, saoPaulo).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+	mockWeatherService.On("GetTemperature", mock.Anything, rio).Return(&models.WeatherResult{TempC: 30}, nil).Once()
+	mockWeatherService.On("GetTemperature", mock.Anything, beloHorizonte).Return(nil, fmt.Errorf("clima: %w", services.ErrUpstreamTimeout)).Once()
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+	w := performBatch(handler, `{"ceps": ["01310100", "123", "01310-100", "20040020", "99999999", "01001000", "30130010"]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"results": [
+		{"cep": "01310100", "status": "ok", "temp_C": 25, "temp_F": 77, "temp_K": 298.15},
+		{"cep": "123", "status": "invalid", "message": "invalid zipcode"},
+		{"cep": "01310-100", "status": "ok", "temp_C": 25, "temp_F": 77, "temp_K": 298.15},
+		{"cep": "20040020", "status": "ok", "temp_C": 30, "temp_F": 86, "temp_K": 303.15},
+		{"cep": "99999999", "status": "not_found", "message": "can not find zipcode"},
+		{"cep": "01001000", "status": "ok", "temp_C": 25, "temp_F": 77, "temp_K": 298.15},
+		{"cep": "30130010", "status": "upstream_error", "message": "upstream timeout"}
+	]}`, w.Body.String())
+
+	// CEPs repetidos e do mesmo município são consultados uma única vez
+	mockCEPService.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestTemperatureHandler_GetTemperatureBatch_InvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewTemperatureHandler(new(MockCEPService), new(MockWeatherService), services.NewTemperatureService(), WithBatchLimits(2, 1))
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"corpo inválido", `{"ceps": "01310100"}`, "invalid request body"},
+		{"lista vazia", `{"ceps": []}`, "invalid request body"},
+		{"lote acima do limite", `{"ceps": ["01310100", "01001000", "20040020"]}`, "too many ceps"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performBatch(handler, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.message, errorMessage(t, w))
+		})
+	}
+}
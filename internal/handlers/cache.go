@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cep-temperatura/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// observedAtKey guarda no contexto o horário da medição que originou a resposta
+const observedAtKey = "observed_at"
+
+// defaultCacheEntries limita quantas respostas têm os validadores guardados
+const defaultCacheEntries = 10000
+
+// ResponseCache define os cabeçalhos de cache das rotas GET e guarda os validadores (ETag e
+// Last-Modified) da última resposta de cada URL e formato. Enquanto essa resposta estiver
+// fresca, uma requisição condicional que a valide recebe 304 sem consultar os serviços
+// externos. O corpo não é guardado: as demais requisições seguem para o handler.
+type ResponseCache struct {
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	maxEntries int
+	private    bool
+}
+
+// CacheOption personaliza o ResponseCache
+type CacheOption func(*ResponseCache)
+
+// WithPrivateCache marca as respostas como Cache-Control: private. Com as chaves de API
+// ativas, um cache compartilhado (CDN, proxy) serviria a resposta de um cliente a outros,
+// sem chave e sem consumir a cota.
+func WithPrivateCache(private bool) CacheOption {
+	return func(rc *ResponseCache) {
+		rc.private = private
+	}
+}
+
+// cacheEntry são os validadores de uma resposta e o instante em que ela deixa de ser fresca
+type cacheEntry struct {
+	etag         string
+	lastModified time.Time
+	stored       time.Time
+	expires      time.Time
+}
+
+// NewResponseCache cria o cache de validadores com até maxEntries respostas; zero usa o padrão
+func NewResponseCache(maxEntries int, opts ...CacheOption) *ResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheEntries
+	}
+	rc := &ResponseCache{entries: map[string]cacheEntry{}, maxEntries: maxEntries}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	return rc
+}
+
+// Handle devolve o middleware de uma rota, com o max-age do Cache-Control. Com maxAge zero,
+// a rota responde sem cabeçalhos de cache.
+func (rc *ResponseCache) Handle(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxAge <= 0 || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		// O formato negociado faz parte da chave: cada formato tem o seu corpo e a sua ETag
+		key := responseFormat(c) + " " + c.Request.URL.RequestURI()
+		now := time.Now()
+		if entry, ok := rc.lookup(key, now); ok && notModified(c.Request, entry) {
+			rc.writeNotModified(c, entry, now)
+			c.Abort()
+			return
+		}
+
+		writer := openapi.NewBufferedWriter(c.Writer)
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.Status() != http.StatusOK {
+			writer.Release()
+			return
+		}
+
+		entry := cacheEntry{etag: strongETag(writer.Body()), stored: now, expires: now.Add(maxAge)}
+		if observedAt, ok := c.Get(observedAtKey); ok {
+			entry.lastModified = observedAt.(time.Time)
+		}
+		rc.store(key, entry)
+
+		// A resposta nova pode ser igual à que o cliente já tem
+		if notModified(c.Request, entry) {
+			rc.writeNotModified(c, entry, now)
+			return
+		}
+		rc.setCacheHeaders(c, entry, now)
+		writer.Release()
+	}
+}
+
+// setObservedAt registra o horário da medição, exposto em Last-Modified
+func setObservedAt(c *gin.Context, observedAt time.Time) {
+	if !observedAt.IsZero() {
+		c.Set(observedAtKey, observedAt)
+	}
+}
+
+// lookup devolve os validadores da resposta, se ela ainda estiver fresca
+func (rc *ResponseCache) lookup(key string, now time.Time) (cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store guarda os validadores. Com o cache cheio, descarta as respostas que já não estão
+// frescas; se nenhuma puder ser descartada, a nova não é guardada.
+func (rc *ResponseCache) store(key string, entry cacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= rc.maxEntries {
+		for existing, cached := range rc.entries {
+			if !entry.stored.Before(cached.expires) {
+				delete(rc.entries, existing)
+			}
+		}
+		if len(rc.entries) >= rc.maxEntries {
+			return
+		}
+	}
+	rc.entries[key] = entry
+}
+
+// notModified avalia If-None-Match e, na ausência dele, If-Modified-Since (RFC 9110)
+func notModified(r *http.Request, entry cacheEntry) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, entry.etag)
+	}
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || entry.lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !entry.lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches compara as ETags de If-None-Match com a da resposta, pela comparação fraca
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// strongETag deriva a ETag do corpo: os primeiros 16 bytes do SHA-256, em hexadecimal
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// setCacheHeaders expõe os validadores e o tempo restante de frescor da resposta
+func (rc *ResponseCache) setCacheHeaders(c *gin.Context, entry cacheEntry, now time.Time) {
+	header := c.Writer.Header()
+	header.Set("ETag", entry.etag)
+	if !entry.lastModified.IsZero() {
+		header.Set("Last-Modified", entry.lastModified.UTC().Format(http.TimeFormat))
+	}
+	visibility := "public"
+	if rc.private {
+		visibility = "private"
+	}
+	maxAge := entry.expires.Sub(entry.stored)
+	header.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds())))
+	if age := int(now.Sub(entry.stored).Seconds()); age > 0 {
+		header.Set("Age", fmt.Sprint(age))
+	}
+	header.Add("Vary", "Accept")
+}
+
+// writeNotModified responde 304 com os validadores, sem corpo
+func (rc *ResponseCache) writeNotModified(c *gin.Context, entry cacheEntry, now time.Time) {
+	rc.setCacheHeaders(c, entry, now)
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Length")
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var observedAt = time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC)
+
+func newCacheRouter(maxAge time.Duration, temperatures ...float64) (*gin.Engine, *MockCEPService, *MockWeatherService) {
+	mockCEPService := new(MockCEPService)
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("ValidateCEP", "123").Return(false)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo", UF: "SP", IBGE: "3550308",
+	}, nil)
+
+	weatherService := new(MockWeatherService)
+	for _, temperature := range temperatures {
+		weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{
+			TempC:      temperature,
+			Conditions: models.Conditions{ObservedAt: observedAt},
+		}, nil).Once()
+	}
+
+	handler := NewTemperatureHandler(mockCEPService, weatherService, services.NewTemperatureService())
+	router := contractRouter()
+	api := router.Group("", NegotiateFormat)
+	api.GET("/temperature/:cep", NewResponseCache(0).Handle(maxAge), handler.GetTemperature)
+	return router, mockCEPService, weatherService
+}
+
+func performCached(router *gin.Engine, path string, header map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCache_Headers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, _ := newCacheRouter(time.Minute, 25)
+
+	w := performCached(router, "/temperature/01310100", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, strongETag(w.Body.Bytes()), w.Header().Get("ETag"))
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, w.Header().Get("ETag"))
+	assert.Equal(t, "Fri, 10 Jan 2025 18:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+}
+
+func TestResponseCache_Private(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/convert", NewResponseCache(0, WithPrivateCache(true)).Handle(time.Minute), func(c *gin.Context) {
+		c.String(http.StatusOK, "298.15")
+	})
+
+	// Com as chaves de API ativas, CDNs e proxies não podem guardar a resposta
+	w := performCached(router, "/convert", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+
+	w = performCached(router, "/convert", map[string]string{"If-None-Match": w.Header().Get("ETag")})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+}
+
+func TestResponseCache_NotModifiedWithoutUpstream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		header func(first *httptest.ResponseRecorder) map[string]string
+	}{
+		{"If-None-Match", func(first *httptest.ResponseRecorder) map[string]string {
+			return map[string]string{"If-None-Match": first.Header().Get("ETag")}
+		}},
+		{"If-None-Match fraco e em lista", func(first *httptest.ResponseRecorder) map[string]string {
+			return map[string]string{"If-None-Match": `"outra", W/` + first.Header().Get("ETag")}
+		}},
+		{"If-Modified-Since", func(first *httptest.ResponseRecorder) map[string]string {
+			return map[string]string{"If-Modified-Since": first.Header().Get("Last-Modified")}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, cepService, weatherService := newCacheRouter(time.Minute, 25)
+
+			first := performCached(router, "/temperature/01310100", nil)
+			require.Equal(t, http.StatusOK, first.Code)
+
+			w := performCached(router, "/temperature/01310100", tt.header(first))
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Empty(t, w.Body.String())
+			assert.Equal(t, first.Header().Get("ETag"), w.Header().Get("ETag"))
+			assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+
+			// Nenhuma consulta além da primeira
+			cepService.AssertNumberOfCalls(t, "GetLocation", 1)
+			weatherService.AssertNumberOfCalls(t, "GetTemperature", 1)
+		})
+	}
+}
+
+func TestResponseCache_Modified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, weatherService := newCacheRouter(time.Minute, 25, 25, 25)
+
+	performCached(router, "/temperature/01310100", nil)
+
+	tests := []map[string]string{
+		{"If-None-Match": `"outra"`},
+		{"If-Modified-Since": "Fri, 10 Jan 2025 17:59:59 GMT"},
+	}
+	for _, header := range tests {
+		w := performCached(router, "/temperature/01310100", header)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	weatherService.AssertNumberOfCalls(t, "GetTemperature", 3)
+}
+
+func TestResponseCache_Expired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, weatherService := newCacheRouter(time.Millisecond, 25, 25, 30)
+
+	first := performCached(router, "/temperature/01310100", nil)
+	time.Sleep(5 * time.Millisecond)
+
+	// Expirada, a resposta é refeita; se não mudou, o cliente ainda recebe 304
+	ifNoneMatch := map[string]string{"If-None-Match": first.Header().Get("ETag")}
+	w := performCached(router, "/temperature/01310100", ifNoneMatch)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	weatherService.AssertNumberOfCalls(t, "GetTemperature", 2)
+
+	time.Sleep(5 * time.Millisecond)
+	w = performCached(router, "/temperature/01310100", ifNoneMatch)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"temp_C": 30, "temp_F": 86, "temp_K": 303.15}`, w.Body.String())
+	assert.NotEqual(t, first.Header().Get("ETag"), w.Header().Get("ETag"))
+}
+
+func TestResponseCache_PerFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, _ := newCacheRouter(time.Minute, 25, 25)
+
+	jsonResponse := performCached(router, "/temperature/01310100", nil)
+
+	// A ETag do JSON não valida a resposta em XML
+	w := performCached(router, "/temperature/01310100?format=xml", map[string]string{"If-None-Match": jsonResponse.Header().Get("ETag")})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, jsonResponse.Header().Get("ETag"), w.Header().Get("ETag"))
+}
+
+func TestResponseCache_ErrorsNotCached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, _ := newCacheRouter(time.Minute)
+
+	w := performCached(router, "/temperature/123", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+
+	w = performCached(router, "/temperature/123", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestResponseCache_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, _ := newCacheRouter(0, 25)
+
+	w := performCached(router, "/temperature/01310100", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestResponseCache_MaxEntries(t *testing.T) {
+	cache := NewResponseCache(1)
+	now := time.Now()
+
+	cache.store("a", cacheEntry{etag: `"a"`, stored: now, expires: now.Add(time.Minute)})
+	cache.store("b", cacheEntry{etag: `"b"`, stored: now, expires: now.Add(time.Minute)})
+	_, ok := cache.lookup("b", now)
+	assert.False(t, ok, "cache cheio de respostas frescas não guarda novas")
+
+	// As respostas expiradas dão lugar às novas
+	later := now.Add(2 * time.Minute)
+	cache.store("b", cacheEntry{etag: `"b"`, stored: later, expires: later.Add(time.Minute)})
+	entry, ok := cache.lookup("b", later)
+	assert.True(t, ok)
+	assert.Equal(t, `"b"`, entry.etag)
+}
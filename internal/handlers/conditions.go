@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"strings"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Blocos opcionais da resposta de temperatura, solicitados via ?include=
+const (
+	includeConditions = "conditions"
+	includeLocation   = "location"
+	includeWind       = "wind"
+	includeHumidity   = "humidity"
+	includeFeelsLike  = "feelslike"
+	includeUV         = "uv"
+	includeComfort    = "comfort"
+)
+
+var knownIncludes = map[string]bool{
+	includeConditions: true,
+	includeLocation:   true,
+	includeWind:       true,
+	includeHumidity:   true,
+	includeFeelsLike:  true,
+	includeUV:         true,
+	includeComfort:    true,
+}
+
+// parseIncludes lê a lista separada por vírgulas do parâmetro include. Sem o parâmetro,
+// ou com ele vazio, a resposta mantém o formato original.
+func parseIncludes(c *gin.Context) (map[string]bool, error) {
+	includes := map[string]bool{}
+	for _, value := range strings.Split(c.Query("include"), ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if !knownIncludes[value] {
+			return nil, withDetail(errInvalidInclude, "unknown include %q", value)
+		}
+		includes[value] = true
+	}
+	return includes, nil
+}
+
+// extendedResponse monta a resposta de temperatura com os blocos solicitados
+func (h *TemperatureHandler) extendedResponse(location *models.CEPResponse, weather *models.WeatherResult, includes map[string]bool) models.ExtendedTemperatureResponse {
+	conditions := weather.Conditions
+	temperature := h.convert(weather.TempC)
+	response := models.ExtendedTemperatureResponse{
+		TemperatureResponse: &temperature,
+		LastUpdated:         conditions.LastUpdated,
+	}
+
+	if includes[includeLocation] {
+		response.Location = &models.LocationResponse{
+			City:    location.Localidade,
+			State:   location.UF,
+			IBGE:    location.IBGE,
+			Weather: weather.Location,
+			Match:   weather.Match.Status,
+		}
+	}
+	if includes[includeConditions] {
+		if conditions.Text != "" || conditions.Code != nil {
+			response.Condition = &models.ConditionResponse{Text: conditions.Text, Code: conditions.Code}
+		}
+		response.PressureMb = conditions.PressureMb
+	}
+	if includes[includeWind] && (conditions.WindKph != nil || conditions.WindDegree != nil || conditions.WindDir != "") {
+		response.Wind = &models.WindResponse{
+			SpeedKph:  conditions.WindKph,
+			Degree:    conditions.WindDegree,
+			Direction: conditions.WindDir,
+		}
+	}
+	if includes[includeHumidity] {
+		response.Humidity = conditions.Humidity
+	}
+	if includes[includeFeelsLike] && conditions.FeelsLikeC != nil {
+		feelsLike := h.convert(*conditions.FeelsLikeC)
+		response.FeelsLike = &feelsLike
+	}
+	if includes[includeUV] {
+		response.UV = conditions.UV
+	}
+	if includes[includeComfort] {
+		indices := h.temperatureService.Comfort(weather.TempC, conditions.Humidity, conditions.WindKph)
+		response.Comfort = &models.ComfortResponse{
+			HeatIndex: h.comfortTemperature(indices.HeatIndex),
+			WindChill: h.comfortTemperature(indices.WindChill),
+			DewPoint:  h.comfortTemperature(indices.DewPoint),
+			Humidex:   comfortValue(indices.Humidex),
+			WetBulb:   h.comfortTemperature(indices.WetBulb),
+		}
+	}
+
+	return response
+}
+
+// comfortTemperature apresenta um índice expresso em Celsius nas três escalas
+func (h *TemperatureHandler) comfortTemperature(index models.ComfortIndex) models.ComfortIndexResponse {
+	response := models.ComfortIndexResponse{Valid: index.Valid, Risk: index.Risk, Reason: index.Reason}
+	if index.Valid {
+		temperature := h.convert(index.Value)
+		response.TemperatureResponse = &temperature
+	}
+	return response
+}
+
+// comfortValue apresenta um índice adimensional, como o humidex
+func comfortValue(index models.ComfortIndex) models.ComfortIndexResponse {
+	response := models.ComfortIndexResponse{Valid: index.Valid, Risk: index.Risk, Reason: index.Reason}
+	if index.Valid {
+		response.Value = &index.Value
+	}
+	return response
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func floatPtr(value float64) *float64 { return &value }
+
+func newConditionsHandler() *TemperatureHandler {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	code := 1003
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo",
+		UF:         "SP",
+		IBGE:       "3550308",
+	}, nil)
+	mockWeatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{
+		TempC: 25,
+		Conditions: models.Conditions{
+			Text:        "Partly cloudy",
+			Code:        &code,
+			Humidity:    floatPtr(65),
+			WindKph:     floatPtr(11.2),
+			WindDegree:  floatPtr(140),
+			WindDir:     "SE",
+			PressureMb:  floatPtr(1015),
+			FeelsLikeC:  floatPtr(30),
+			UV:          floatPtr(7),
+			LastUpdated: "2025-01-10 15:00",
+		},
+		Location: models.WeatherLocation{Name: "São Paulo", Region: "Sao Paulo", Country: "Brazil"},
+		Match:    models.LocationMatch{Status: models.LocationMatched, Strategy: "coordinates", Attempts: 1},
+	}, nil)
+
+	return NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+}
+
+func performTemperature(handler *TemperatureHandler, rawQuery string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.GET("/temperature/:cep", handler.GetTemperature)
+
+	req, _ := http.NewRequest("GET", "/temperature/01310100?"+rawQuery, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTemperatureHandler_GetTemperature_DefaultPayloadUnchanged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, rawQuery := range []string{"", "include="} {
+		w := performTemperature(newConditionsHandler(), rawQuery)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"temp_C":25,"temp_F":77,"temp_K":298.15}`, w.Body.String())
+	}
+}
+
+func TestTemperatureHandler_GetTemperature_Includes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("todos os blocos", func(t *testing.T) {
+		w := performTemperature(newConditionsHandler(), "include=conditions,location,wind,humidity,feelslike,uv")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"temp_C": 25, "temp_F": 77, "temp_K": 298.15,
+			"location": {"city": "São Paulo", "state": "SP", "ibge": "3550308",
+				"weather": {"name": "São Paulo", "region": "Sao Paulo", "country": "Brazil"}, "match": "matched"},
+			"condition": {"text": "Partly cloudy", "code": 1003},
+			"pressure_mb": 1015,
+			"humidity": 65,
+			"wind": {"speed_kph": 11.2, "degree": 140, "direction": "SE"},
+			"feelslike": {"temp_C": 30, "temp_F": 86, "temp_K": 303.15},
+			"uv": 7,
+			"last_updated": "2025-01-10 15:00"
+		}`, w.Body.String())
+	})
+
+	t.Run("apenas os blocos solicitados", func(t *testing.T) {
+		w := performTemperature(newConditionsHandler(), "include=Humidity, uv")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"temp_C": 25, "temp_F": 77, "temp_K": 298.15,
+			"humidity": 65,
+			"uv": 7,
+			"last_updated": "2025-01-10 15:00"
+		}`, w.Body.String())
+	})
+
+	t.Run("bloco desconhecido", func(t *testing.T) {
+		w := performTemperature(newConditionsHandler(), "include=wind,pollen")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid include", response["message"])
+	})
+}
+
+func TestTemperatureHandler_GetTemperature_Comfort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := performTemperature(newConditionsHandler(), "include=comfort")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		TempC   float64 `json:"temp_C"`
+		Comfort map[string]struct {
+			TempC  *float64 `json:"temp_C"`
+			TempF  *float64 `json:"temp_F"`
+			Value  *float64 `json:"value"`
+			Valid  bool     `json:"valid"`
+			Risk   string   `json:"risk"`
+			Reason string   `json:"reason"`
+		} `json:"comfort"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 25.0, response.TempC)
+
+	// 25 °C está abaixo da faixa do índice de calor e acima da faixa da sensação pelo vento
+	for _, name := range []string{"heat_index", "wind_chill"} {
+		index := response.Comfort[name]
+		assert.False(t, index.Valid, name)
+		assert.Nil(t, index.TempC, name)
+		assert.NotEmpty(t, index.Reason, name)
+		assert.Empty(t, index.Risk, name)
+	}
+
+	dewPoint := response.Comfort["dew_point"]
+	assert.True(t, dewPoint.Valid)
+	assert.InDelta(t, 18.0, *dewPoint.TempC, 0.1)
+	assert.NotNil(t, dewPoint.TempF)
+	assert.Equal(t, models.RiskNone, dewPoint.Risk)
+
+	humidex := response.Comfort["humidex"]
+	assert.True(t, humidex.Valid)
+	assert.Nil(t, humidex.TempC)
+	assert.InDelta(t, 31.0, *humidex.Value, 0.1)
+	assert.Equal(t, models.RiskCaution, humidex.Risk)
+
+	assert.True(t, response.Comfort["wet_bulb"].Valid)
+}
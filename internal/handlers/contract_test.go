@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"cep-temperatura/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contractRouter monta um roteador que valida as respostas contra o documento OpenAPI, para
+// que a divergência entre os handlers e o contrato publicado falhe os testes
+func contractRouter() *gin.Engine {
+	validator, err := openapi.NewValidator(openapi.WithResponseValidation(true))
+	if err != nil {
+		panic(err)
+	}
+	router := gin.New()
+	router.Use(validator.Middleware)
+	return router
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxConvertValues limita o tamanho de uma conversão em lote
+const maxConvertValues = 1000
+
+// Convert converte uma temperatura entre duas escalas (?value=&from=&to=)
+func (h *TemperatureHandler) Convert(c *gin.Context) {
+	value, err := strconv.ParseFloat(c.Query("value"), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		writeError(c, withDetail(errInvalidValue, "value %q", c.Query("value")))
+		return
+	}
+
+	from, to, err := parseConvertUnits(c.Query("from"), c.Query("to"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if err := h.temperatureService.Validate(value, from); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeResponse(c, http.StatusOK, models.ConvertResponse{
+		Value:  value,
+		From:   from.Symbol,
+		To:     to.Symbol,
+		Result: h.temperatureService.Convert(value, from, to),
+	})
+}
+
+// ConvertBatch converte uma lista de temperaturas entre duas escalas. Basta um valor abaixo
+// do zero absoluto para rejeitar o lote inteiro; o índice dele vem em detail.
+func (h *TemperatureHandler) ConvertBatch(c *gin.Context) {
+	var request models.ConvertBatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil || len(request.Values) == 0 {
+		writeError(c, errInvalidBody)
+		return
+	}
+	if len(request.Values) > maxConvertValues {
+		writeError(c, withDetail(errConvertBatchTooLong, "got %d values", len(request.Values)))
+		return
+	}
+
+	from, to, err := parseConvertUnits(request.From, request.To)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	results := make([]float64, len(request.Values))
+	for i, value := range request.Values {
+		if err := h.temperatureService.Validate(value, from); err != nil {
+			writeError(c, withDetail(err, "values[%d]: %g %s is colder than absolute zero", i, value, from.Symbol))
+			return
+		}
+		results[i] = h.temperatureService.Convert(value, from, to)
+	}
+
+	writeResponse(c, http.StatusOK, models.ConvertBatchResponse{
+		From:    from.Symbol,
+		To:      to.Symbol,
+		Values:  request.Values,
+		Results: results,
+	})
+}
+
+// parseConvertUnits interpreta as escalas de origem e destino de uma conversão
+func parseConvertUnits(fromSymbol, toSymbol string) (from, to services.Unit, err error) {
+	if from, err = services.ParseUnit(fromSymbol); err != nil {
+		return from, to, withDetail(err, "from %q", fromSymbol)
+	}
+	if to, err = services.ParseUnit(toSymbol); err != nil {
+		return from, to, withDetail(err, "to %q", toSymbol)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConvertHandler() *TemperatureHandler {
+	return NewTemperatureHandler(new(MockCEPService), new(MockWeatherService), services.NewTemperatureService())
+}
+
+func performConvert(rawQuery string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.GET("/convert", newConvertHandler().Convert)
+
+	req, _ := http.NewRequest("GET", "/convert?"+rawQuery, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func performConvertBatch(body string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.POST("/convert", newConvertHandler().ConvertBatch)
+
+	req, _ := http.NewRequest("POST", "/convert", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func errorMessage(t *testing.T, w *httptest.ResponseRecorder) string {
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response["message"]
+}
+
+func TestTemperatureHandler_Convert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sucesso", func(t *testing.T) {
+		w := performConvert("value=100&from=c&to=F")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"value": 100, "from": "C", "to": "F", "result": 212}`, w.Body.String())
+	})
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		status   int
+		message  string
+	}{
+		{"valor ausente", "from=C&to=F", http.StatusBadRequest, "invalid value"},
+		{"valor não numérico", "value=abc&from=C&to=F", http.StatusBadRequest, "invalid value"},
+		{"valor infinito", "value=Inf&from=C&to=F", http.StatusBadRequest, "invalid value"},
+		{"escala desconhecida", "value=10&from=C&to=X", http.StatusBadRequest, "invalid unit"},
+		{"escala ausente", "value=10&to=F", http.StatusBadRequest, "invalid unit"},
+		{"abaixo do zero absoluto", "value=-1&from=K&to=C", http.StatusUnprocessableEntity, "temperature below absolute zero"},
+		{"abaixo do zero absoluto em Delisle", "value=600&from=De&to=C", http.StatusUnprocessableEntity, "temperature below absolute zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performConvert(tt.rawQuery)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.message, errorMessage(t, w))
+		})
+	}
+}
+
+func TestTemperatureHandler_ConvertBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sucesso", func(t *testing.T) {
+		w := performConvertBatch(`{"values": [0, 100, -40], "from": "C", "to": "F"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"from": "C", "to": "F", "values": [0, 100, -40], "results": [32, 212, -40]}`, w.Body.String())
+	})
+
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		message string
+	}{
+		{"corpo inválido", `{"values": "10"}`, http.StatusBadRequest, "invalid request body"},
+		{"lista vazia", `{"values": [], "from": "C", "to": "F"}`, http.StatusBadRequest, "invalid request body"},
+		{"escala desconhecida", `{"values": [10], "from": "C", "to": "X"}`, http.StatusBadRequest, "invalid unit"},
+		{"um valor abaixo do zero absoluto", `{"values": [10, -500], "from": "F", "to": "C"}`, http.StatusUnprocessableEntity, "temperature below absolute zero"},
+		{"um valor abaixo do zero absoluto em Delisle", `{"values": [150, 600], "from": "De", "to": "C"}`, http.StatusUnprocessableEntity, "temperature below absolute zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performConvertBatch(tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.message, errorMessage(t, w))
+		})
+	}
+
+	t.Run("Delisle", func(t *testing.T) {
+		// Os valores crescem no sentido do frio: 150 De é 0 °C e 0 De é 100 °C
+		w := performConvertBatch(`{"values": [150, 0, 300], "from": "De", "to": "C"}`)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"from": "De", "to": "C", "values": [150, 0, 300], "results": [0, 100, -100]}`, w.Body.String())
+	})
+
+	t.Run("índice do valor recusado", func(t *testing.T) {
+		w := performConvertBatch(`{"values": [10, 20, -500], "from": "F", "to": "C"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "values[2]: -500 F is colder than absolute zero", response.Detail)
+	})
+
+	t.Run("lote acima do limite", func(t *testing.T) {
+		values, _ := json.Marshal(make([]float64, maxConvertValues+1))
+		w := performConvertBatch(`{"values": ` + string(values) + `, "from": "C", "to": "F"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "too many values, maximum is 1000", errorMessage(t, w))
+	})
+}
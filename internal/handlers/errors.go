@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"cep-temperatura/internal/alerts"
+	"cep-temperatura/internal/apikeys"
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/openapi"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Erros de validação dos parâmetros de consulta
+var (
+	errInvalidForecastDays = errors.New("invalid forecast days")
+	errInvalidHourly       = errors.New("invalid hourly flag")
+	errInvalidDate         = errors.New("invalid date")
+	errInvalidDateRange    = errors.New("invalid date range")
+	errDateRangeTooLong    = errors.New("date range too long")
+	errInvalidPagination   = errors.New("invalid pagination")
+	errInvalidInclude      = errors.New("invalid include")
+	errInvalidUnits        = errors.New("invalid units")
+	errInvalidValue        = errors.New("invalid value")
+	errInvalidBody         = errors.New("invalid request body")
+	errConvertBatchTooLong = errors.New("convert batch too long")
+	errCEPBatchTooLong     = errors.New("cep batch too long")
+	errInvalidFormat       = errors.New("invalid format")
+	errNotAcceptable       = errors.New("not acceptable")
+	errFormatUnsupported   = errors.New("format not supported for response")
+	errUnknownProblemType  = errors.New("unknown problem type")
+)
+
+// detailedError acrescenta a um erro do catálogo a descrição da ocorrência, como o parâmetro,
+// o CEP ou o índice que o causou. A descrição é exposta em detail.
+type detailedError struct {
+	err    error
+	detail string
+}
+
+func (e *detailedError) Error() string { return e.detail + ": " + e.err.Error() }
+
+func (e *detailedError) Unwrap() error { return e.err }
+
+// withDetail descreve a ocorrência do erro
+func withDetail(err error, format string, args ...any) error {
+	return &detailedError{err: err, detail: fmt.Sprintf(format, args...)}
+}
+
+// errorDetail devolve a descrição da ocorrência na cadeia do erro, ou vazio
+func errorDetail(err error) string {
+	var detailed *detailedError
+	if errors.As(err, &detailed) {
+		return detailed.detail
+	}
+	var requestErr *openapi.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.Detail
+	}
+	return ""
+}
+
+// invalidCEP descreve o CEP recusado pela validação
+func invalidCEP(cep string) error {
+	return withDetail(services.ErrInvalidCEP, "cep %q must have 8 digits", cep)
+}
+
+// WriteError escreve o erro com o corpo das rotas da API, para os middlewares de fora do
+// pacote, como a validação do contrato OpenAPI
+func WriteError(c *gin.Context, err error) {
+	writeError(c, err)
+}
+
+// writeError responde com o status HTTP e a mensagem correspondentes ao erro tipado. Os
+// clientes que pedem problem+json recebem o corpo da RFC 7807, com o código estável do erro;
+// a descrição da ocorrência, quando houver, vai em detail nos dois corpos.
+func writeError(c *gin.Context, err error) {
+	entry := lookupError(err)
+	if wantsProblem(c) {
+		writeProblem(c, entry, errorDetail(err))
+		return
+	}
+	writeResponse(c, entry.status, models.ErrorResponse{Message: entry.message, Detail: errorDetail(err)})
+}
+
+// errorResponse mapeia os erros dos serviços para status HTTP e mensagem
+func errorResponse(err error) (int, string) {
+	entry := lookupError(err)
+	return entry.status, entry.message
+}
+
+// catalogEntry descreve um erro da API: o status HTTP, o código estável exposto em code no
+// problem+json, o título do tipo de problema e a mensagem legada
+type catalogEntry struct {
+	target  error
+	status  int
+	code    string
+	title   string
+	message string
+}
+
+// httpErrors lista os erros próprios da API HTTP, testados antes dos erros dos serviços
+var httpErrors = []catalogEntry{
+	{errInvalidForecastDays, http.StatusBadRequest, "invalid_days", "Invalid forecast days", "invalid days"},
+	{errInvalidHourly, http.StatusBadRequest, "invalid_hourly", "Invalid hourly flag", "invalid hourly"},
+	{errInvalidDate, http.StatusBadRequest, "invalid_date", "Invalid date", "invalid date, expected YYYY-MM-DD"},
+	{errInvalidDateRange, http.StatusBadRequest, "invalid_date_range", "Invalid date range", "invalid date range"},
+	{errDateRangeTooLong, http.StatusBadRequest, "date_range_too_long", "Date range too long", "date range exceeds 366 days"},
+	{errInvalidPagination, http.StatusBadRequest, "invalid_pagination", "Invalid pagination", "invalid pagination"},
+	{errInvalidInclude, http.StatusBadRequest, "invalid_include", "Invalid include", "invalid include"},
+	{errInvalidUnits, http.StatusBadRequest, "invalid_units", "Invalid units", "invalid units"},
+	{errInvalidValue, http.StatusBadRequest, "invalid_value", "Invalid value", "invalid value"},
+	{errInvalidBody, http.StatusBadRequest, "invalid_body", "Invalid request body", "invalid request body"},
+	{openapi.ErrInvalidRequest, http.StatusBadRequest, "validation", "Validation failed", "invalid request"},
+	{errConvertBatchTooLong, http.StatusBadRequest, "too_many_values", "Too many values", "too many values, maximum is 1000"},
+	{errCEPBatchTooLong, http.StatusBadRequest, "too_many_ceps", "Too many zipcodes", "too many ceps"},
+	{errInvalidFormat, http.StatusBadRequest, "invalid_format", "Invalid format", "invalid format"},
+	{errNotAcceptable, http.StatusNotAcceptable, "not_acceptable", "Not acceptable", "not acceptable"},
+	{errFormatUnsupported, http.StatusNotAcceptable, "format_unsupported", "Format not supported", "format not supported for this response"},
+	{errUnknownProblemType, http.StatusNotFound, "problem_type_not_found", "Problem type not found", "unknown problem type"},
+	{alerts.ErrInvalidRule, http.StatusBadRequest, "invalid_alert", "Invalid alert rule", "invalid alert rule"},
+	{alerts.ErrRuleNotFound, http.StatusNotFound, "alert_not_found", "Alert not found", "can not find alert"},
+	{alerts.ErrTooManyRules, http.StatusConflict, "too_many_alerts", "Too many alerts", "alert limit reached"},
+	{errMissingAPIKey, http.StatusUnauthorized, "missing_api_key", "API key required", "missing api key"},
+	{apikeys.ErrInvalidKey, http.StatusUnauthorized, "invalid_api_key", "Invalid API key", "invalid api key"},
+	{apikeys.ErrQuotaExceeded, http.StatusTooManyRequests, "quota_exceeded", "Quota exceeded", "api key quota exceeded"},
+	{errAdminUnauthorized, http.StatusUnauthorized, "admin_unauthorized", "Admin token required", "invalid admin token"},
+	{apikeys.ErrInvalidQuota, http.StatusBadRequest, "invalid_quota", "Invalid quota", "invalid quota"},
+	{apikeys.ErrKeyNotFound, http.StatusNotFound, "api_key_not_found", "API key not found", "can not find api key"},
+}
+
+// errorCatalog lista os erros da API: os próprios da API HTTP e os dos serviços, de
+// services.ErrorCatalog, que o gRPC, o GraphQL e o WebSocket também usam. Os códigos são
+// estáveis: um código publicado não muda de significado nem é reutilizado.
+var errorCatalog = slices.Concat(httpErrors, serviceErrors())
+
+// internalError é a entrada dos erros fora do catálogo
+var internalError = serviceEntry(services.InternalError)
+
+// serviceErrors converte services.ErrorCatalog em entradas do catálogo
+func serviceErrors() []catalogEntry {
+	entries := make([]catalogEntry, len(services.ErrorCatalog))
+	for i, info := range services.ErrorCatalog {
+		entries[i] = serviceEntry(info)
+	}
+	return entries
+}
+
+func serviceEntry(info services.ErrorInfo) catalogEntry {
+	return catalogEntry{target: info.Target, status: info.Status, code: info.Code, title: info.Title, message: info.Message}
+}
+
+// lookupError encontra a entrada do catálogo correspondente ao erro
+func lookupError(err error) catalogEntry {
+	for _, entry := range httpErrors {
+		if errors.Is(err, entry.target) {
+			return entry
+		}
+	}
+	return serviceEntry(services.DescribeError(err))
+}
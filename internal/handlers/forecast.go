@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Alcance aceito para o parâmetro days. O limite superior é o da Open-Meteo; os demais
+// provedores devolvem apenas os dias que cobrem.
+const (
+	defaultForecastDays = 3
+	maxForecastDays     = 16
+)
+
+// GetForecast busca a previsão de um CEP para os próximos dias
+func (h *TemperatureHandler) GetForecast(c *gin.Context) {
+	cep := c.Param("cep")
+
+	// Validar CEP
+	if !h.cepService.ValidateCEP(cep) {
+		writeError(c, invalidCEP(cep))
+		return
+	}
+
+	options, err := parseForecastOptions(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	ctx, cancel := h.budget.requestContext(c.Request.Context())
+	defer cancel()
+
+	// Buscar localização do CEP
+	location, ok := h.lookupLocation(c, ctx, cep)
+	if !ok {
+		return
+	}
+
+	// Buscar previsão
+	forecast, err := h.weatherService.GetForecast(ctx, services.WeatherQueryFor(location), options)
+	if err != nil {
+		h.writeServiceError(c, err)
+		return
+	}
+	setLocationMatchHeaders(c, forecast.Location, forecast.Match)
+
+	response := models.ForecastResponse{Days: make([]models.ForecastDayResponse, 0, len(forecast.Days))}
+	for _, day := range forecast.Days {
+		dayResponse := models.ForecastDayResponse{
+			Date: day.Date,
+			Min:  h.convert(day.MinC),
+			Max:  h.convert(day.MaxC),
+			Avg:  h.convert(day.AvgC),
+		}
+		for _, hour := range day.Hours {
+			dayResponse.Hourly = append(dayResponse.Hourly, models.ForecastHourResponse{
+				Time:                hour.Time,
+				TemperatureResponse: h.convert(hour.TempC),
+			})
+		}
+		response.Days = append(response.Days, dayResponse)
+	}
+
+	writeResponse(c, http.StatusOK, response)
+}
+
+// parseForecastOptions lê os parâmetros days (1 a 16, padrão 3) e hourly (padrão false)
+func parseForecastOptions(c *gin.Context) (services.ForecastOptions, error) {
+	options := services.ForecastOptions{Days: defaultForecastDays}
+
+	if value, ok := c.GetQuery("days"); ok {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > maxForecastDays {
+			return options, withDetail(errInvalidForecastDays, "days must be an integer between 1 and %d", maxForecastDays)
+		}
+		options.Days = days
+	}
+
+	if value, ok := c.GetQuery("hourly"); ok {
+		hourly, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, withDetail(errInvalidHourly, "hourly must be a boolean")
+		}
+		options.Hourly = hourly
+	}
+
+	return options, nil
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func performForecast(handler *TemperatureHandler, cep, rawQuery string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.GET("/forecast/:cep", handler.GetForecast)
+
+	req, _ := http.NewRequest("GET", "/forecast/"+cep+"?"+rawQuery, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTemperatureHandler_GetForecast_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	query := models.WeatherQuery{City: "São Paulo", State: "SP", IBGE: "3550308"}
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo",
+		UF:         "SP",
+		IBGE:       "3550308",
+	}, nil)
+	mockWeatherService.On("GetForecast", mock.Anything, query, services.ForecastOptions{Days: 2, Hourly: true}).Return(&models.ForecastResult{
+		Days: []models.ForecastDay{
+			{Date: "2025-01-10", MinC: 18, MaxC: 28, AvgC: 23, Hours: []models.ForecastHour{{Time: "2025-01-10 00:00", TempC: 20}}},
+			{Date: "2025-01-11", MinC: 19, MaxC: 30, AvgC: 24.5},
+		},
+		Location: models.WeatherLocation{Name: "São Paulo", Region: "Sao Paulo", Country: "Brazil"},
+		Match:    models.LocationMatch{Status: models.LocationMatched, Strategy: "coordinates", Attempts: 1},
+	}, nil)
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+
+	w := performForecast(handler, "01310100", "days=2&hourly=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.LocationMatched, w.Header().Get("X-Weather-Location-Match"))
+
+	var response models.ForecastResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Days, 2)
+	assert.Equal(t, "2025-01-10", response.Days[0].Date)
+	assert.Equal(t, models.TemperatureResponse{TempC: 18, TempF: 64.4, TempK: 291.15}, response.Days[0].Min)
+	assert.Equal(t, models.TemperatureResponse{TempC: 28, TempF: 82.4, TempK: 301.15}, response.Days[0].Max)
+	assert.Equal(t, 23.0, response.Days[0].Avg.TempC)
+	assert.Equal(t, []models.ForecastHourResponse{
+		{Time: "2025-01-10 00:00", TemperatureResponse: models.TemperatureResponse{TempC: 20, TempF: 68, TempK: 293.15}},
+	}, response.Days[0].Hourly)
+	assert.Nil(t, response.Days[1].Hourly)
+
+	mockCEPService.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestTemperatureHandler_GetForecast_DefaultOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP"}, nil)
+	mockWeatherService.On("GetForecast", mock.Anything, mock.Anything, services.ForecastOptions{Days: defaultForecastDays}).
+		Return(&models.ForecastResult{}, nil)
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+
+	w := performForecast(handler, "01310100", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"days": []}`, w.Body.String())
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestTemperatureHandler_GetForecast_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		cep             string
+		rawQuery        string
+		validCEP        bool
+		cepErr          error
+		forecastErr     error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "CEP inválido",
+			cep:             "123",
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedMessage: "invalid zipcode",
+		},
+		{
+			name:            "dias fora do intervalo",
+			cep:             "01310100",
+			rawQuery:        "days=17",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid days",
+		},
+		{
+			name:            "dias não numérico",
+			cep:             "01310100",
+			rawQuery:        "days=três",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid days",
+		},
+		{
+			name:            "hourly inválido",
+			cep:             "01310100",
+			rawQuery:        "hourly=talvez",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid hourly",
+		},
+		{
+			name:            "CEP não encontrado",
+			cep:             "99999999",
+			validCEP:        true,
+			cepErr:          services.ErrCEPNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "can not find zipcode",
+		},
+		{
+			name:            "provedor sem previsão",
+			cep:             "01310100",
+			validCEP:        true,
+			forecastErr:     services.ErrForecastUnsupported,
+			expectedStatus:  http.StatusNotImplemented,
+			expectedMessage: "forecast not supported by weather provider",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+
+			mockCEPService.On("ValidateCEP", tt.cep).Return(tt.validCEP)
+			if tt.cepErr != nil {
+				mockCEPService.On("GetLocation", mock.Anything, tt.cep).Return(nil, tt.cepErr)
+			} else {
+				mockCEPService.On("GetLocation", mock.Anything, tt.cep).Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP"}, nil)
+			}
+			mockWeatherService.On("GetForecast", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.forecastErr)
+
+			handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+
+			w := performForecast(handler, tt.cep, tt.rawQuery)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMessage, response["message"])
+		})
+	}
+}
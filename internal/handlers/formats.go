@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+)
+
+// jsonField é um campo de um objeto JSON decodificado com a ordem preservada
+type jsonField struct {
+	key   string
+	value any
+}
+
+// jsonObject é um objeto JSON com os campos na ordem em que foram serializados. Os demais
+// valores ficam como []any, string, json.Number, bool ou nil.
+type jsonObject []jsonField
+
+// decodeOrdered decodifica JSON preservando a ordem dos campos, para que XML, CSV e
+// MessagePack sigam a mesma ordem da resposta JSON
+func decodeOrdered(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeValue(decoder)
+}
+
+func decodeValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := jsonObject{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, jsonField{key: key.(string), value: value})
+		}
+		_, err := decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	case json.Delim('}'), json.Delim(']'):
+		return nil, errors.New("unexpected JSON delimiter")
+	default:
+		return token, nil
+	}
+}
+
+// scalarText escreve um valor escalar como texto, com os números na mesma forma do JSON
+func scalarText(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	default:
+		return ""
+	}
+}
+
+// xmlArrayItem nomeia os elementos de uma lista: {"days": [...]} vira <days><item>...</item></days>
+const xmlArrayItem = "item"
+
+// encodeXML escreve o valor sob o elemento raiz, com um elemento por campo
+func encodeXML(root string, value any) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	writeXMLElement(encoder, root, value)
+	_ = encoder.Flush()
+	return buf.Bytes()
+}
+
+func writeXMLElement(encoder *xml.Encoder, name string, value any) {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	_ = encoder.EncodeToken(start)
+	switch value := value.(type) {
+	case jsonObject:
+		for _, field := range value {
+			writeXMLElement(encoder, field.key, field.value)
+		}
+	case []any:
+		for _, item := range value {
+			writeXMLElement(encoder, xmlArrayItem, item)
+		}
+	default:
+		_ = encoder.EncodeToken(xml.CharData(scalarText(value)))
+	}
+	_ = encoder.EncodeToken(start.End())
+}
+
+// encodeCSV achata o valor em linhas: objetos aninhados viram colunas com os nomes unidos
+// por ponto (min.temp_C) e cada item de uma lista vira uma linha, repetindo os campos de
+// fora dela. Os itens da lista de primeiro nível não levam o nome dela nas colunas.
+func encodeCSV(value any) []byte {
+	rows := flattenRows(value, "", true)
+
+	var columns []string
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, cell := range row {
+			if !seen[cell.key] {
+				seen[cell.key] = true
+				columns = append(columns, cell.key)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write(columns)
+	for _, row := range rows {
+		values := make(map[string]string, len(row))
+		for _, cell := range row {
+			values[cell.key] = scalarText(cell.value)
+		}
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = values[column]
+		}
+		_ = writer.Write(record)
+	}
+	writer.Flush()
+	return buf.Bytes()
+}
+
+// flattenRows devolve as linhas do valor. Um objeto combina as linhas de cada campo; as
+// respostas da API têm no máximo uma lista por nível, então a combinação não se multiplica.
+func flattenRows(value any, prefix string, root bool) [][]jsonField {
+	switch value := value.(type) {
+	case jsonObject:
+		rows := [][]jsonField{{}}
+		for _, field := range value {
+			fieldRows := flattenRows(field.value, joinColumn(prefix, field.key), false)
+			if _, isArray := field.value.([]any); isArray && root {
+				fieldRows = flattenRows(field.value, prefix, false)
+			}
+			rows = combineRows(rows, fieldRows)
+		}
+		return rows
+	case []any:
+		var rows [][]jsonField
+		for _, item := range value {
+			rows = append(rows, flattenRows(item, prefix, false)...)
+		}
+		if len(rows) == 0 {
+			return [][]jsonField{{}}
+		}
+		return rows
+	default:
+		return [][]jsonField{{{key: prefix, value: value}}}
+	}
+}
+
+func combineRows(left, right [][]jsonField) [][]jsonField {
+	combined := make([][]jsonField, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			row := make([]jsonField, 0, len(l)+len(r))
+			combined = append(combined, append(append(row, l...), r...))
+		}
+	}
+	return combined
+}
+
+func joinColumn(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// msgpackMap é codificado em MessagePack como um mapa, com as chaves na ordem do slice
+// (chave, valor, chave, valor...)
+type msgpackMap []any
+
+// MapBySlice indica ao codec que o slice representa um mapa
+func (msgpackMap) MapBySlice() {}
+
+// msgpackValue converte o valor decodificado para a codificação MessagePack, com os números
+// inteiros como inteiros e os demais como float64
+func msgpackValue(value any) any {
+	switch value := value.(type) {
+	case jsonObject:
+		result := make(msgpackMap, 0, 2*len(value))
+		for _, field := range value {
+			result = append(result, field.key, msgpackValue(field.value))
+		}
+		return result
+	case []any:
+		result := make([]any, len(value))
+		for i, item := range value {
+			result[i] = msgpackValue(item)
+		}
+		return result
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			return integer
+		}
+		float, _ := value.Float64()
+		return float
+	default:
+		return value
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limites do endpoint de histórico. Cada página gera uma única consulta ao provedor, por isso
+// o tamanho máximo da página acompanha o maior intervalo aceito pela WeatherAPI (31 dias).
+const (
+	maxHistoryDays         = 366
+	defaultHistoryPageSize = 31
+	maxHistoryPageSize     = 31
+)
+
+// historyRequest representa os parâmetros já validados de uma consulta de histórico
+type historyRequest struct {
+	from     time.Time
+	to       time.Time
+	page     int
+	pageSize int
+}
+
+// GetHistory busca as temperaturas diárias de um CEP em um intervalo de datas passadas
+func (h *TemperatureHandler) GetHistory(c *gin.Context) {
+	cep := c.Param("cep")
+
+	// Validar CEP
+	if !h.cepService.ValidateCEP(cep) {
+		writeError(c, invalidCEP(cep))
+		return
+	}
+
+	request, err := parseHistoryRequest(c, time.Now())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	totalDays := int(request.to.Sub(request.from).Hours()/24) + 1
+	response := models.HistoryResponse{
+		Days: []models.HistoryDayResponse{},
+		Pagination: models.Pagination{
+			Page:       request.page,
+			PageSize:   request.pageSize,
+			TotalDays:  totalDays,
+			TotalPages: (totalDays + request.pageSize - 1) / request.pageSize,
+		},
+	}
+
+	ctx, cancel := h.budget.requestContext(c.Request.Context())
+	defer cancel()
+
+	// Buscar localização do CEP
+	location, ok := h.lookupLocation(c, ctx, cep)
+	if !ok {
+		return
+	}
+
+	// Página além do intervalo: nada a consultar na API de clima
+	if request.page > response.Pagination.TotalPages {
+		writeResponse(c, http.StatusOK, response)
+		return
+	}
+
+	// Buscar apenas os dias da página solicitada
+	from := request.from.AddDate(0, 0, (request.page-1)*request.pageSize)
+	to := from.AddDate(0, 0, request.pageSize-1)
+	if to.After(request.to) {
+		to = request.to
+	}
+
+	history, err := h.weatherService.GetHistory(ctx, services.WeatherQueryFor(location), services.HistoryOptions{From: from, To: to})
+	if err != nil {
+		h.writeServiceError(c, err)
+		return
+	}
+	setLocationMatchHeaders(c, history.Location, history.Match)
+
+	for _, day := range history.Days {
+		response.Days = append(response.Days, models.HistoryDayResponse{
+			Date: day.Date,
+			Min:  h.convert(day.MinC),
+			Max:  h.convert(day.MaxC),
+			Avg:  h.convert(day.AvgC),
+		})
+	}
+
+	writeResponse(c, http.StatusOK, response)
+}
+
+// parseHistoryRequest valida o intervalo (from e to obrigatórios, anteriores a hoje e com até
+// 366 dias) e a paginação (page a partir de 1, page_size de 1 a 31)
+func parseHistoryRequest(c *gin.Context, now time.Time) (historyRequest, error) {
+	request := historyRequest{page: 1, pageSize: defaultHistoryPageSize}
+
+	from, errFrom := time.Parse(time.DateOnly, c.Query("from"))
+	to, errTo := time.Parse(time.DateOnly, c.Query("to"))
+	if errFrom != nil {
+		return request, withDetail(errInvalidDate, "from %q", c.Query("from"))
+	}
+	if errTo != nil {
+		return request, withDetail(errInvalidDate, "to %q", c.Query("to"))
+	}
+
+	today, _ := time.Parse(time.DateOnly, now.Format(time.DateOnly))
+	if from.After(to) || !to.Before(today) {
+		return request, withDetail(errInvalidDateRange, "from must not be after to, and to must be before today")
+	}
+	if to.Sub(from) >= maxHistoryDays*24*time.Hour {
+		return request, withDetail(errDateRangeTooLong, "%s to %s", c.Query("from"), c.Query("to"))
+	}
+	request.from, request.to = from, to
+
+	if value, ok := c.GetQuery("page"); ok {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return request, withDetail(errInvalidPagination, "page must be a positive integer")
+		}
+		request.page = page
+	}
+
+	if value, ok := c.GetQuery("page_size"); ok {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxHistoryPageSize {
+			return request, withDetail(errInvalidPagination, "page_size must be an integer between 1 and %d", maxHistoryPageSize)
+		}
+		request.pageSize = pageSize
+	}
+
+	return request, nil
+}
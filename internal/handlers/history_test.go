@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func performHistory(handler *TemperatureHandler, cep, rawQuery string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.GET("/history/:cep", handler.GetHistory)
+
+	req, _ := http.NewRequest("GET", "/history/"+cep+"?"+rawQuery, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse(time.DateOnly, value)
+	return parsed
+}
+
+func TestTemperatureHandler_GetHistory_Pagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP"}, nil)
+	// Intervalo de 5 dias em páginas de 2: a segunda página cobre os dias 3 e 4
+	mockWeatherService.On("GetHistory", mock.Anything, models.WeatherQuery{City: "São Paulo", State: "SP"},
+		services.HistoryOptions{From: date("2024-01-03"), To: date("2024-01-04")}).
+		Return(&models.HistoryResult{Days: []models.HistoryDay{
+			{Date: "2024-01-03", MinC: 18, MaxC: 28, AvgC: 23},
+			{Date: "2024-01-04", MinC: 19, MaxC: 30, AvgC: 24},
+		}}, nil)
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+
+	w := performHistory(handler, "01310100", "from=2024-01-01&to=2024-01-05&page=2&page_size=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.HistoryResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.Pagination{Page: 2, PageSize: 2, TotalDays: 5, TotalPages: 3}, response.Pagination)
+	assert.Len(t, response.Days, 2)
+	assert.Equal(t, "2024-01-03", response.Days[0].Date)
+	assert.Equal(t, models.TemperatureResponse{TempC: 18, TempF: 64.4, TempK: 291.15}, response.Days[0].Min)
+
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestTemperatureHandler_GetHistory_LastPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP"}, nil)
+	mockWeatherService.On("GetHistory", mock.Anything, mock.Anything,
+		services.HistoryOptions{From: date("2024-01-05"), To: date("2024-01-05")}).
+		Return(&models.HistoryResult{}, nil)
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+
+	w := performHistory(handler, "01310100", "from=2024-01-01&to=2024-01-05&page=3&page_size=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockWeatherService.AssertExpectations(t)
+
+	t.Run("página além do intervalo não consulta a API de clima", func(t *testing.T) {
+		w := performHistory(handler, "01310100", "from=2024-01-01&to=2024-01-05&page=4&page_size=2")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"days": [], "pagination": {"page": 4, "page_size": 2, "total_days": 5, "total_pages": 3}}`, w.Body.String())
+		mockWeatherService.AssertNumberOfCalls(t, "GetHistory", 1)
+	})
+}
+
+func TestTemperatureHandler_GetHistory_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		cep             string
+		rawQuery        string
+		validCEP        bool
+		cepErr          error
+		historyErr      error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "CEP inválido",
+			cep:             "123",
+			rawQuery:        "from=2024-01-01&to=2024-01-05",
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedMessage: "invalid zipcode",
+		},
+		{
+			name:            "datas ausentes",
+			cep:             "01310100",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid date, expected YYYY-MM-DD",
+		},
+		{
+			name:            "data em outro formato",
+			cep:             "01310100",
+			rawQuery:        "from=01/01/2024&to=2024-01-05",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid date, expected YYYY-MM-DD",
+		},
+		{
+			name:            "início depois do fim",
+			cep:             "01310100",
+			rawQuery:        "from=2024-01-05&to=2024-01-01",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid date range",
+		},
+		{
+			name:            "data futura",
+			cep:             "01310100",
+			rawQuery:        "from=2024-01-01&to=2999-01-01",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid date range",
+		},
+		{
+			name:            "intervalo longo demais",
+			cep:             "01310100",
+			rawQuery:        "from=2022-01-01&to=2023-01-02",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "date range exceeds 366 days",
+		},
+		{
+			name:            "página inválida",
+			cep:             "01310100",
+			rawQuery:        "from=2024-01-01&to=2024-01-05&page=0",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid pagination",
+		},
+		{
+			name:            "página grande demais",
+			cep:             "01310100",
+			rawQuery:        "from=2024-01-01&to=2024-01-05&page_size=32",
+			validCEP:        true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid pagination",
+		},
+		{
+			name:            "CEP não encontrado",
+			cep:             "99999999",
+			rawQuery:        "from=2024-01-01&to=2024-01-05",
+			validCEP:        true,
+			cepErr:          services.ErrCEPNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "can not find zipcode",
+		},
+		{
+			name:            "provedor sem histórico",
+			cep:             "01310100",
+			rawQuery:        "from=2024-01-01&to=2024-01-05",
+			validCEP:        true,
+			historyErr:      services.ErrHistoryUnsupported,
+			expectedStatus:  http.StatusNotImplemented,
+			expectedMessage: "history not supported by weather provider",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+
+			mockCEPService.On("ValidateCEP", tt.cep).Return(tt.validCEP)
+			if tt.cepErr != nil {
+				mockCEPService.On("GetLocation", mock.Anything, tt.cep).Return(nil, tt.cepErr)
+			} else {
+				mockCEPService.On("GetLocation", mock.Anything, tt.cep).Return(&models.CEPResponse{Localidade: "São Paulo", UF: "SP"}, nil)
+			}
+			mockWeatherService.On("GetHistory", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.historyErr)
+
+			handler := NewTemperatureHandler(mockCEPService, mockWeatherService, services.NewTemperatureService())
+
+			w := performHistory(handler, tt.cep, tt.rawQuery)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMessage, response["message"])
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cabeçalhos e chaves de contexto dos erros em problem+json
+const (
+	mimeProblemJSON  = "application/problem+json"
+	apiVersionHeader = "X-API-Version"
+	requestIDHeader  = "X-Request-ID"
+	requestIDKey     = "request_id"
+	problemTypesPath = "/problems/"
+	maxRequestIDSize = 128
+)
+
+// problemAPIVersion é a primeira versão da API cujos erros são problem+json por padrão
+const problemAPIVersion = 2
+
+// RequestID identifica cada requisição pelo X-Request-ID recebido ou, sem ele, por um novo
+// identificador. O valor volta no cabeçalho da resposta e em request_id do problem+json.
+func RequestID(c *gin.Context) {
+	id := c.GetHeader(requestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	c.Set(requestIDKey, id)
+	c.Header(requestIDHeader, id)
+	c.Next()
+}
+
+// validRequestID aceita identificadores curtos de letras, dígitos, ponto, hífen e sublinhado,
+// para que o valor recebido possa ser repetido nos cabeçalhos e nos logs com segurança
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDSize {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// wantsProblem indica se o cliente optou pelos erros em problem+json: pelo Accept ou pela
+// versão da API em X-API-Version. Os erros nos demais formatos mantêm o corpo legado.
+func wantsProblem(c *gin.Context) bool {
+	if responseFormat(c) != formatJSON {
+		return false
+	}
+	if version, err := strconv.Atoi(c.GetHeader(apiVersionHeader)); err == nil && version >= problemAPIVersion {
+		return true
+	}
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == mimeProblemJSON && params["q"] != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeProblem responde com o corpo da RFC 7807 da entrada do catálogo
+func writeProblem(c *gin.Context, entry catalogEntry, detail string) {
+	c.Header("Content-Type", mimeProblemJSON)
+	c.JSON(entry.status, problemResponse(c, entry, detail))
+}
+
+// problemResponse monta o corpo da RFC 7807 da entrada do catálogo para a requisição, com a
+// descrição da ocorrência em detail; sem ela, detail é omitido
+func problemResponse(c *gin.Context, entry catalogEntry, detail string) models.ProblemResponse {
+	return models.ProblemResponse{
+		Type:      problemTypesPath + entry.code,
+		Title:     entry.title,
+		Status:    entry.status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      entry.code,
+		RequestID: c.GetString(requestIDKey),
+		Message:   entry.message,
+	}
+}
+
+// GetProblemType descreve um tipo de problema do catálogo, para que o type do problem+json
+// aponte para uma página que existe
+func GetProblemType(c *gin.Context) {
+	code := c.Param("code")
+	for _, entry := range append([]catalogEntry{internalError}, errorCatalog...) {
+		if entry.code == code {
+			c.JSON(http.StatusOK, models.ProblemTypeResponse{
+				Type:   problemTypesPath + entry.code,
+				Title:  entry.title,
+				Status: entry.status,
+				Code:   entry.code,
+			})
+			return
+		}
+	}
+	writeError(c, errUnknownProblemType)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func performProblem(handler *TemperatureHandler, target string, headers map[string]string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.Use(RequestID)
+	router.GET("/temperature/:cep", NegotiateFormat, handler.GetTemperature)
+	router.GET("/problems/:code", GetProblemType)
+
+	req, _ := http.NewRequest("GET", target, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func invalidCEPHandler() *TemperatureHandler {
+	mockCEPService := new(MockCEPService)
+	mockCEPService.On("ValidateCEP", "123").Return(false)
+	return NewTemperatureHandler(mockCEPService, new(MockWeatherService), new(MockTemperatureService))
+}
+
+func TestWriteError_Problem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"Accept problem+json", map[string]string{"Accept": "application/problem+json"}},
+		{"Accept com JSON e problem+json", map[string]string{"Accept": "application/json, application/problem+json"}},
+		{"versão 2 da API", map[string]string{"X-API-Version": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.headers["X-Request-ID"] = "req-42"
+			w := performProblem(invalidCEPHandler(), "/temperature/123", tt.headers)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Equal(t, "req-42", w.Header().Get("X-Request-ID"))
+
+			var response models.ProblemResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, models.ProblemResponse{
+				Type:      "/problems/invalid_zipcode",
+				Title:     "Invalid zipcode",
+				Status:    http.StatusUnprocessableEntity,
+				Detail:    `cep "123" must have 8 digits`,
+				Instance:  "/temperature/123",
+				Code:      "invalid_zipcode",
+				RequestID: "req-42",
+				Message:   "invalid zipcode",
+			}, response)
+		})
+	}
+}
+
+func TestWriteError_Legacy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		target      string
+		headers     map[string]string
+		contentType string
+		body        string
+	}{
+		{"sem opção", "/temperature/123", nil, "application/json; charset=utf-8", `{"message":"invalid zipcode","detail":"cep \"123\" must have 8 digits"}`},
+		{"versão 1 da API", "/temperature/123", map[string]string{"X-API-Version": "1"}, "application/json; charset=utf-8", `{"message":"invalid zipcode","detail":"cep \"123\" must have 8 digits"}`},
+		{"problem+json recusado", "/temperature/123", map[string]string{"Accept": "application/json, application/problem+json;q=0"}, "application/json; charset=utf-8", `{"message":"invalid zipcode","detail":"cep \"123\" must have 8 digits"}`},
+		{"formato XML", "/temperature/123?format=xml", map[string]string{"X-API-Version": "2"}, "application/xml; charset=utf-8",
+			`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<error><message>invalid zipcode</message><detail>cep &#34;123&#34; must have 8 digits</detail></error>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performProblem(invalidCEPHandler(), tt.target, tt.headers)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("identificador recebido", func(t *testing.T) {
+		w := performProblem(invalidCEPHandler(), "/temperature/123", map[string]string{"X-Request-ID": "abc_123.x-y"})
+		assert.Equal(t, "abc_123.x-y", w.Header().Get("X-Request-ID"))
+	})
+
+	t.Run("identificador inválido é substituído", func(t *testing.T) {
+		w := performProblem(invalidCEPHandler(), "/temperature/123", map[string]string{"X-Request-ID": "a b\x01"})
+		assert.Len(t, w.Header().Get("X-Request-ID"), 32)
+	})
+
+	t.Run("sem identificador", func(t *testing.T) {
+		first := performProblem(invalidCEPHandler(), "/temperature/123", nil)
+		second := performProblem(invalidCEPHandler(), "/temperature/123", nil)
+		assert.Len(t, first.Header().Get("X-Request-ID"), 32)
+		assert.NotEqual(t, first.Header().Get("X-Request-ID"), second.Header().Get("X-Request-ID"))
+	})
+}
+
+func TestWriteError_RequestValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, err := openapi.NewValidator(openapi.WithRequestValidation(true), openapi.WithErrorWriter(WriteError))
+	require.NoError(t, err)
+	router := gin.New()
+	router.Use(RequestID, validator.Middleware)
+	router.GET("/forecast/:cep", func(c *gin.Context) {
+		t.Fatal("a requisição fora do contrato não chega ao handler")
+	})
+
+	req, _ := http.NewRequest("GET", "/forecast/01310100?days=17", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response models.ProblemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "validation", response.Code)
+	assert.Equal(t, "invalid request", response.Message)
+	assert.Equal(t, "invalid query parameter days: number must be at most 16", response.Detail)
+}
+
+func TestGetProblemType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := performProblem(invalidCEPHandler(), "/problems/upstream_timeout", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"type":"/problems/upstream_timeout","title":"Upstream timeout","status":504,"code":"upstream_timeout"}`, w.Body.String())
+
+	w = performProblem(invalidCEPHandler(), "/problems/unknown", map[string]string{"Accept": "application/problem+json"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response models.ProblemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "problem_type_not_found", response.Code)
+}
+
+func TestErrorCatalog_StableCodes(t *testing.T) {
+	doc, err := openapi.Spec()
+	require.NoError(t, err)
+	documented := doc.Components.Schemas["ProblemCode"].Value.Enum
+
+	seen := map[string]bool{}
+	for _, entry := range append([]catalogEntry{internalError}, errorCatalog...) {
+		assert.False(t, seen[entry.code], "código repetido: %s", entry.code)
+		seen[entry.code] = true
+		assert.Contains(t, documented, entry.code, "código fora do documento OpenAPI")
+		assert.NotEmpty(t, entry.title, entry.code)
+	}
+	assert.Len(t, documented, len(seen))
+}
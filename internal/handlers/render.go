@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/pb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/protobuf/proto"
+)
+
+// Formatos de resposta, escolhidos via ?format= ou pelo cabeçalho Accept
+const (
+	formatJSON     = "json"
+	formatXML      = "xml"
+	formatCSV      = "csv"
+	formatMsgPack  = "msgpack"
+	formatProtobuf = "protobuf"
+)
+
+// formatKey guarda no contexto da requisição o formato negociado
+const formatKey = "response_format"
+
+var knownFormats = map[string]bool{
+	formatJSON:     true,
+	formatXML:      true,
+	formatCSV:      true,
+	formatMsgPack:  true,
+	formatProtobuf: true,
+}
+
+// mediaTypes lista os tipos aceitos no cabeçalho Accept, na ordem de preferência do servidor
+var mediaTypes = []string{
+	gin.MIMEJSON,
+	mimeProblemJSON,
+	gin.MIMEXML,
+	gin.MIMEXML2,
+	"text/csv",
+	"application/msgpack",
+	"application/x-msgpack",
+	"application/x-protobuf",
+	"application/protobuf",
+}
+
+var mediaTypeFormats = map[string]string{
+	gin.MIMEJSON:             formatJSON,
+	mimeProblemJSON:          formatJSON,
+	gin.MIMEXML:              formatXML,
+	gin.MIMEXML2:             formatXML,
+	"text/csv":               formatCSV,
+	"application/msgpack":    formatMsgPack,
+	"application/x-msgpack":  formatMsgPack,
+	"application/x-protobuf": formatProtobuf,
+	"application/protobuf":   formatProtobuf,
+}
+
+// NegotiateFormat escolhe o formato da resposta antes do handler, para que um formato
+// inválido ou inaceitável seja recusado sem consultar os serviços externos
+func NegotiateFormat(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		writeError(c, err)
+		c.Abort()
+		return
+	}
+	c.Set(formatKey, format)
+	c.Next()
+}
+
+// negotiateFormat lê o formato de ?format=, que tem precedência, ou do cabeçalho Accept.
+// Sem nenhum dos dois, ou com Accept genérico, a resposta é JSON.
+func negotiateFormat(c *gin.Context) (string, error) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		if !knownFormats[format] {
+			return "", withDetail(errInvalidFormat, "unknown format %q", format)
+		}
+		return format, nil
+	}
+
+	if c.GetHeader("Accept") == "" {
+		return formatJSON, nil
+	}
+	mediaType := c.NegotiateFormat(mediaTypes...)
+	if mediaType == "" {
+		return "", errNotAcceptable
+	}
+	return mediaTypeFormats[mediaType], nil
+}
+
+// responseFormat devolve o formato negociado pelo middleware ou, sem ele, negocia na hora.
+// Uma negociação que falha aqui cai para JSON, já que a resposta precisa ser escrita.
+func responseFormat(c *gin.Context) string {
+	if format := c.GetString(formatKey); format != "" {
+		return format
+	}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		return formatJSON
+	}
+	return format
+}
+
+// writeResponse escreve o corpo no formato negociado. Os nomes dos campos são sempre os
+// da resposta JSON; os demais formatos são derivados dela.
+func writeResponse(c *gin.Context, status int, body any) {
+	format := responseFormat(c)
+	if format == formatJSON {
+		c.JSON(status, body)
+		return
+	}
+	if format == formatProtobuf {
+		writeProtobuf(c, status, body)
+		return
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	tree, err := decodeOrdered(encoded)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	switch format {
+	case formatXML:
+		root := "response"
+		if _, ok := body.(models.ErrorResponse); ok {
+			root = "error"
+		}
+		c.Render(status, render.Data{ContentType: "application/xml; charset=utf-8", Data: encodeXML(root, tree)})
+	case formatCSV:
+		c.Render(status, render.Data{ContentType: "text/csv; charset=utf-8", Data: encodeCSV(tree)})
+	case formatMsgPack:
+		c.Render(status, render.MsgPack{Data: msgpackValue(tree)})
+	}
+}
+
+// writeProtobuf escreve as respostas que têm mensagem em api/proto/temperature.proto.
+// As demais são recusadas com 406, com o corpo de erro também em protobuf.
+func writeProtobuf(c *gin.Context, status int, body any) {
+	var message proto.Message
+	switch body := body.(type) {
+	case models.TemperatureResponse:
+		message = &pb.TemperatureResponse{Temp_C: body.TempC, Temp_F: body.TempF, Temp_K: body.TempK}
+	case models.ErrorResponse:
+		message = &pb.ErrorResponse{Message: body.Message, Detail: body.Detail}
+	default:
+		code, text := errorResponse(errFormatUnsupported)
+		status, message = code, &pb.ErrorResponse{Message: text}
+	}
+	c.ProtoBuf(status, message)
+}
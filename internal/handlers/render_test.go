@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cep-temperatura/internal/pb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+)
+
+func performNegotiated(handler *TemperatureHandler, cep, rawQuery, accept string) *httptest.ResponseRecorder {
+	router := contractRouter()
+	router.GET("/temperature/:cep", NegotiateFormat, handler.GetTemperature)
+
+	req, _ := http.NewRequest("GET", "/temperature/"+cep+"?"+rawQuery, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNegotiateFormat_Temperature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("JSON por padrão", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "", "*/*")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `{"temp_C":25,"temp_F":77,"temp_K":298.15}`, w.Body.String())
+	})
+
+	t.Run("XML via Accept", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "", "application/xml")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<response><temp_C>25</temp_C><temp_F>77</temp_F><temp_K>298.15</temp_K></response>`, w.Body.String())
+	})
+
+	t.Run("CSV via format, com precedência sobre Accept", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "format=csv&units=K,C", "application/xml")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "temp_K,temp_C\n298.15,25\n", w.Body.String())
+	})
+
+	t.Run("CSV com blocos aninhados", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "format=csv&include=wind,uv", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "temp_C,temp_F,temp_K,wind.speed_kph,wind.degree,wind.direction,uv,last_updated\n"+
+			"25,77,298.15,11.2,140,SE,7,2025-01-10 15:00\n", w.Body.String())
+	})
+
+	t.Run("MessagePack", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "", "application/x-msgpack")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
+
+		handle := &codec.MsgpackHandle{}
+		handle.MapType = reflect.TypeOf(map[string]any(nil))
+		var response map[string]any
+		assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&response))
+		assert.EqualValues(t, 25, response["temp_C"])
+		assert.EqualValues(t, 77, response["temp_F"])
+		assert.Equal(t, 298.15, response["temp_K"])
+	})
+
+	t.Run("Protobuf", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "format=protobuf", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+
+		var response pb.TemperatureResponse
+		assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 25.0, response.GetTemp_C())
+		assert.Equal(t, 77.0, response.GetTemp_F())
+		assert.Equal(t, 298.15, response.GetTemp_K())
+	})
+}
+
+func TestNegotiateFormat_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("erro em XML", func(t *testing.T) {
+		handler := newConditionsHandler()
+		handler.cepService.(*MockCEPService).On("ValidateCEP", "123").Return(false)
+
+		w := performNegotiated(handler, "123", "format=xml", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<error><message>invalid zipcode</message><detail>cep &#34;123&#34; must have 8 digits</detail></error>`, w.Body.String())
+	})
+
+	t.Run("erro em Protobuf", func(t *testing.T) {
+		handler := newConditionsHandler()
+		handler.cepService.(*MockCEPService).On("ValidateCEP", "123").Return(false)
+
+		w := performNegotiated(handler, "123", "", "application/x-protobuf")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response pb.ErrorResponse
+		assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid zipcode", response.GetMessage())
+		assert.Equal(t, `cep "123" must have 8 digits`, response.GetDetail())
+	})
+
+	t.Run("erro em CSV", func(t *testing.T) {
+		handler := newConditionsHandler()
+		handler.cepService.(*MockCEPService).On("ValidateCEP", "123").Return(false)
+
+		w := performNegotiated(handler, "123", "format=csv", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "message,detail\ninvalid zipcode,\"cep \"\"123\"\" must have 8 digits\"\n", w.Body.String())
+	})
+
+	t.Run("erro em MessagePack", func(t *testing.T) {
+		handler := newConditionsHandler()
+		handler.cepService.(*MockCEPService).On("ValidateCEP", "123").Return(false)
+
+		w := performNegotiated(handler, "123", "format=msgpack", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response map[string]string
+		assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&response))
+		assert.Equal(t, map[string]string{"message": "invalid zipcode", "detail": `cep "123" must have 8 digits`}, response)
+	})
+
+	t.Run("resposta sem esquema Protobuf", func(t *testing.T) {
+		w := performNegotiated(newConditionsHandler(), "01310100", "format=protobuf&include=uv", "")
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+
+		var response pb.ErrorResponse
+		assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "format not supported for this response", response.GetMessage())
+		assert.Empty(t, response.GetDetail())
+	})
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		accept   string
+		status   int
+		message  string
+	}{
+		{"formato desconhecido", "format=yaml", "", http.StatusBadRequest, "invalid format"},
+		{"Accept sem formato suportado", "", "text/html", http.StatusNotAcceptable, "not acceptable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// O handler não é chamado: a negociação falha antes de consultar os serviços
+			handler := NewTemperatureHandler(new(MockCEPService), new(MockWeatherService), new(MockTemperatureService))
+
+			w := performNegotiated(handler, "01310100", tt.rawQuery, tt.accept)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.message, response["message"])
+		})
+	}
+}
+
+func TestEncodeCSV_Rows(t *testing.T) {
+	tree, err := decodeOrdered([]byte(`{
+		"days": [
+			{"date": "2024-01-01", "min": {"temp_C": 18}, "hourly": [{"time": "00:00", "temp_C": 19}, {"time": "01:00", "temp_C": 18.5}]},
+			{"date": "2024-01-02", "min": {"temp_C": 17}, "hourly": []}
+		],
+		"pagination": {"page": 1, "total_pages": 3}
+	}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "date,min.temp_C,hourly.time,hourly.temp_C,pagination.page,pagination.total_pages\n"+
+		"2024-01-01,18,00:00,19,1,3\n"+
+		"2024-01-01,18,01:00,18.5,1,3\n"+
+		"2024-01-02,17,,,1,3\n", string(encodeCSV(tree)))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Eventos do stream de temperatura
+const (
+	eventTemperature = "temperature"
+	eventError       = "error"
+)
+
+// defaultStreamHeartbeat é o intervalo máximo sem eventos em um stream de temperatura
+const defaultStreamHeartbeat = 15 * time.Second
+
+// GetTemperatureStream envia a temperatura de um CEP como Server-Sent Events: um evento
+// temperature na primeira consulta, a cada mudança e a cada heartbeat sem mudança. Os streams
+// do mesmo município compartilham a consulta de clima, que para quando o último cliente sai.
+func (h *TemperatureHandler) GetTemperatureStream(c *gin.Context) {
+	cep := c.Param("cep")
+
+	// Validar CEP
+	if !h.cepService.ValidateCEP(cep) {
+		writeError(c, invalidCEP(cep))
+		return
+	}
+
+	// Buscar localização do CEP
+	ctx, cancel := h.budget.requestContext(c.Request.Context())
+	location, ok := h.lookupLocation(c, ctx, cep)
+	cancel()
+	if !ok {
+		return
+	}
+
+	updates, unsubscribe := h.watcher.Subscribe(location)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(h.streamHeartbeat)
+	defer heartbeat.Stop()
+
+	var last *models.TemperatureResponse
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+
+		case update, ok := <-updates:
+			if !ok {
+				return false
+			}
+			if update.Err != nil {
+				c.SSEvent(eventError, streamError(c, update.Err))
+				return true
+			}
+			temperature := h.convert(update.Weather.TempC)
+			if last != nil && *last == temperature {
+				return true
+			}
+			last = &temperature
+			c.SSEvent(eventTemperature, temperature)
+			heartbeat.Reset(h.streamHeartbeat)
+			return true
+
+		case <-heartbeat.C:
+			if last == nil {
+				// Sem leitura ainda: um comentário mantém a conexão aberta nos proxies
+				_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+				return true
+			}
+			c.SSEvent(eventTemperature, *last)
+			return true
+		}
+	})
+}
+
+// streamError monta o corpo do evento de erro no mesmo modelo dos erros da rota
+func streamError(c *gin.Context, err error) any {
+	entry := lookupError(err)
+	if wantsProblem(c) {
+		return problemResponse(c, entry, errorDetail(err))
+	}
+	return models.ErrorResponse{Message: entry.message, Detail: errorDetail(err)}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+// openStream conecta ao stream do CEP e devolve o leitor dos eventos e a função que desconecta
+func openStream(t *testing.T, handler *TemperatureHandler, cep string) (*http.Response, *bufio.Reader, context.CancelFunc) {
+	router := contractRouter()
+	router.GET("/temperature/:cep/stream", handler.GetTemperatureStream)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/temperature/"+cep+"/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body), cancel
+}
+
+// readEvent lê o próximo evento do stream, ignorando os comentários
+func readEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event.name != "":
+			return event
+		case strings.HasPrefix(line, "event:"):
+			event.name = line[len("event:"):]
+		case strings.HasPrefix(line, "data:"):
+			event.data = line[len("data:"):]
+		}
+	}
+}
+
+func newStreamHandler(weatherService *MockWeatherService, interval, heartbeat time.Duration) *TemperatureHandler {
+	mockCEPService := new(MockCEPService)
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("ValidateCEP", "123").Return(false)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo", UF: "SP", IBGE: "3550308",
+	}, nil)
+
+	return NewTemperatureHandler(
+		mockCEPService,
+		weatherService,
+		services.NewTemperatureService(),
+		WithWeatherWatcher(services.NewWeatherWatcher(weatherService, services.WithPollInterval(interval))),
+		WithStreamHeartbeat(heartbeat),
+	)
+}
+
+func TestTemperatureHandler_GetTemperatureStream_Changes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil).Times(3)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(nil, services.ErrUpstreamTimeout).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 30}, nil)
+
+	resp, reader, disconnect := openStream(t, newStreamHandler(weatherService, 10*time.Millisecond, time.Hour), "01310100")
+	defer disconnect()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// As consultas repetidas com a mesma temperatura não geram eventos
+	assert.Equal(t, sseEvent{"temperature", `{"temp_C":25,"temp_F":77,"temp_K":298.15}`}, readEvent(t, reader))
+	assert.Equal(t, sseEvent{"error", `{"message":"upstream timeout"}`}, readEvent(t, reader))
+	assert.Equal(t, sseEvent{"temperature", `{"temp_C":30,"temp_F":86,"temp_K":303.15}`}, readEvent(t, reader))
+}
+
+func TestTemperatureHandler_GetTemperatureStream_Heartbeat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+
+	_, reader, disconnect := openStream(t, newStreamHandler(weatherService, time.Hour, 20*time.Millisecond), "01310100")
+	defer disconnect()
+
+	first := readEvent(t, reader)
+	start := time.Now()
+	assert.Equal(t, first, readEvent(t, reader))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestTemperatureHandler_GetTemperatureStream_Disconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	handler := newStreamHandler(weatherService, 10*time.Millisecond, time.Hour)
+	_, first, disconnectFirst := openStream(t, handler, "01310100")
+	_, second, disconnectSecond := openStream(t, handler, "01310100")
+	readEvent(t, first)
+	readEvent(t, second)
+	assert.Equal(t, 1, handler.watcher.Pollers())
+
+	disconnectFirst()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, handler.watcher.Pollers())
+
+	// A consulta do município para quando o último cliente desconecta
+	disconnectSecond()
+	assert.Eventually(t, func() bool { return handler.watcher.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestTemperatureHandler_GetTemperatureStream_InvalidCEP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	resp, _, disconnect := openStream(t, newStreamHandler(new(MockWeatherService), time.Hour, time.Hour), "123")
+	defer disconnect()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"cep-temperatura/internal/alerts"
 	"cep-temperatura/internal/models"
 	"cep-temperatura/internal/services"
 
@@ -14,6 +19,49 @@ type TemperatureHandler struct {
 	cepService         services.CEPService
 	weatherService     services.WeatherService
 	temperatureService services.TemperatureService
+	budget             RequestBudget
+	batch              BatchLimits
+	watcher            *services.WeatherWatcher
+	streamHeartbeat    time.Duration
+	alerts             *alerts.Evaluator
+}
+
+// HandlerOption personaliza o TemperatureHandler
+type HandlerOption func(*TemperatureHandler)
+
+// WithRequestBudget define o tempo total de cada requisição e a fração dele reservada à consulta de CEP
+func WithRequestBudget(total time.Duration, cepShare float64) HandlerOption {
+	return func(h *TemperatureHandler) {
+		h.budget = RequestBudget{Total: total, CEPShare: cepShare}
+	}
+}
+
+// WithBatchLimits define quantos CEPs um lote aceita e quantas consultas dele correm em paralelo
+func WithBatchLimits(maxCEPs, workers int) HandlerOption {
+	return func(h *TemperatureHandler) {
+		h.batch = BatchLimits{MaxCEPs: maxCEPs, Workers: workers}
+	}
+}
+
+// WithWeatherWatcher define o agendador compartilhado das consultas de clima dos streams
+func WithWeatherWatcher(watcher *services.WeatherWatcher) HandlerOption {
+	return func(h *TemperatureHandler) {
+		h.watcher = watcher
+	}
+}
+
+// WithStreamHeartbeat define o intervalo máximo sem eventos em um stream de temperatura
+func WithStreamHeartbeat(heartbeat time.Duration) HandlerOption {
+	return func(h *TemperatureHandler) {
+		h.streamHeartbeat = heartbeat
+	}
+}
+
+// WithAlertEvaluator define o avaliador que guarda as regras de alerta de /alerts
+func WithAlertEvaluator(evaluator *alerts.Evaluator) HandlerOption {
+	return func(h *TemperatureHandler) {
+		h.alerts = evaluator
+	}
 }
 
 // NewTemperatureHandler cria uma nova instância do handler de temperatura
@@ -21,53 +69,172 @@ func NewTemperatureHandler(
 	cepService services.CEPService,
 	weatherService services.WeatherService,
 	temperatureService services.TemperatureService,
+	opts ...HandlerOption,
 ) *TemperatureHandler {
-	return &TemperatureHandler{
+	h := &TemperatureHandler{
 		cepService:         cepService,
 		weatherService:     weatherService,
 		temperatureService: temperatureService,
+		batch:              BatchLimits{MaxCEPs: defaultBatchMaxCEPs, Workers: defaultBatchWorkers},
+		streamHeartbeat:    defaultStreamHeartbeat,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.watcher == nil {
+		h.watcher = services.NewWeatherWatcher(weatherService)
+	}
+	if h.alerts == nil {
+		h.alerts = alerts.NewEvaluator(weatherService, temperatureService)
+	}
+	return h
+}
+
+// RequestBudget divide o prazo de uma requisição entre a consulta de CEP e a de clima.
+// Com Total zero, as consultas ficam limitadas apenas pela conexão do cliente.
+type RequestBudget struct {
+	Total    time.Duration
+	CEPShare float64
+}
+
+// requestContext aplica o prazo total sobre o contexto da requisição
+func (b RequestBudget) requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if b.Total <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, b.Total)
 }
 
+// cepContext reserva para a consulta de CEP a sua fração do prazo total;
+// o que sobrar fica para a consulta de clima
+func (b RequestBudget) cepContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if b.Total <= 0 || b.CEPShare <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, time.Duration(float64(b.Total)*b.CEPShare))
+}
+
+// statusClientClosedRequest registra nos logs que o cliente desistiu antes da resposta
+const statusClientClosedRequest = 499
+
 // GetTemperature busca a temperatura de um CEP
 func (h *TemperatureHandler) GetTemperature(c *gin.Context) {
 	cep := c.Param("cep")
 
 	// Validar CEP
 	if !h.cepService.ValidateCEP(cep) {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"message": "invalid zipcode",
-		})
+		writeError(c, invalidCEP(cep))
 		return
 	}
 
-	// Buscar localização do CEP
-	location, err := h.cepService.GetLocation(cep)
+	includes, err := parseIncludes(c)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"message": "can not find zipcode",
-		})
+		writeError(c, err)
+		return
+	}
+
+	units, err := services.ParseUnits(c.Query("units"))
+	if err != nil {
+		writeError(c, withDetail(errInvalidUnits, "units %q", c.Query("units")))
+		return
+	}
+
+	ctx, cancel := h.budget.requestContext(c.Request.Context())
+	defer cancel()
+
+	// Buscar localização do CEP
+	location, ok := h.lookupLocation(c, ctx, cep)
+	if !ok {
 		return
 	}
 
 	// Buscar temperatura
-	temperature, err := h.weatherService.GetTemperature(location.Localidade, location.UF)
+	weather, err := h.weatherService.GetTemperature(ctx, services.WeatherQueryFor(location))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "erro ao consultar temperatura",
-		})
+		h.writeServiceError(c, err)
 		return
 	}
+	setLocationMatchHeaders(c, weather.Location, weather.Match)
+	setObservedAt(c, weather.Conditions.ObservedAt)
+
+	// Converter temperaturas e retornar resposta; sem include nem units, o formato é o original
+	switch {
+	case len(includes) > 0:
+		response := h.extendedResponse(location, weather, includes)
+		if len(units) > 0 {
+			response.Readings = h.readings(weather.TempC, units)
+		}
+		writeResponse(c, http.StatusOK, response)
+	case len(units) > 0:
+		writeResponse(c, http.StatusOK, h.readings(weather.TempC, units))
+	default:
+		writeResponse(c, http.StatusOK, h.convert(weather.TempC))
+	}
+}
 
-	// Converter temperaturas
-	fahrenheit, kelvin := h.temperatureService.ConvertTemperatures(temperature)
+// lookupLocation busca a localização do CEP dentro da fração do prazo reservada a ela,
+// respondendo com o erro quando a consulta falha
+func (h *TemperatureHandler) lookupLocation(c *gin.Context, ctx context.Context, cep string) (*models.CEPResponse, bool) {
+	cepCtx, cancel := h.budget.cepContext(ctx)
+	defer cancel()
 
-	// Retornar resposta
-	response := models.TemperatureResponse{
-		TempC: temperature,
+	location, err := h.cepService.GetLocation(cepCtx, cep)
+	if err != nil {
+		h.writeServiceError(c, err)
+		return nil, false
+	}
+	return location, true
+}
+
+// convert expressa uma temperatura em Celsius nas três escalas da resposta
+func (h *TemperatureHandler) convert(celsius float64) models.TemperatureResponse {
+	fahrenheit, kelvin := h.temperatureService.ConvertTemperatures(celsius)
+	return models.TemperatureResponse{
+		TempC: celsius,
 		TempF: fahrenheit,
 		TempK: kelvin,
 	}
+}
+
+// readings expressa uma temperatura em Celsius nas escalas selecionadas via ?units=
+func (h *TemperatureHandler) readings(celsius float64, units []services.Unit) models.TemperatureReadings {
+	readings := make(models.TemperatureReadings, 0, len(units))
+	for _, unit := range units {
+		readings = append(readings, models.TemperatureReading{
+			Unit:  unit.Symbol,
+			Value: h.temperatureService.Convert(celsius, services.UnitCelsius, unit),
+		})
+	}
+	return readings
+}
 
-	c.JSON(http.StatusOK, response)
+// writeServiceError responde com o erro do serviço, a menos que o cliente já tenha desconectado
+func (h *TemperatureHandler) writeServiceError(c *gin.Context, err error) {
+	if c.Request.Context().Err() != nil {
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+	writeError(c, err)
+}
+
+// setLocationMatchHeaders expõe nos cabeçalhos o local resolvido pela API de clima e o resultado
+// da comparação com o local do CEP, sem alterar o corpo da resposta
+func setLocationMatchHeaders(c *gin.Context, location models.WeatherLocation, match models.LocationMatch) {
+	if match.Status == "" {
+		return
+	}
+	c.Header("X-Weather-Location", strings.Join(nonEmpty(location.Name, location.Region, location.Country), ", "))
+	c.Header("X-Weather-Location-Match", match.Status)
+	c.Header("X-Weather-Query-Strategy", match.Strategy)
+	c.Header("X-Weather-Query-Attempts", strconv.Itoa(match.Attempts))
+}
+
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
 }
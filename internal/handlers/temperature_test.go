@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -23,8 +27,8 @@ func (m *MockCEPService) ValidateCEP(cep string) bool {
 	return args.Bool(0)
 }
 
-func (m *MockCEPService) GetLocation(cep string) (*models.CEPResponse, error) {
-	args := m.Called(cep)
+func (m *MockCEPService) GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	args := m.Called(ctx, cep)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -36,9 +40,28 @@ type MockWeatherService struct {
 	mock.Mock
 }
 
-func (m *MockWeatherService) GetTemperature(city, state string) (float64, error) {
-	args := m.Called(city, state)
-	return args.Get(0).(float64), args.Error(1)
+func (m *MockWeatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResult), args.Error(1)
+}
+
+func (m *MockWeatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options services.ForecastOptions) (*models.ForecastResult, error) {
+	args := m.Called(ctx, query, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ForecastResult), args.Error(1)
+}
+
+func (m *MockWeatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options services.HistoryOptions) (*models.HistoryResult, error) {
+	args := m.Called(ctx, query, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HistoryResult), args.Error(1)
 }
 
 // MockTemperatureService é um mock do TemperatureService
@@ -51,6 +74,26 @@ func (m *MockTemperatureService) ConvertTemperatures(celsius float64) (fahrenhei
 	return args.Get(0).(float64), args.Get(1).(float64)
 }
 
+func (m *MockTemperatureService) Convert(value float64, from, to services.Unit) float64 {
+	args := m.Called(value, from, to)
+	return args.Get(0).(float64)
+}
+
+func (m *MockTemperatureService) ConvertDelta(delta float64, from, to services.Unit) float64 {
+	args := m.Called(delta, from, to)
+	return args.Get(0).(float64)
+}
+
+func (m *MockTemperatureService) Validate(value float64, unit services.Unit) error {
+	args := m.Called(value, unit)
+	return args.Error(0)
+}
+
+func (m *MockTemperatureService) Comfort(celsius float64, humidity, windKph *float64) models.ComfortIndices {
+	args := m.Called(celsius, humidity, windKph)
+	return args.Get(0).(models.ComfortIndices)
+}
+
 func TestTemperatureHandler_GetTemperature_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -61,12 +104,12 @@ func TestTemperatureHandler_GetTemperature_Success(t *testing.T) {
 
 	// Configurar mocks
 	mockCEPService.On("ValidateCEP", "01310100").Return(true)
-	mockCEPService.On("GetLocation", "01310100").Return(&models.CEPResponse{
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
 		Localidade: "São Paulo",
 		UF:         "SP",
 	}, nil)
 
-	mockWeatherService.On("GetTemperature", "São Paulo", "SP").Return(28.5, nil)
+	mockWeatherService.On("GetTemperature", mock.Anything, models.WeatherQuery{City: "São Paulo", State: "SP"}).Return(&models.WeatherResult{TempC: 28.5}, nil)
 	mockTemperatureService.On("ConvertTemperatures", 28.5).Return(83.3, 301.5)
 
 	// Criar handler
@@ -144,7 +187,7 @@ func TestTemperatureHandler_GetTemperature_CEPNotFound(t *testing.T) {
 
 	// Configurar mocks
 	mockCEPService.On("ValidateCEP", "99999999").Return(true)
-	mockCEPService.On("GetLocation", "99999999").Return(nil, assert.AnError)
+	mockCEPService.On("GetLocation", mock.Anything, "99999999").Return(nil, services.ErrCEPNotFound)
 
 	// Criar handler
 	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, mockTemperatureService)
@@ -168,3 +211,204 @@ func TestTemperatureHandler_GetTemperature_CEPNotFound(t *testing.T) {
 	// Verificar se os mocks foram chamados
 	mockCEPService.AssertExpectations(t)
 }
+
+func TestTemperatureHandler_GetTemperature_ErrorMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		cepErr          error
+		weatherErr      error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "CEP não encontrado",
+			cepErr:          fmt.Errorf("viacep: %w", services.ErrCEPNotFound),
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "can not find zipcode",
+		},
+		{
+			name:            "provedor de CEP indisponível",
+			cepErr:          fmt.Errorf("erro ao consultar CEP: status 500: %w", services.ErrUpstreamUnavailable),
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: "upstream unavailable",
+		},
+		{
+			name:            "timeout do provedor de CEP",
+			cepErr:          fmt.Errorf("erro ao consultar CEP: %w", services.ErrUpstreamTimeout),
+			expectedStatus:  http.StatusGatewayTimeout,
+			expectedMessage: "upstream timeout",
+		},
+		{
+			name:            "resposta inválida do provedor de CEP",
+			cepErr:          fmt.Errorf("erro ao decodificar resposta: %w", services.ErrBadUpstreamPayload),
+			expectedStatus:  http.StatusBadGateway,
+			expectedMessage: "invalid upstream response",
+		},
+		{
+			name:            "localização não resolvida pela API de clima",
+			weatherErr:      fmt.Errorf("erro ao consultar clima: status 400: %w", services.ErrWeatherLocationNotFound),
+			expectedStatus:  http.StatusBadGateway,
+			expectedMessage: "can not resolve weather location",
+		},
+		{
+			name:            "cota da API de clima excedida",
+			weatherErr:      fmt.Errorf("erro ao consultar clima: status 403: %w", services.ErrQuotaExceeded),
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: "upstream quota exceeded",
+		},
+		{
+			name:            "erro desconhecido",
+			weatherErr:      assert.AnError,
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+			mockTemperatureService := new(MockTemperatureService)
+
+			mockCEPService.On("ValidateCEP", "01310100").Return(true)
+			if tt.cepErr != nil {
+				mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(nil, tt.cepErr)
+			} else {
+				mockCEPService.On("GetLocation", mock.Anything, "01310100").Return(&models.CEPResponse{
+					Localidade: "São Paulo",
+					UF:         "SP",
+				}, nil)
+				mockWeatherService.On("GetTemperature", mock.Anything, models.WeatherQuery{City: "São Paulo", State: "SP"}).Return(nil, tt.weatherErr)
+			}
+
+			handler := NewTemperatureHandler(mockCEPService, mockWeatherService, mockTemperatureService)
+
+			req, _ := http.NewRequest("GET", "/temperature/01310100", nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "cep", Value: "01310100"}}
+
+			handler.GetTemperature(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedMessage)
+			mockCEPService.AssertExpectations(t)
+			mockWeatherService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTemperatureHandler_GetTemperature_RequestBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+	mockTemperatureService := new(MockTemperatureService)
+
+	// A consulta de CEP recebe apenas a sua fração do prazo; a de clima, o prazo total
+	cepDeadline := mock.MatchedBy(func(ctx context.Context) bool {
+		deadline, ok := ctx.Deadline()
+		return ok && time.Until(deadline) <= 400*time.Millisecond
+	})
+	weatherDeadline := mock.MatchedBy(func(ctx context.Context) bool {
+		deadline, ok := ctx.Deadline()
+		return ok && time.Until(deadline) > 400*time.Millisecond
+	})
+
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", cepDeadline, "01310100").Return(&models.CEPResponse{
+		Localidade: "São Paulo",
+		UF:         "SP",
+	}, nil)
+	mockWeatherService.On("GetTemperature", weatherDeadline, models.WeatherQuery{City: "São Paulo", State: "SP"}).Return(&models.WeatherResult{TempC: 28.5}, nil)
+	mockTemperatureService.On("ConvertTemperatures", 28.5).Return(83.3, 301.5)
+
+	handler := NewTemperatureHandler(
+		mockCEPService,
+		mockWeatherService,
+		mockTemperatureService,
+		WithRequestBudget(time.Second, 0.4),
+	)
+
+	req, _ := http.NewRequest("GET", "/temperature/01310100", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "cep", Value: "01310100"}}
+
+	handler.GetTemperature(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockCEPService.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestTemperatureHandler_GetTemperature_ClientDisconnected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+	mockTemperatureService := new(MockTemperatureService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockCEPService.On("ValidateCEP", "01310100").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "01310100").
+		Run(func(mock.Arguments) { cancel() }).
+		Return(nil, fmt.Errorf("requisição interrompida: %w: %w", services.ErrUpstreamUnavailable, context.Canceled))
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, mockTemperatureService)
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/temperature/01310100", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "cep", Value: "01310100"}}
+
+	handler.GetTemperature(c)
+
+	assert.Equal(t, statusClientClosedRequest, c.Writer.Status())
+	assert.Empty(t, w.Body.String())
+	mockWeatherService.AssertNotCalled(t, "GetTemperature", mock.Anything, mock.Anything)
+}
+
+func TestTemperatureHandler_GetTemperature_LocationMatchHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+	mockTemperatureService := new(MockTemperatureService)
+
+	mockCEPService.On("ValidateCEP", "64900000").Return(true)
+	mockCEPService.On("GetLocation", mock.Anything, "64900000").Return(&models.CEPResponse{
+		Localidade: "Bom Jesus",
+		UF:         "PI",
+	}, nil)
+	mockWeatherService.On("GetTemperature", mock.Anything, models.WeatherQuery{City: "Bom Jesus", State: "PI"}).Return(&models.WeatherResult{
+		TempC:    12.0,
+		Location: models.WeatherLocation{Name: "Bom Jesus", Region: "Rio Grande do Sul", Country: "Brazil"},
+		Match:    models.LocationMatch{Status: models.LocationMismatch, Strategy: "city_uf", Attempts: 1},
+	}, nil)
+	mockTemperatureService.On("ConvertTemperatures", 12.0).Return(53.6, 285.0)
+
+	handler := NewTemperatureHandler(mockCEPService, mockWeatherService, mockTemperatureService)
+
+	req, _ := http.NewRequest("GET", "/temperature/64900000", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "cep", Value: "64900000"}}
+
+	handler.GetTemperature(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "mismatch", w.Header().Get("X-Weather-Location-Match"))
+	assert.Equal(t, "Bom Jesus, Rio Grande do Sul, Brazil", w.Header().Get("X-Weather-Location"))
+	assert.Equal(t, "city_uf", w.Header().Get("X-Weather-Query-Strategy"))
+	assert.Equal(t, "1", w.Header().Get("X-Weather-Query-Attempts"))
+
+	// O corpo continua com apenas as três temperaturas
+	assert.JSONEq(t, `{"temp_C": 12.0, "temp_F": 53.6, "temp_K": 285.0}`, w.Body.String())
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemperatureHandler_GetTemperature_Units(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("escalas na ordem solicitada", func(t *testing.T) {
+		w := performTemperature(newConditionsHandler(), "units=K,C,F,Re")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"temp_K":298.15,"temp_C":25,"temp_F":77,"temp_Re":20}`, w.Body.String())
+	})
+
+	t.Run("escalas combinadas com include", func(t *testing.T) {
+		w := performTemperature(newConditionsHandler(), "units=C,Re&include=uv")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"temp_C":25,"temp_Re":20,"uv":7,"last_updated":"2025-01-10 15:00"}`, w.Body.String())
+	})
+
+	t.Run("escala desconhecida", func(t *testing.T) {
+		w := performTemperature(newConditionsHandler(), "units=C,X")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid units", response["message"])
+	})
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// AlertRequest representa o cadastro de uma regra de alerta: notificar a URL quando a
+// temperatura do CEP passar de Above ou ficar abaixo de Below (em Celsius)
+type AlertRequest struct {
+	CEP    string   `json:"cep"`
+	URL    string   `json:"url"`
+	Above  *float64 `json:"above,omitempty"`
+	Below  *float64 `json:"below,omitempty"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// AlertResponse representa uma regra de alerta cadastrada. O segredo que assina os webhooks
+// só aparece na resposta do cadastro; State é a condição da última avaliação.
+type AlertResponse struct {
+	ID        string    `json:"id"`
+	CEP       string    `json:"cep"`
+	URL       string    `json:"url"`
+	Above     *float64  `json:"above,omitempty"`
+	Below     *float64  `json:"below,omitempty"`
+	Secret    string    `json:"secret,omitempty"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlertListResponse representa as regras de alerta cadastradas
+type AlertListResponse struct {
+	Alerts []AlertResponse `json:"alerts"`
+}
+
+// AlertEvent é o corpo do webhook enviado quando a temperatura do CEP cruza um limite. O ID
+// se repete nas novas tentativas da mesma entrega.
+type AlertEvent struct {
+	ID          string    `json:"id"`
+	AlertID     string    `json:"alert_id"`
+	CEP         string    `json:"cep"`
+	Condition   string    `json:"condition"`
+	Threshold   float64   `json:"threshold"`
+	TempC       float64   `json:"temp_C"`
+	TempF       float64   `json:"temp_F"`
+	TempK       float64   `json:"temp_K"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+// DeadLetter representa um webhook que esgotou as tentativas de entrega
+type DeadLetter struct {
+	Event    AlertEvent `json:"event"`
+	URL      string     `json:"url"`
+	Attempts int        `json:"attempts"`
+	Error    string     `json:"error"`
+	FailedAt time.Time  `json:"failed_at"`
+}
+
+// DeadLetterListResponse representa os webhooks não entregues, do mais antigo ao mais recente
+type DeadLetterListResponse struct {
+	DeadLetters []DeadLetter `json:"dead_letters"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// APIKeyRequest representa o cadastro de uma chave de API. Cotas omitidas recebem os valores
+// padrão da configuração; cotas zeradas não limitam o consumo.
+type APIKeyRequest struct {
+	Name         string `json:"name"`
+	DailyQuota   *int64 `json:"daily_quota,omitempty"`
+	MonthlyQuota *int64 `json:"monthly_quota,omitempty"`
+}
+
+// APIKeyResponse representa uma chave de API cadastrada. A chave só aparece na resposta do
+// cadastro; Prefix permite reconhecê-la depois. Source indica se a chave veio da configuração
+// ou da API de administração.
+type APIKeyResponse struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Key          string      `json:"key,omitempty"`
+	Prefix       string      `json:"prefix,omitempty"`
+	Source       string      `json:"source"`
+	DailyQuota   int64       `json:"daily_quota"`
+	MonthlyQuota int64       `json:"monthly_quota"`
+	Usage        APIKeyUsage `json:"usage"`
+	CreatedAt    time.Time   `json:"created_at"`
+	RevokedAt    *time.Time  `json:"revoked_at,omitempty"`
+}
+
+// APIKeyUsage representa o consumo da chave no último dia e no último mês em que foi usada (UTC)
+type APIKeyUsage struct {
+	Day        string `json:"day,omitempty"`
+	DayCount   int64  `json:"day_count"`
+	Month      string `json:"month,omitempty"`
+	MonthCount int64  `json:"month_count"`
+}
+
+// APIKeyListResponse representa as chaves de API cadastradas, inclusive as revogadas
+type APIKeyListResponse struct {
+	Keys []APIKeyResponse `json:"keys"`
+}
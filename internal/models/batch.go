@@ -0,0 +1,28 @@
+package models
+
+// Situação de cada CEP em uma consulta em lote
+const (
+	BatchStatusOK            = "ok"
+	BatchStatusInvalid       = "invalid"
+	BatchStatusNotFound      = "not_found"
+	BatchStatusUpstreamError = "upstream_error"
+)
+
+// BatchTemperatureRequest representa uma consulta de temperatura de vários CEPs
+type BatchTemperatureRequest struct {
+	CEPs []string `json:"ceps"`
+}
+
+// BatchTemperatureResponse representa os resultados de uma consulta em lote, na ordem recebida
+type BatchTemperatureResponse struct {
+	Results []BatchTemperatureResult `json:"results"`
+}
+
+// BatchTemperatureResult representa o resultado de um CEP do lote: as temperaturas quando
+// a situação é ok, ou a mensagem de erro nas demais
+type BatchTemperatureResult struct {
+	CEP    string `json:"cep"`
+	Status string `json:"status"`
+	*TemperatureResponse
+	Message string `json:"message,omitempty"`
+}
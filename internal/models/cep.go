@@ -1,5 +1,10 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // CEPResponse representa a resposta da API ViaCEP
 type CEPResponse struct {
 	CEP         string `json:"cep"`
@@ -13,6 +18,43 @@ type CEPResponse struct {
 	DDD         string `json:"ddd"`
 	SIAFI       string `json:"siafi"`
 	Erro        bool   `json:"erro"`
+	Provider    string `json:"provider,omitempty"`
+}
+
+// BrasilAPIResponse representa a resposta da BrasilAPI
+type BrasilAPIResponse struct {
+	CEP          string `json:"cep"`
+	State        string `json:"state"`
+	City         string `json:"city"`
+	Neighborhood string `json:"neighborhood"`
+	Street       string `json:"street"`
+	Service      string `json:"service"`
+}
+
+// OpenCEPResponse representa a resposta da OpenCEP
+type OpenCEPResponse struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Localidade  string `json:"localidade"`
+	UF          string `json:"uf"`
+	IBGE        string `json:"ibge"`
+}
+
+// AwesomeAPIResponse representa a resposta da AwesomeAPI
+type AwesomeAPIResponse struct {
+	CEP         string `json:"cep"`
+	AddressType string `json:"address_type"`
+	AddressName string `json:"address_name"`
+	Address     string `json:"address"`
+	State       string `json:"state"`
+	District    string `json:"district"`
+	Lat         string `json:"lat"`
+	Lng         string `json:"lng"`
+	City        string `json:"city"`
+	CityIBGE    string `json:"city_ibge"`
+	DDD         string `json:"ddd"`
 }
 
 // TemperatureResponse representa a resposta de temperatura
@@ -22,6 +64,101 @@ type TemperatureResponse struct {
 	TempK float64 `json:"temp_K"`
 }
 
+// ExtendedTemperatureResponse representa a resposta de temperatura com os blocos opcionais
+// solicitados via include. Os blocos não solicitados, ou não informados pelo provedor, são omitidos.
+type ExtendedTemperatureResponse struct {
+	*TemperatureResponse
+	Readings    TemperatureReadings  `json:"-"`
+	Location    *LocationResponse    `json:"location,omitempty"`
+	Condition   *ConditionResponse   `json:"condition,omitempty"`
+	PressureMb  *float64             `json:"pressure_mb,omitempty"`
+	Humidity    *float64             `json:"humidity,omitempty"`
+	Wind        *WindResponse        `json:"wind,omitempty"`
+	FeelsLike   *TemperatureResponse `json:"feelslike,omitempty"`
+	UV          *float64             `json:"uv,omitempty"`
+	Comfort     *ComfortResponse     `json:"comfort,omitempty"`
+	LastUpdated string               `json:"last_updated,omitempty"`
+}
+
+// MarshalJSON escreve as temperaturas nas escalas selecionadas, quando houver, no lugar das
+// três escalas padrão, seguidas dos blocos opcionais
+func (r ExtendedTemperatureResponse) MarshalJSON() ([]byte, error) {
+	type plain ExtendedTemperatureResponse
+	if r.Readings == nil {
+		return json.Marshal(plain(r))
+	}
+
+	r.TemperatureResponse = nil
+	blocks, err := json.Marshal(plain(r))
+	if err != nil {
+		return nil, err
+	}
+	readings, err := r.Readings.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) <= len("{}") || len(readings) <= len("{}") {
+		return append(readings[:len(readings)-1], blocks[1:]...), nil
+	}
+	return append(append(readings[:len(readings)-1], ','), blocks[1:]...), nil
+}
+
+// TemperatureReading representa uma temperatura expressa em uma escala
+type TemperatureReading struct {
+	Unit  string
+	Value float64
+}
+
+// TemperatureReadings representa uma temperatura nas escalas selecionadas via ?units=,
+// serializada como {"temp_C": ..., "temp_F": ...} na ordem da seleção
+type TemperatureReadings []TemperatureReading
+
+// MarshalJSON serializa as leituras como um objeto, preservando a ordem das escalas
+func (r TemperatureReadings) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, reading := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal("temp_" + reading.Unit)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(reading.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// LocationResponse descreve o município do CEP e o local resolvido pela API de clima
+type LocationResponse struct {
+	City    string          `json:"city"`
+	State   string          `json:"state"`
+	IBGE    string          `json:"ibge,omitempty"`
+	Weather WeatherLocation `json:"weather"`
+	Match   string          `json:"match,omitempty"`
+}
+
+// ConditionResponse descreve a condição do tempo no vocabulário do provedor de clima
+type ConditionResponse struct {
+	Text string `json:"text,omitempty"`
+	Code *int   `json:"code,omitempty"`
+}
+
+// WindResponse descreve o vento: velocidade em km/h e direção em graus e na rosa dos ventos
+type WindResponse struct {
+	SpeedKph  *float64 `json:"speed_kph,omitempty"`
+	Degree    *float64 `json:"degree,omitempty"`
+	Direction string   `json:"direction,omitempty"`
+}
+
 // WeatherResponse representa a resposta da API de clima
 type WeatherResponse struct {
 	Location struct {
@@ -29,7 +166,31 @@ type WeatherResponse struct {
 		Region  string `json:"region"`
 		Country string `json:"country"`
 	} `json:"location"`
-	Current struct {
-		TempC float64 `json:"temp_c"`
-	} `json:"current"`
+	Current WeatherAPICurrent `json:"current"`
+}
+
+// WeatherAPICurrent representa as condições atuais informadas pela WeatherAPI
+type WeatherAPICurrent struct {
+	TempC            float64 `json:"temp_c"`
+	LastUpdated      string  `json:"last_updated"`
+	LastUpdatedEpoch int64   `json:"last_updated_epoch"`
+	Condition        struct {
+		Text string `json:"text"`
+		Code int    `json:"code"`
+	} `json:"condition"`
+	WindKph    *float64 `json:"wind_kph"`
+	WindDegree *float64 `json:"wind_degree"`
+	WindDir    string   `json:"wind_dir"`
+	PressureMb *float64 `json:"pressure_mb"`
+	Humidity   *float64 `json:"humidity"`
+	FeelsLikeC *float64 `json:"feelslike_c"`
+	UV         *float64 `json:"uv"`
+}
+
+// WeatherAPIError representa o corpo de erro retornado pela WeatherAPI
+type WeatherAPIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
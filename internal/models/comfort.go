@@ -0,0 +1,48 @@
+package models
+
+// Categorias de risco dos índices de conforto térmico, da mais branda à mais grave
+const (
+	RiskNone           = "none"
+	RiskCaution        = "caution"
+	RiskExtremeCaution = "extreme_caution"
+	RiskDanger         = "danger"
+	RiskExtremeDanger  = "extreme_danger"
+)
+
+// ComfortIndex representa um índice de conforto térmico calculado. Fora da faixa de validade
+// da fórmula, ou sem os dados necessários, Valid é falso e Reason explica o motivo.
+type ComfortIndex struct {
+	Value  float64
+	Valid  bool
+	Risk   string
+	Reason string
+}
+
+// ComfortIndices reúne os índices derivados da temperatura, umidade e vento. Todos são
+// expressos em graus Celsius, exceto o humidex, que é adimensional.
+type ComfortIndices struct {
+	HeatIndex ComfortIndex
+	WindChill ComfortIndex
+	DewPoint  ComfortIndex
+	Humidex   ComfortIndex
+	WetBulb   ComfortIndex
+}
+
+// ComfortResponse representa os índices de conforto térmico na resposta de temperatura
+type ComfortResponse struct {
+	HeatIndex ComfortIndexResponse `json:"heat_index"`
+	WindChill ComfortIndexResponse `json:"wind_chill"`
+	DewPoint  ComfortIndexResponse `json:"dew_point"`
+	Humidex   ComfortIndexResponse `json:"humidex"`
+	WetBulb   ComfortIndexResponse `json:"wet_bulb"`
+}
+
+// ComfortIndexResponse representa um índice nas três escalas (ou como valor adimensional,
+// no caso do humidex), com a validade e a categoria de risco
+type ComfortIndexResponse struct {
+	*TemperatureResponse
+	Value  *float64 `json:"value,omitempty"`
+	Valid  bool     `json:"valid"`
+	Risk   string   `json:"risk,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
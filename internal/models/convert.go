@@ -0,0 +1,24 @@
+package models
+
+// ConvertResponse representa a conversão de uma temperatura entre duas escalas
+type ConvertResponse struct {
+	Value  float64 `json:"value"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Result float64 `json:"result"`
+}
+
+// ConvertBatchRequest representa a conversão em lote de várias temperaturas na mesma escala
+type ConvertBatchRequest struct {
+	Values []float64 `json:"values"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+}
+
+// ConvertBatchResponse representa o resultado da conversão em lote, na ordem dos valores recebidos
+type ConvertBatchResponse struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Values  []float64 `json:"values"`
+	Results []float64 `json:"results"`
+}
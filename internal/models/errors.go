@@ -0,0 +1,29 @@
+package models
+
+// ErrorResponse representa o corpo de erro de todas as rotas. Detail descreve a ocorrência,
+// como o parâmetro ou o índice que causou o erro, quando houver.
+type ErrorResponse struct {
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// ProblemResponse representa o corpo de erro da RFC 7807 (application/problem+json), com o
+// código estável do erro em code e a mensagem legada em message
+type ProblemResponse struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ProblemTypeResponse descreve um tipo de problema do catálogo de erros
+type ProblemTypeResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+}
@@ -0,0 +1,95 @@
+package models
+
+// ForecastHour representa a temperatura prevista para uma hora, no horário local do município
+type ForecastHour struct {
+	Time  string
+	TempC float64
+}
+
+// ForecastDay representa a previsão de um dia, com as horas previstas quando solicitadas
+type ForecastDay struct {
+	Date  string
+	MinC  float64
+	MaxC  float64
+	AvgC  float64
+	Hours []ForecastHour
+}
+
+// ForecastResult representa o resultado de uma consulta de previsão
+type ForecastResult struct {
+	Days     []ForecastDay
+	Location WeatherLocation
+	Match    LocationMatch
+}
+
+// ForecastResponse representa a resposta do endpoint de previsão
+type ForecastResponse struct {
+	Days []ForecastDayResponse `json:"days"`
+}
+
+// ForecastDayResponse representa a previsão de um dia nas três escalas
+type ForecastDayResponse struct {
+	Date   string                 `json:"date"`
+	Min    TemperatureResponse    `json:"min"`
+	Max    TemperatureResponse    `json:"max"`
+	Avg    TemperatureResponse    `json:"avg"`
+	Hourly []ForecastHourResponse `json:"hourly,omitempty"`
+}
+
+// ForecastHourResponse representa a temperatura prevista para uma hora nas três escalas
+type ForecastHourResponse struct {
+	Time string `json:"time"`
+	TemperatureResponse
+}
+
+// WeatherAPIForecastResponse representa a resposta do endpoint forecast.json da WeatherAPI
+type WeatherAPIForecastResponse struct {
+	Location struct {
+		Name    string `json:"name"`
+		Region  string `json:"region"`
+		Country string `json:"country"`
+	} `json:"location"`
+	Forecast struct {
+		ForecastDay []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxTempC float64 `json:"maxtemp_c"`
+				MinTempC float64 `json:"mintemp_c"`
+				AvgTempC float64 `json:"avgtemp_c"`
+			} `json:"day"`
+			Hour []struct {
+				Time  string  `json:"time"`
+				TempC float64 `json:"temp_c"`
+			} `json:"hour"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+}
+
+// OpenMeteoForecastResponse representa a resposta de previsão diária e horária da Open-Meteo
+type OpenMeteoForecastResponse struct {
+	Daily struct {
+		Time []string  `json:"time"`
+		Max  []float64 `json:"temperature_2m_max"`
+		Min  []float64 `json:"temperature_2m_min"`
+		Mean []float64 `json:"temperature_2m_mean"`
+	} `json:"daily"`
+	Hourly struct {
+		Time          []string  `json:"time"`
+		Temperature2m []float64 `json:"temperature_2m"`
+	} `json:"hourly"`
+}
+
+// OpenWeatherMapForecastResponse representa a previsão em intervalos de 3 horas da OpenWeatherMap
+type OpenWeatherMapForecastResponse struct {
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			Temp float64 `json:"temp"`
+		} `json:"main"`
+	} `json:"list"`
+	City struct {
+		Name     string `json:"name"`
+		Country  string `json:"country"`
+		Timezone int    `json:"timezone"`
+	} `json:"city"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// HistoryDay representa as temperaturas observadas em um dia
+type HistoryDay struct {
+	Date string
+	MinC float64
+	MaxC float64
+	AvgC float64
+}
+
+// HistoryResult representa o resultado de uma consulta de histórico
+type HistoryResult struct {
+	Days     []HistoryDay
+	Location WeatherLocation
+	Match    LocationMatch
+}
+
+// HistoryResponse representa uma página da resposta do endpoint de histórico
+type HistoryResponse struct {
+	Days       []HistoryDayResponse `json:"days"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// HistoryDayResponse representa as temperaturas de um dia nas três escalas
+type HistoryDayResponse struct {
+	Date string              `json:"date"`
+	Min  TemperatureResponse `json:"min"`
+	Max  TemperatureResponse `json:"max"`
+	Avg  TemperatureResponse `json:"avg"`
+}
+
+// Pagination descreve a página devolvida dentro do intervalo consultado
+type Pagination struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalDays  int `json:"total_days"`
+	TotalPages int `json:"total_pages"`
+}
+
+// OpenMeteoArchiveResponse representa a resposta diária da API de histórico da Open-Meteo
+type OpenMeteoArchiveResponse struct {
+	Daily struct {
+		Time []string   `json:"time"`
+		Max  []*float64 `json:"temperature_2m_max"`
+		Min  []*float64 `json:"temperature_2m_min"`
+		Mean []*float64 `json:"temperature_2m_mean"`
+	} `json:"daily"`
+}
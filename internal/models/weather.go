@@ -0,0 +1,148 @@
+package models
+
+import "time"
+
+// WeatherQuery identifica o local de uma consulta de clima
+type WeatherQuery struct {
+	City  string
+	State string
+	IBGE  string
+}
+
+// Coordinates representa um par latitude/longitude em graus decimais
+type Coordinates struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// Resultados da comparação entre o local do CEP e o local resolvido pela API de clima
+const (
+	LocationMatched   = "matched"
+	LocationMismatch  = "mismatch"
+	LocationUnchecked = "unchecked"
+)
+
+// WeatherLocation representa o local resolvido pela API de clima
+type WeatherLocation struct {
+	Name    string `json:"name"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+}
+
+// LocationMatch registra a comparação entre o local do CEP e o resolvido pela API de clima
+type LocationMatch struct {
+	Status   string `json:"status"`
+	Strategy string `json:"strategy"`
+	Attempts int    `json:"attempts"`
+}
+
+// WeatherResult representa o resultado de uma consulta de clima
+type WeatherResult struct {
+	TempC      float64
+	Conditions Conditions
+	Location   WeatherLocation
+	Match      LocationMatch
+}
+
+// Conditions reúne as condições atuais além da temperatura. Cada provedor informa um
+// subconjunto delas; os campos ausentes ficam nulos. LastUpdated é o horário da medição como
+// exibido pelo provedor; ObservedAt é o mesmo instante, zero quando o provedor não o informa.
+type Conditions struct {
+	Text        string
+	Code        *int
+	Humidity    *float64
+	WindKph     *float64
+	WindDegree  *float64
+	WindDir     string
+	PressureMb  *float64
+	FeelsLikeC  *float64
+	UV          *float64
+	LastUpdated string
+	ObservedAt  time.Time
+}
+
+// OpenMeteoCurrentResponse representa a resposta de condições atuais da Open-Meteo
+type OpenMeteoCurrentResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Current          struct {
+		Time                string   `json:"time"`
+		Temperature2m       float64  `json:"temperature_2m"`
+		RelativeHumidity2m  *float64 `json:"relative_humidity_2m"`
+		ApparentTemperature *float64 `json:"apparent_temperature"`
+		WeatherCode         *int     `json:"weather_code"`
+		WindSpeed10m        *float64 `json:"wind_speed_10m"`
+		WindDirection10m    *float64 `json:"wind_direction_10m"`
+		PressureMSL         *float64 `json:"pressure_msl"`
+		UVIndex             *float64 `json:"uv_index"`
+	} `json:"current"`
+}
+
+// OpenMeteoError representa o corpo de erro da Open-Meteo
+type OpenMeteoError struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// OpenMeteoGeocodingResponse representa a resposta da API de geocodificação da Open-Meteo
+type OpenMeteoGeocodingResponse struct {
+	Results []struct {
+		Name        string  `json:"name"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		Country     string  `json:"country"`
+		CountryCode string  `json:"country_code"`
+		Admin1      string  `json:"admin1"`
+	} `json:"results"`
+}
+
+// OpenWeatherMapResponse representa a resposta de clima atual da OpenWeatherMap
+type OpenWeatherMapResponse struct {
+	Name    string `json:"name"`
+	Dt      int64  `json:"dt"`
+	Weather []struct {
+		ID          int    `json:"id"`
+		Description string `json:"description"`
+	} `json:"weather"`
+	Main struct {
+		Temp      float64  `json:"temp"`
+		FeelsLike *float64 `json:"feels_like"`
+		Pressure  *float64 `json:"pressure"`
+		Humidity  *float64 `json:"humidity"`
+	} `json:"main"`
+	Wind struct {
+		Speed *float64 `json:"speed"`
+		Deg   *float64 `json:"deg"`
+	} `json:"wind"`
+	Sys struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+	Timezone int `json:"timezone"`
+}
+
+// INMETStation representa uma estação meteorológica automática do INMET
+type INMETStation struct {
+	Code      string `json:"CD_ESTACAO"`
+	Name      string `json:"DC_NOME"`
+	UF        string `json:"SG_ESTADO"`
+	Latitude  string `json:"VL_LATITUDE"`
+	Longitude string `json:"VL_LONGITUDE"`
+	Status    string `json:"CD_SITUACAO"`
+}
+
+// INMETObservation representa uma medição horária de uma estação do INMET.
+// Campos ausentes na medição chegam como null.
+type INMETObservation struct {
+	Date        string  `json:"DT_MEDICAO"`
+	Hour        string  `json:"HR_MEDICAO"`
+	Temperature *string `json:"TEM_INS"`
+	MinTemp     *string `json:"TEM_MIN"`
+	MaxTemp     *string `json:"TEM_MAX"`
+	Humidity    *string `json:"UMD_INS"`
+	Pressure    *string `json:"PRE_INS"`
+	WindSpeed   *string `json:"VEN_VEL"`
+	WindDir     *string `json:"VEN_DIR"`
+	StationName string  `json:"DC_NOME"`
+	UF          string  `json:"UF"`
+}
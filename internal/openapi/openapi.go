@@ -0,0 +1,53 @@
+// Package openapi publica o contrato OpenAPI 3 das rotas HTTP e valida requisições e
+// respostas contra ele.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// loadSpec carrega e valida o documento embutido uma única vez
+var loadSpec = sync.OnceValues(func() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+	return doc, nil
+})
+
+// Spec devolve o documento OpenAPI da API
+func Spec() (*openapi3.T, error) {
+	return loadSpec()
+}
+
+// ServeSpec responde com o documento OpenAPI em JSON, em GET /openapi.json
+func ServeSpec(c *gin.Context) {
+	doc, err := Spec()
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// ServeDocs responde com a documentação navegável (Redoc) do documento servido em /openapi.json
+func ServeDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsHTML)
+}
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// mimeEventStream é o tipo das respostas em Server-Sent Events
+const mimeEventStream = "text/event-stream"
+
+// ErrInvalidRequest é o erro das requisições fora do contrato
+var ErrInvalidRequest = errors.New("invalid request")
+
+// RequestError descreve a requisição fora do contrato pelo parâmetro ou campo do corpo
+// recusado
+type RequestError struct {
+	Detail string
+}
+
+func (e *RequestError) Error() string { return e.Detail }
+
+func (e *RequestError) Unwrap() error { return ErrInvalidRequest }
+
+// ErrorWriter escreve a resposta das requisições recusadas
+type ErrorWriter func(c *gin.Context, err error)
+
+// Validator confere requisições e respostas contra o documento OpenAPI. Rotas fora do
+// documento, como o próprio /openapi.json, passam sem validação.
+type Validator struct {
+	router     routers.Router
+	requests   bool
+	responses  bool
+	writeError ErrorWriter
+}
+
+// Option personaliza o Validator
+type Option func(*Validator)
+
+// WithRequestValidation recusa com 400 as requisições fora do contrato, antes do handler
+func WithRequestValidation(enabled bool) Option {
+	return func(v *Validator) {
+		v.requests = enabled
+	}
+}
+
+// WithResponseValidation troca por um 500 as respostas fora do contrato, para que a
+// divergência entre handler e documento falhe os testes
+func WithResponseValidation(enabled bool) Option {
+	return func(v *Validator) {
+		v.responses = enabled
+	}
+}
+
+// WithErrorWriter escreve as recusas com o mesmo corpo de erro das rotas da API. O erro
+// recebido é um *RequestError, que envolve ErrInvalidRequest.
+func WithErrorWriter(write ErrorWriter) Option {
+	return func(v *Validator) {
+		v.writeError = write
+	}
+}
+
+// NewValidator cria o validador do documento embutido. Sem opções, as requisições não são
+// validadas e as respostas são validadas apenas no modo de teste do Gin.
+func NewValidator(opts ...Option) (*Validator, error) {
+	doc, err := Spec()
+	if err != nil {
+		return nil, err
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build openapi router: %w", err)
+	}
+
+	v := &Validator{router: router, responses: gin.Mode() == gin.TestMode, writeError: writeRequestError}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+// Middleware valida a requisição antes do handler e a resposta depois dele, conforme as opções
+func (v *Validator) Middleware(c *gin.Context) {
+	if !v.requests && !v.responses {
+		c.Next()
+		return
+	}
+	route, pathParams, err := v.router.FindRoute(c.Request)
+	if err != nil {
+		c.Next()
+		return
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    c.Request,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			// Os padrões do documento são aplicados pelos handlers; a requisição segue intacta
+			SkipSettingDefaults: true,
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	if v.requests {
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			v.writeError(c, &RequestError{Detail: requestErrorMessage(err)})
+			c.Abort()
+			return
+		}
+	}
+	if !v.responses || streams(route) {
+		c.Next()
+		return
+	}
+
+	writer := NewBufferedWriter(c.Writer)
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+
+	err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 writer.Status(),
+		Header:                 writer.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(writer.Body())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			// Os demais formatos são derivados do JSON; basta validar o corpo em JSON
+			ExcludeResponseBody: !isJSON(writer.Header().Get("Content-Type")),
+		},
+	})
+	if err != nil {
+		_ = c.Error(err)
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Length")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Message: "response does not match the API contract: " + err.Error(),
+		})
+		return
+	}
+
+	writer.Release()
+}
+
+// writeRequestError é a recusa sem WithErrorWriter, com o corpo de erro legado
+func writeRequestError(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: ErrInvalidRequest.Error(), Detail: err.Error()})
+}
+
+// streams indica se a operação responde com Server-Sent Events, cujo corpo não termina e por
+// isso não pode ser retido para validação
+func streams(route *routers.Route) bool {
+	response := route.Operation.Responses.Status(http.StatusOK)
+	return response != nil && response.Value != nil && response.Value.Content.Get(mimeEventStream) != nil
+}
+
+// requestErrorMessage resume o erro de validação no parâmetro ou campo do corpo recusado
+func requestErrorMessage(err error) string {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return "invalid request"
+	}
+
+	subject := "request body"
+	if parameter := requestErr.Parameter; parameter != nil {
+		subject = fmt.Sprintf("%s parameter %s", parameter.In, parameter.Name)
+	}
+	reason := requestErr.Reason
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		reason = schemaErr.Reason
+		if field := strings.Join(schemaErr.JSONPointer(), "."); field != "" {
+			reason = field + ": " + reason
+		}
+	}
+	if reason == "" {
+		return "invalid " + subject
+	}
+	return fmt.Sprintf("invalid %s: %s", subject, reason)
+}
+
+// isJSON indica se o Content-Type da resposta é JSON
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == gin.MIMEJSON || strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(t *testing.T, opts ...Option) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	validator, err := NewValidator(opts...)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(validator.Middleware)
+	return router
+}
+
+func perform(router *gin.Engine, method, target, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func message(t *testing.T, w *httptest.ResponseRecorder) string {
+	return field(t, w, "message")
+}
+
+func field(t *testing.T, w *httptest.ResponseRecorder, name string) string {
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response[name]
+}
+
+func TestSpec_Valid(t *testing.T) {
+	doc, err := Spec()
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"/health", "/temperature/{cep}", "/temperature/batch", "/forecast/{cep}",
+		"/history/{cep}", "/convert", "/stats/cep-providers", "/graphql",
+	} {
+		assert.NotNil(t, doc.Paths.Value(path), path)
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/openapi.json", ServeSpec)
+	router.GET("/docs", ServeDocs)
+
+	w := perform(router, "GET", "/openapi.json", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Contains(t, doc["paths"], "/temperature/{cep}")
+
+	w = perform(router, "GET", "/docs", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `spec-url="/openapi.json"`)
+}
+
+func TestValidator_Requests(t *testing.T) {
+	router := newRouter(t, WithRequestValidation(true), WithResponseValidation(false))
+	router.GET("/forecast/:cep", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"days": []any{}})
+	})
+	router.POST("/convert", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"from": "C", "to": "F", "values": []float64{}, "results": []float64{}})
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		detail string
+	}{
+		{"parâmetros válidos", "GET", "/forecast/01310100?days=5&hourly=true", "", http.StatusOK, ""},
+		{"days acima do limite", "GET", "/forecast/01310100?days=17", "", http.StatusBadRequest, "invalid query parameter days: number must be at most 16"},
+		{"days não numérico", "GET", "/forecast/01310100?days=abc", "", http.StatusBadRequest, "invalid query parameter days"},
+		{"corpo válido", "POST", "/convert", `{"values":[0],"from":"C","to":"F"}`, http.StatusOK, ""},
+		{"corpo sem campo obrigatório", "POST", "/convert", `{"values":[0],"from":"C"}`, http.StatusBadRequest, `invalid request body: to: property "to" is missing`},
+		{"corpo com tipo errado", "POST", "/convert", `{"values":["0"],"from":"C","to":"F"}`, http.StatusBadRequest, "invalid request body: values.0: value must be a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := perform(router, tt.method, tt.target, tt.body)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.detail != "" {
+				assert.Equal(t, "invalid request", message(t, w))
+				assert.True(t, strings.HasPrefix(field(t, w, "detail"), tt.detail), field(t, w, "detail"))
+			}
+		})
+	}
+}
+
+func TestValidator_Responses(t *testing.T) {
+	router := newRouter(t, WithResponseValidation(true))
+	router.GET("/convert", func(c *gin.Context) {
+		if c.Query("drift") != "" {
+			c.JSON(http.StatusOK, gin.H{"value": 0, "from": "C", "to": "F", "result": "32"})
+			return
+		}
+		c.Header("X-Custom", "kept")
+		c.JSON(http.StatusOK, gin.H{"value": 0, "from": "C", "to": "F", "result": 32})
+	})
+	router.GET("/convert/xml", func(c *gin.Context) {
+		c.XML(http.StatusOK, gin.H{"anything": "goes"})
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusTeapot, gin.H{"status": "ok"})
+	})
+
+	t.Run("resposta conforme o contrato", func(t *testing.T) {
+		w := perform(router, "GET", "/convert?value=0&from=C&to=F", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"value":0,"from":"C","to":"F","result":32}`, w.Body.String())
+		assert.Equal(t, "kept", w.Header().Get("X-Custom"))
+	})
+
+	t.Run("campo com tipo divergente", func(t *testing.T) {
+		w := perform(router, "GET", "/convert?value=0&from=C&to=F&drift=1", "")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, message(t, w), "response does not match the API contract")
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("status não documentado", func(t *testing.T) {
+		w := perform(router, "GET", "/health", "")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, message(t, w), "status is not supported")
+	})
+
+	t.Run("rota fora do contrato", func(t *testing.T) {
+		w := perform(router, "GET", "/convert/xml", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "<anything>goes</anything>")
+	})
+}
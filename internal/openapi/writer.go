@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BufferedWriter retém status e corpo da resposta até o middleware que o instalou decidir o
+// que enviar, como a validação da resposta contra o contrato e os cabeçalhos do cache HTTP.
+// Os cabeçalhos continuam sendo os do writer original.
+type BufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	written bool
+}
+
+// NewBufferedWriter retém a resposta escrita em w, com status 200 se o handler não definir outro
+func NewBufferedWriter(w gin.ResponseWriter) *BufferedWriter {
+	return &BufferedWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+// Body devolve o corpo retido
+func (w *BufferedWriter) Body() []byte {
+	return w.body.Bytes()
+}
+
+// Release escreve o status e o corpo retidos no writer original
+func (w *BufferedWriter) Release() {
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
+
+func (w *BufferedWriter) WriteHeader(status int) {
+	if !w.written {
+		w.status = status
+	}
+}
+
+func (w *BufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *BufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *BufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *BufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *BufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *BufferedWriter) Written() bool {
+	return w.written
+}
+
+func (w *BufferedWriter) Flush() {}
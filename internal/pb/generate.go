@@ -0,0 +1,4 @@
+// Package pb contém o código gerado a partir de api/proto.
+package pb
+
+//go:generate protoc -I ../../api/proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative temperature.proto temperature_service.proto
@@ -0,0 +1,204 @@
+// Esquema Protocol Buffers das respostas da API, usado quando o cliente pede
+// application/x-protobuf (Accept) ou ?format=protobuf.
+//
+// Os nomes dos campos seguem os da resposta JSON (temp_C, temp_F, temp_K).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: temperature.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Temperatura nas três escalas padrão, como em GET /temperature/:cep
+type TemperatureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Temp_C        float64                `protobuf:"fixed64,1,opt,name=temp_C,proto3" json:"temp_C,omitempty"`
+	Temp_F        float64                `protobuf:"fixed64,2,opt,name=temp_F,proto3" json:"temp_F,omitempty"`
+	Temp_K        float64                `protobuf:"fixed64,3,opt,name=temp_K,proto3" json:"temp_K,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemperatureResponse) Reset() {
+	*x = TemperatureResponse{}
+	mi := &file_temperature_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemperatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemperatureResponse) ProtoMessage() {}
+
+func (x *TemperatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemperatureResponse.ProtoReflect.Descriptor instead.
+func (*TemperatureResponse) Descriptor() ([]byte, []int) {
+	return file_temperature_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TemperatureResponse) GetTemp_C() float64 {
+	if x != nil {
+		return x.Temp_C
+	}
+	return 0
+}
+
+func (x *TemperatureResponse) GetTemp_F() float64 {
+	if x != nil {
+		return x.Temp_F
+	}
+	return 0
+}
+
+func (x *TemperatureResponse) GetTemp_K() float64 {
+	if x != nil {
+		return x.Temp_K
+	}
+	return 0
+}
+
+// Corpo de erro de qualquer rota, como {"message": ..., "detail": ...} em JSON
+type ErrorResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// Ocorrência do erro, como o parâmetro ou o índice que o causou; vazio quando não houver
+	Detail        string `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorResponse) Reset() {
+	*x = ErrorResponse{}
+	mi := &file_temperature_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorResponse) ProtoMessage() {}
+
+func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
+func (*ErrorResponse) Descriptor() ([]byte, []int) {
+	return file_temperature_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ErrorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ErrorResponse) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+var File_temperature_proto protoreflect.FileDescriptor
+
+const file_temperature_proto_rawDesc = "" +
+	"\n" +
+	"\x11temperature.proto\x12\x11ceptemperatura.v1\"]\n" +
+	"\x13TemperatureResponse\x12\x16\n" +
+	"\x06temp_C\x18\x01 \x01(\x01R\x06temp_C\x12\x16\n" +
+	"\x06temp_F\x18\x02 \x01(\x01R\x06temp_F\x12\x16\n" +
+	"\x06temp_K\x18\x03 \x01(\x01R\x06temp_K\"A\n" +
+	"\rErrorResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x16\n" +
+	"\x06detail\x18\x02 \x01(\tR\x06detailB\x1dZ\x1bcep-temperatura/internal/pbb\x06proto3"
+
+var (
+	file_temperature_proto_rawDescOnce sync.Once
+	file_temperature_proto_rawDescData []byte
+)
+
+func file_temperature_proto_rawDescGZIP() []byte {
+	file_temperature_proto_rawDescOnce.Do(func() {
+		file_temperature_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_temperature_proto_rawDesc), len(file_temperature_proto_rawDesc)))
+	})
+	return file_temperature_proto_rawDescData
+}
+
+var file_temperature_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_temperature_proto_goTypes = []any{
+	(*TemperatureResponse)(nil), // 0: ceptemperatura.v1.TemperatureResponse
+	(*ErrorResponse)(nil),       // 1: ceptemperatura.v1.ErrorResponse
+}
+var file_temperature_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_temperature_proto_init() }
+func file_temperature_proto_init() {
+	if File_temperature_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_temperature_proto_rawDesc), len(file_temperature_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_temperature_proto_goTypes,
+		DependencyIndexes: file_temperature_proto_depIdxs,
+		MessageInfos:      file_temperature_proto_msgTypes,
+	}.Build()
+	File_temperature_proto = out.File
+	file_temperature_proto_goTypes = nil
+	file_temperature_proto_depIdxs = nil
+}
@@ -0,0 +1,432 @@
+// Serviço gRPC de temperatura, servido ao lado da API HTTP (GRPC_PORT).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: temperature_service.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetTemperatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cep           string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemperatureRequest) Reset() {
+	*x = GetTemperatureRequest{}
+	mi := &file_temperature_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemperatureRequest) ProtoMessage() {}
+
+func (x *GetTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*GetTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_temperature_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetTemperatureRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type BatchGetTemperatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ceps          []string               `protobuf:"bytes,1,rep,name=ceps,proto3" json:"ceps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetTemperatureRequest) Reset() {
+	*x = BatchGetTemperatureRequest{}
+	mi := &file_temperature_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetTemperatureRequest) ProtoMessage() {}
+
+func (x *BatchGetTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_temperature_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BatchGetTemperatureRequest) GetCeps() []string {
+	if x != nil {
+		return x.Ceps
+	}
+	return nil
+}
+
+type BatchGetTemperatureResponse struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Results       []*BatchTemperatureResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetTemperatureResponse) Reset() {
+	*x = BatchGetTemperatureResponse{}
+	mi := &file_temperature_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetTemperatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetTemperatureResponse) ProtoMessage() {}
+
+func (x *BatchGetTemperatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetTemperatureResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetTemperatureResponse) Descriptor() ([]byte, []int) {
+	return file_temperature_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchGetTemperatureResponse) GetResults() []*BatchTemperatureResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Resultado de um CEP do lote: status é ok, invalid, not_found ou upstream_error
+type BatchTemperatureResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cep           string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Temperature   *TemperatureResponse   `protobuf:"bytes,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchTemperatureResult) Reset() {
+	*x = BatchTemperatureResult{}
+	mi := &file_temperature_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchTemperatureResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchTemperatureResult) ProtoMessage() {}
+
+func (x *BatchTemperatureResult) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchTemperatureResult.ProtoReflect.Descriptor instead.
+func (*BatchTemperatureResult) Descriptor() ([]byte, []int) {
+	return file_temperature_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchTemperatureResult) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *BatchTemperatureResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BatchTemperatureResult) GetTemperature() *TemperatureResponse {
+	if x != nil {
+		return x.Temperature
+	}
+	return nil
+}
+
+func (x *BatchTemperatureResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type WatchTemperatureRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Cep   string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	// Ignorado: as consultas ao provedor de clima são compartilhadas por município, a cada
+	// STREAM_INTERVAL
+	IntervalSeconds uint32 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchTemperatureRequest) Reset() {
+	*x = WatchTemperatureRequest{}
+	mi := &file_temperature_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTemperatureRequest) ProtoMessage() {}
+
+func (x *WatchTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*WatchTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_temperature_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchTemperatureRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *WatchTemperatureRequest) GetIntervalSeconds() uint32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type TemperatureUpdate struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Cep         string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	Temperature *TemperatureResponse   `protobuf:"bytes,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Horário da observação informado pelo provedor, quando houver
+	LastUpdated   string `protobuf:"bytes,3,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemperatureUpdate) Reset() {
+	*x = TemperatureUpdate{}
+	mi := &file_temperature_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemperatureUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemperatureUpdate) ProtoMessage() {}
+
+func (x *TemperatureUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_temperature_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemperatureUpdate.ProtoReflect.Descriptor instead.
+func (*TemperatureUpdate) Descriptor() ([]byte, []int) {
+	return file_temperature_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TemperatureUpdate) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *TemperatureUpdate) GetTemperature() *TemperatureResponse {
+	if x != nil {
+		return x.Temperature
+	}
+	return nil
+}
+
+func (x *TemperatureUpdate) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+var File_temperature_service_proto protoreflect.FileDescriptor
+
+const file_temperature_service_proto_rawDesc = "" +
+	"\n" +
+	"\x19temperature_service.proto\x12\x11ceptemperatura.v1\x1a\x11temperature.proto\")\n" +
+	"\x15GetTemperatureRequest\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\"0\n" +
+	"\x1aBatchGetTemperatureRequest\x12\x12\n" +
+	"\x04ceps\x18\x01 \x03(\tR\x04ceps\"b\n" +
+	"\x1bBatchGetTemperatureResponse\x12C\n" +
+	"\aresults\x18\x01 \x03(\v2).ceptemperatura.v1.BatchTemperatureResultR\aresults\"\xa6\x01\n" +
+	"\x16BatchTemperatureResult\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12H\n" +
+	"\vtemperature\x18\x03 \x01(\v2&.ceptemperatura.v1.TemperatureResponseR\vtemperature\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"V\n" +
+	"\x17WatchTemperatureRequest\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\rR\x0fintervalSeconds\"\x92\x01\n" +
+	"\x11TemperatureUpdate\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x12H\n" +
+	"\vtemperature\x18\x02 \x01(\v2&.ceptemperatura.v1.TemperatureResponseR\vtemperature\x12!\n" +
+	"\flast_updated\x18\x03 \x01(\tR\vlastUpdated2\xd6\x02\n" +
+	"\x12TemperatureService\x12b\n" +
+	"\x0eGetTemperature\x12(.ceptemperatura.v1.GetTemperatureRequest\x1a&.ceptemperatura.v1.TemperatureResponse\x12t\n" +
+	"\x13BatchGetTemperature\x12-.ceptemperatura.v1.BatchGetTemperatureRequest\x1a..ceptemperatura.v1.BatchGetTemperatureResponse\x12f\n" +
+	"\x10WatchTemperature\x12*.ceptemperatura.v1.WatchTemperatureRequest\x1a$.ceptemperatura.v1.TemperatureUpdate0\x01B\x1dZ\x1bcep-temperatura/internal/pbb\x06proto3"
+
+var (
+	file_temperature_service_proto_rawDescOnce sync.Once
+	file_temperature_service_proto_rawDescData []byte
+)
+
+func file_temperature_service_proto_rawDescGZIP() []byte {
+	file_temperature_service_proto_rawDescOnce.Do(func() {
+		file_temperature_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_temperature_service_proto_rawDesc), len(file_temperature_service_proto_rawDesc)))
+	})
+	return file_temperature_service_proto_rawDescData
+}
+
+var file_temperature_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_temperature_service_proto_goTypes = []any{
+	(*GetTemperatureRequest)(nil),       // 0: ceptemperatura.v1.GetTemperatureRequest
+	(*BatchGetTemperatureRequest)(nil),  // 1: ceptemperatura.v1.BatchGetTemperatureRequest
+	(*BatchGetTemperatureResponse)(nil), // 2: ceptemperatura.v1.BatchGetTemperatureResponse
+	(*BatchTemperatureResult)(nil),      // 3: ceptemperatura.v1.BatchTemperatureResult
+	(*WatchTemperatureRequest)(nil),     // 4: ceptemperatura.v1.WatchTemperatureRequest
+	(*TemperatureUpdate)(nil),           // 5: ceptemperatura.v1.TemperatureUpdate
+	(*TemperatureResponse)(nil),         // 6: ceptemperatura.v1.TemperatureResponse
+}
+var file_temperature_service_proto_depIdxs = []int32{
+	3, // 0: ceptemperatura.v1.BatchGetTemperatureResponse.results:type_name -> ceptemperatura.v1.BatchTemperatureResult
+	6, // 1: ceptemperatura.v1.BatchTemperatureResult.temperature:type_name -> ceptemperatura.v1.TemperatureResponse
+	6, // 2: ceptemperatura.v1.TemperatureUpdate.temperature:type_name -> ceptemperatura.v1.TemperatureResponse
+	0, // 3: ceptemperatura.v1.TemperatureService.GetTemperature:input_type -> ceptemperatura.v1.GetTemperatureRequest
+	1, // 4: ceptemperatura.v1.TemperatureService.BatchGetTemperature:input_type -> ceptemperatura.v1.BatchGetTemperatureRequest
+	4, // 5: ceptemperatura.v1.TemperatureService.WatchTemperature:input_type -> ceptemperatura.v1.WatchTemperatureRequest
+	6, // 6: ceptemperatura.v1.TemperatureService.GetTemperature:output_type -> ceptemperatura.v1.TemperatureResponse
+	2, // 7: ceptemperatura.v1.TemperatureService.BatchGetTemperature:output_type -> ceptemperatura.v1.BatchGetTemperatureResponse
+	5, // 8: ceptemperatura.v1.TemperatureService.WatchTemperature:output_type -> ceptemperatura.v1.TemperatureUpdate
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_temperature_service_proto_init() }
+func file_temperature_service_proto_init() {
+	if File_temperature_service_proto != nil {
+		return
+	}
+	file_temperature_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_temperature_service_proto_rawDesc), len(file_temperature_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_temperature_service_proto_goTypes,
+		DependencyIndexes: file_temperature_service_proto_depIdxs,
+		MessageInfos:      file_temperature_service_proto_msgTypes,
+	}.Build()
+	File_temperature_service_proto = out.File
+	file_temperature_service_proto_goTypes = nil
+	file_temperature_service_proto_depIdxs = nil
+}
@@ -0,0 +1,213 @@
+// Serviço gRPC de temperatura, servido ao lado da API HTTP (GRPC_PORT).
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: temperature_service.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TemperatureService_GetTemperature_FullMethodName      = "/ceptemperatura.v1.TemperatureService/GetTemperature"
+	TemperatureService_BatchGetTemperature_FullMethodName = "/ceptemperatura.v1.TemperatureService/BatchGetTemperature"
+	TemperatureService_WatchTemperature_FullMethodName    = "/ceptemperatura.v1.TemperatureService/WatchTemperature"
+)
+
+// TemperatureServiceClient is the client API for TemperatureService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Temperatura por CEP, com os mesmos serviços de CEP e clima da API HTTP
+type TemperatureServiceClient interface {
+	// Temperatura atual de um CEP
+	GetTemperature(ctx context.Context, in *GetTemperatureRequest, opts ...grpc.CallOption) (*TemperatureResponse, error)
+	// Temperatura atual de vários CEPs, com o resultado de cada um na ordem recebida
+	BatchGetTemperature(ctx context.Context, in *BatchGetTemperatureRequest, opts ...grpc.CallOption) (*BatchGetTemperatureResponse, error)
+	// Acompanha a temperatura de um CEP, enviando uma atualização a cada mudança
+	WatchTemperature(ctx context.Context, in *WatchTemperatureRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TemperatureUpdate], error)
+}
+
+type temperatureServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTemperatureServiceClient(cc grpc.ClientConnInterface) TemperatureServiceClient {
+	return &temperatureServiceClient{cc}
+}
+
+func (c *temperatureServiceClient) GetTemperature(ctx context.Context, in *GetTemperatureRequest, opts ...grpc.CallOption) (*TemperatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TemperatureResponse)
+	err := c.cc.Invoke(ctx, TemperatureService_GetTemperature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *temperatureServiceClient) BatchGetTemperature(ctx context.Context, in *BatchGetTemperatureRequest, opts ...grpc.CallOption) (*BatchGetTemperatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetTemperatureResponse)
+	err := c.cc.Invoke(ctx, TemperatureService_BatchGetTemperature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *temperatureServiceClient) WatchTemperature(ctx context.Context, in *WatchTemperatureRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TemperatureUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TemperatureService_ServiceDesc.Streams[0], TemperatureService_WatchTemperature_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTemperatureRequest, TemperatureUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TemperatureService_WatchTemperatureClient = grpc.ServerStreamingClient[TemperatureUpdate]
+
+// TemperatureServiceServer is the server API for TemperatureService service.
+// All implementations must embed UnimplementedTemperatureServiceServer
+// for forward compatibility.
+//
+// Temperatura por CEP, com os mesmos serviços de CEP e clima da API HTTP
+type TemperatureServiceServer interface {
+	// Temperatura atual de um CEP
+	GetTemperature(context.Context, *GetTemperatureRequest) (*TemperatureResponse, error)
+	// Temperatura atual de vários CEPs, com o resultado de cada um na ordem recebida
+	BatchGetTemperature(context.Context, *BatchGetTemperatureRequest) (*BatchGetTemperatureResponse, error)
+	// Acompanha a temperatura de um CEP, enviando uma atualização a cada mudança
+	WatchTemperature(*WatchTemperatureRequest, grpc.ServerStreamingServer[TemperatureUpdate]) error
+	mustEmbedUnimplementedTemperatureServiceServer()
+}
+
+// UnimplementedTemperatureServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTemperatureServiceServer struct{}
+
+func (UnimplementedTemperatureServiceServer) GetTemperature(context.Context, *GetTemperatureRequest) (*TemperatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemperature not implemented")
+}
+func (UnimplementedTemperatureServiceServer) BatchGetTemperature(context.Context, *BatchGetTemperatureRequest) (*BatchGetTemperatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetTemperature not implemented")
+}
+func (UnimplementedTemperatureServiceServer) WatchTemperature(*WatchTemperatureRequest, grpc.ServerStreamingServer[TemperatureUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTemperature not implemented")
+}
+func (UnimplementedTemperatureServiceServer) mustEmbedUnimplementedTemperatureServiceServer() {}
+func (UnimplementedTemperatureServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeTemperatureServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TemperatureServiceServer will
+// result in compilation errors.
+type UnsafeTemperatureServiceServer interface {
+	mustEmbedUnimplementedTemperatureServiceServer()
+}
+
+func RegisterTemperatureServiceServer(s grpc.ServiceRegistrar, srv TemperatureServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTemperatureServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TemperatureService_ServiceDesc, srv)
+}
+
+func _TemperatureService_GetTemperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemperatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemperatureServiceServer).GetTemperature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemperatureService_GetTemperature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemperatureServiceServer).GetTemperature(ctx, req.(*GetTemperatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemperatureService_BatchGetTemperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetTemperatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemperatureServiceServer).BatchGetTemperature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemperatureService_BatchGetTemperature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemperatureServiceServer).BatchGetTemperature(ctx, req.(*BatchGetTemperatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemperatureService_WatchTemperature_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTemperatureRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TemperatureServiceServer).WatchTemperature(m, &grpc.GenericServerStream[WatchTemperatureRequest, TemperatureUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TemperatureService_WatchTemperatureServer = grpc.ServerStreamingServer[TemperatureUpdate]
+
+// TemperatureService_ServiceDesc is the grpc.ServiceDesc for TemperatureService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TemperatureService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ceptemperatura.v1.TemperatureService",
+	HandlerType: (*TemperatureServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTemperature",
+			Handler:    _TemperatureService_GetTemperature_Handler,
+		},
+		{
+			MethodName: "BatchGetTemperature",
+			Handler:    _TemperatureService_BatchGetTemperature_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTemperature",
+			Handler:       _TemperatureService_WatchTemperature_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "temperature_service.proto",
+}
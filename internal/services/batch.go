@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"cep-temperatura/internal/models"
+)
+
+// BatchResult é o resultado de um CEP de uma consulta em lote: o clima do município, ou o
+// erro da validação, da consulta de CEP ou da consulta de clima
+type BatchResult struct {
+	CEP      string
+	Location *models.CEPResponse
+	Weather  *models.WeatherResult
+	Err      error
+}
+
+// BatchLookup consulta a temperatura de vários CEPs. CEPs repetidos são consultados uma única
+// vez, e CEPs do mesmo município compartilham a consulta de clima.
+type BatchLookup struct {
+	CEPService     CEPService
+	WeatherService WeatherService
+	// Workers limita o número de consultas simultâneas
+	Workers int
+	// CEPContext e WeatherContext definem o prazo de cada consulta; sem eles, as consultas
+	// ficam limitadas apenas pelo contexto do lote
+	CEPContext     func(context.Context) (context.Context, context.CancelFunc)
+	WeatherContext func(context.Context) (context.Context, context.CancelFunc)
+}
+
+type batchLocation struct {
+	location *models.CEPResponse
+	err      error
+}
+
+type batchWeather struct {
+	query   models.WeatherQuery
+	weather *models.WeatherResult
+	err     error
+}
+
+// Lookup devolve um resultado por CEP, na ordem recebida. A falha de um CEP não afeta os demais.
+func (b BatchLookup) Lookup(ctx context.Context, ceps []string) []BatchResult {
+	results := make([]BatchResult, len(ceps))
+
+	// Validar e deduplicar os CEPs
+	locations := map[string]*batchLocation{}
+	var unique []string
+	for i, cep := range ceps {
+		results[i].CEP = cep
+		if !b.CEPService.ValidateCEP(cep) {
+			results[i].Err = ErrInvalidCEP
+			continue
+		}
+		if key := formatCEP(cep); locations[key] == nil {
+			locations[key] = &batchLocation{}
+			unique = append(unique, cep)
+		}
+	}
+
+	// Buscar a localização de cada CEP distinto
+	runBounded(len(unique), b.Workers, func(i int) {
+		cepCtx, cancel := withDeadline(ctx, b.CEPContext)
+		defer cancel()
+
+		lookup := locations[formatCEP(unique[i])]
+		lookup.location, lookup.err = b.CEPService.GetLocation(cepCtx, unique[i])
+	})
+
+	// Agrupar os CEPs por município
+	municipalities := map[string]*batchWeather{}
+	var queries []*batchWeather
+	for _, cep := range unique {
+		lookup := locations[formatCEP(cep)]
+		if lookup.err != nil {
+			continue
+		}
+		key := MunicipalityKey(lookup.location)
+		if municipalities[key] == nil {
+			municipalities[key] = &batchWeather{query: WeatherQueryFor(lookup.location)}
+			queries = append(queries, municipalities[key])
+		}
+	}
+
+	// Buscar o clima de cada município distinto
+	runBounded(len(queries), b.Workers, func(i int) {
+		weatherCtx, cancel := withDeadline(ctx, b.WeatherContext)
+		defer cancel()
+
+		queries[i].weather, queries[i].err = b.WeatherService.GetTemperature(weatherCtx, queries[i].query)
+	})
+
+	// Montar os resultados na ordem recebida
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		lookup := locations[formatCEP(results[i].CEP)]
+		if lookup.err != nil {
+			results[i].Err = lookup.err
+			continue
+		}
+		weather := municipalities[MunicipalityKey(lookup.location)]
+		results[i].Location = lookup.location
+		results[i].Weather, results[i].Err = weather.weather, weather.err
+	}
+
+	return results
+}
+
+func withDeadline(ctx context.Context, deadline func(context.Context) (context.Context, context.CancelFunc)) (context.Context, context.CancelFunc) {
+	if deadline == nil {
+		return context.WithCancel(ctx)
+	}
+	return deadline(ctx)
+}
+
+// WeatherQueryFor identifica, para a consulta de clima, o município do CEP
+func WeatherQueryFor(location *models.CEPResponse) models.WeatherQuery {
+	return models.WeatherQuery{
+		City:  location.Localidade,
+		State: location.UF,
+		IBGE:  location.IBGE,
+	}
+}
+
+// MunicipalityKey identifica o município do CEP pelo código IBGE ou, sem ele, pela UF e cidade
+func MunicipalityKey(location *models.CEPResponse) string {
+	if location.IBGE != "" {
+		return location.IBGE
+	}
+	return strings.ToUpper(location.UF) + "/" + strings.ToLower(location.Localidade)
+}
+
+// runBounded executa fn para cada índice de 0 a n-1 com no máximo workers execuções simultâneas
+func runBounded(n, workers int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(n, max(workers, 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
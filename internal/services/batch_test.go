@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCEPService struct {
+	mock.Mock
+}
+
+func (m *mockCEPService) ValidateCEP(cep string) bool { return validateCEP(cep) }
+
+func (m *mockCEPService) GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CEPResponse), args.Error(1)
+}
+
+type mockWeatherService struct {
+	mock.Mock
+}
+
+func (m *mockWeatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResult), args.Error(1)
+}
+
+func (m *mockWeatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options ForecastOptions) (*models.ForecastResult, error) {
+	return nil, ErrForecastUnsupported
+}
+
+func (m *mockWeatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options HistoryOptions) (*models.HistoryResult, error) {
+	return nil, ErrHistoryUnsupported
+}
+
+func TestBatchLookup_Lookup(t *testing.T) {
+	cepService := new(mockCEPService)
+	weatherService := new(mockWeatherService)
+
+	saoPaulo := &models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	cepService.On("GetLocation", mock.Anything, "01310100").Return(saoPaulo, nil).Once()
+	cepService.On("GetLocation", mock.Anything, "01001000").Return(saoPaulo, nil).Once()
+	cepService.On("GetLocation", mock.Anything, "99999999").Return(nil, ErrCEPNotFound).Once()
+	weatherService.On("GetTemperature", mock.Anything, WeatherQueryFor(saoPaulo)).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+
+	lookup := BatchLookup{CEPService: cepService, WeatherService: weatherService, Workers: 2}
+	results := lookup.Lookup(context.Background(), []string{"01310100", "123", "01310-100", "99999999", "01001000"})
+
+	assert.Len(t, results, 5)
+	assert.Equal(t, 25.0, results[0].Weather.TempC)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidCEP)
+	assert.Equal(t, "01310-100", results[2].CEP)
+	assert.Equal(t, 25.0, results[2].Weather.TempC)
+	assert.ErrorIs(t, results[3].Err, ErrCEPNotFound)
+	assert.Equal(t, saoPaulo, results[4].Location)
+
+	// CEPs repetidos e do mesmo município são consultados uma única vez
+	cepService.AssertExpectations(t)
+	weatherService.AssertExpectations(t)
+}
+
+func TestMunicipalityKey(t *testing.T) {
+	assert.Equal(t, "3550308", MunicipalityKey(&models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}))
+	assert.Equal(t, "SP/são paulo", MunicipalityKey(&models.CEPResponse{Localidade: "São Paulo", UF: "sp"}))
+}
+
+func TestRunBounded(t *testing.T) {
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	var seen []int
+
+	runBounded(20, 3, func(i int) {
+		current := running.Add(1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+
+		mu.Lock()
+		seen = append(seen, i)
+		mu.Unlock()
+	})
+
+	assert.Len(t, seen, 20)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
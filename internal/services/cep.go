@@ -1,33 +1,43 @@
 package services
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
+	"context"
+	"errors"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
+	"cep-temperatura/internal/config"
 	"cep-temperatura/internal/models"
 )
 
 // CEPService interface para operações de CEP
 type CEPService interface {
 	ValidateCEP(cep string) bool
-	GetLocation(cep string) (*models.CEPResponse, error)
+	GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error)
 }
 
 type cepService struct {
-	baseURL string
-	client  *http.Client
+	providers  []CEPProvider
+	mode       string
+	hedgeDelay time.Duration
+	stats      map[string]*providerCounters
 }
 
 // NewCEPService cria uma nova instância do serviço de CEP
-func NewCEPService() CEPService {
-	return &cepService{
-		baseURL: "https://viacep.com.br/ws",
-		client:  &http.Client{},
+func NewCEPService(cfg *config.Config) (CEPService, error) {
+	providers, err := newCEPProviders(cfg, &http.Client{Timeout: cfg.Server.RequestTimeout})
+	if err != nil {
+		return nil, err
 	}
+
+	return &cepService{
+		providers:  providers,
+		mode:       cfg.CEP.Mode,
+		hedgeDelay: cfg.CEP.HedgeDelay,
+		stats:      newProviderCounters(providers),
+	}, nil
 }
 
 // ValidateCEP valida se o CEP está no formato correto
@@ -35,49 +45,73 @@ func (s *cepService) ValidateCEP(cep string) bool {
 	return validateCEP(cep)
 }
 
-// GetLocation busca a localização pelo CEP
-func (s *cepService) GetLocation(cep string) (*models.CEPResponse, error) {
+// GetLocation busca a localização pelo CEP usando os provedores configurados
+func (s *cepService) GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
 	if !s.ValidateCEP(cep) {
-		return nil, fmt.Errorf("invalid zipcode")
+		return nil, ErrInvalidCEP
 	}
 
 	formattedCEP := formatCEP(cep)
-	url := fmt.Sprintf("%s/%s/json/", s.baseURL, formattedCEP)
 
-	resp, err := s.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao consultar CEP: %w", err)
+	if s.mode == config.CEPModeRace && len(s.providers) > 1 {
+		return s.raceLookup(ctx, formattedCEP)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao ler resposta: %w", err)
-	}
+	return s.sequentialLookup(ctx, formattedCEP)
+}
 
-	var cepResponse models.CEPResponse
-	if err := json.Unmarshal(body, &cepResponse); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+// sequentialLookup tenta os provedores um a um, na ordem configurada
+func (s *cepService) sequentialLookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	var lastErr error
+	for _, provider := range s.providers {
+		// Prazo esgotado ou cliente desconectado: não adianta tentar o próximo provedor
+		if err := ctx.Err(); err != nil {
+			return nil, contextError(err)
+		}
+
+		location, err := provider.Lookup(ctx, cep)
+		if err == nil {
+			location.Provider = provider.Name()
+			return location, nil
+		}
+		lastErr = mergeLookupError(lastErr, err)
 	}
 
-	if cepResponse.Erro {
-		return nil, fmt.Errorf("can not find zipcode")
+	return nil, finalLookupError(lastErr)
+}
+
+// mergeLookupError mantém o erro mais relevante: um "não encontrado" não sobrepõe falhas de outros provedores
+func mergeLookupError(lastErr, err error) error {
+	if lastErr == nil || !errors.Is(err, ErrCEPNotFound) {
+		return err
 	}
+	return lastErr
+}
 
-	return &cepResponse, nil
+// finalLookupError define o erro retornado quando nenhum provedor respondeu com sucesso
+func finalLookupError(lastErr error) error {
+	if lastErr == nil || errors.Is(lastErr, ErrCEPNotFound) {
+		return ErrCEPNotFound
+	}
+	return lastErr
 }
 
 // validateCEP valida se o CEP está no formato correto (8 dígitos)
 func validateCEP(cep string) bool {
 	// Remove hífens e espaços
-	cleanCEP := strings.ReplaceAll(cep, "-", "")
-	cleanCEP = strings.ReplaceAll(cleanCEP, " ", "")
+	cleanCEP := formatCEP(cep)
 
 	// Verifica se tem exatamente 8 dígitos
 	matched, _ := regexp.MatchString(`^\d{8}$`, cleanCEP)
 	return matched
 }
 
+// NormalizeCEP remove do CEP os hífens e espaços que a validação aceita; CEPs iguais depois
+// de normalizados são o mesmo CEP
+func NormalizeCEP(cep string) string {
+	return formatCEP(cep)
+}
+
 // formatCEP formata o CEP removendo hífens e espaços
 func formatCEP(cep string) string {
 	cleanCEP := strings.ReplaceAll(cep, "-", "")
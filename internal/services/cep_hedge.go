@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"cep-temperatura/internal/models"
+)
+
+// ProviderStats contabiliza o desempenho de um provedor de CEP no modo race
+type ProviderStats struct {
+	Wins   int64 `json:"wins"`
+	Losses int64 `json:"losses"`
+}
+
+// ProviderStatsReporter expõe as estatísticas por provedor para ajuste do hedge
+type ProviderStatsReporter interface {
+	ProviderStats() map[string]ProviderStats
+}
+
+type providerCounters struct {
+	wins   atomic.Int64
+	losses atomic.Int64
+}
+
+func newProviderCounters(providers []CEPProvider) map[string]*providerCounters {
+	stats := make(map[string]*providerCounters, len(providers))
+	for _, provider := range providers {
+		stats[provider.Name()] = &providerCounters{}
+	}
+	return stats
+}
+
+// ProviderStats retorna uma cópia das vitórias e derrotas de cada provedor
+func (s *cepService) ProviderStats() map[string]ProviderStats {
+	stats := make(map[string]ProviderStats, len(s.stats))
+	for name, counters := range s.stats {
+		stats[name] = ProviderStats{
+			Wins:   counters.wins.Load(),
+			Losses: counters.losses.Load(),
+		}
+	}
+	return stats
+}
+
+type lookupResult struct {
+	provider CEPProvider
+	location *models.CEPResponse
+	err      error
+}
+
+// raceLookup consulta o primeiro provedor e, a cada hedgeDelay sem resposta (ou a cada falha),
+// dispara o próximo. A primeira resposta válida vence e as demais consultas são canceladas.
+func (s *cepService) raceLookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan lookupResult, len(s.providers))
+	launched := make([]CEPProvider, 0, len(s.providers))
+
+	launch := func() {
+		provider := s.providers[len(launched)]
+		launched = append(launched, provider)
+		go func() {
+			location, err := provider.Lookup(ctx, cep)
+			results <- lookupResult{provider: provider, location: location, err: err}
+		}()
+	}
+
+	launch()
+	inFlight := 1
+	hedge := time.After(s.hedgeDelay)
+
+	var lastErr error
+	for inFlight > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			if len(launched) < len(s.providers) {
+				launch()
+				inFlight++
+				hedge = time.After(s.hedgeDelay)
+			}
+		case result := <-results:
+			inFlight--
+			if result.err == nil {
+				s.recordRace(launched, result.provider)
+				result.location.Provider = result.provider.Name()
+				return result.location, nil
+			}
+			lastErr = mergeLookupError(lastErr, result.err)
+			// Sem consultas pendentes, não faz sentido esperar o hedge para tentar o próximo
+			if inFlight == 0 && len(launched) < len(s.providers) {
+				launch()
+				inFlight++
+				hedge = time.After(s.hedgeDelay)
+			}
+		case <-ctx.Done():
+			s.recordRace(launched, nil)
+			return nil, contextError(ctx.Err())
+		}
+	}
+
+	s.recordRace(launched, nil)
+	return nil, finalLookupError(lastErr)
+}
+
+// recordRace registra a vitória do provedor vencedor e a derrota dos demais disparados
+func (s *cepService) recordRace(launched []CEPProvider, winner CEPProvider) {
+	for _, provider := range launched {
+		counters, ok := s.stats[provider.Name()]
+		if !ok {
+			continue
+		}
+		if provider == winner {
+			counters.wins.Add(1)
+		} else {
+			counters.losses.Add(1)
+		}
+	}
+}
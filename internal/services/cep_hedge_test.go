@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCEPService_RaceMode_HedgesSlowProvider(t *testing.T) {
+	var cancelled atomic.Bool
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled.Store(true)
+		case <-time.After(2 * time.Second):
+			w.Write([]byte(`{"cep":"01310-100","localidade":"Lenta","uf":"SP"}`))
+		}
+	}))
+	defer slow.Close()
+	fast := newJSONServer(http.StatusOK, `{"cep":"01310100","state":"SP","city":"São Paulo"}`)
+	defer fast.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI},
+		slow.URL, fast.URL, "", "",
+	)
+	cfg.CEP.Mode = config.CEPModeRace
+	cfg.CEP.HedgeDelay = 20 * time.Millisecond
+	service := mustNewCEPService(t, cfg)
+
+	start := time.Now()
+	location, err := service.GetLocation(context.Background(), "01310100")
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "São Paulo", location.Localidade)
+	assert.Equal(t, config.CEPProviderBrasilAPI, location.Provider)
+
+	stats := service.(ProviderStatsReporter).ProviderStats()
+	assert.Equal(t, ProviderStats{Wins: 0, Losses: 1}, stats[config.CEPProviderViaCEP])
+	assert.Equal(t, ProviderStats{Wins: 1, Losses: 0}, stats[config.CEPProviderBrasilAPI])
+
+	assert.Eventually(t, cancelled.Load, time.Second, 10*time.Millisecond)
+}
+
+func TestCEPService_RaceMode_FastProviderSkipsHedge(t *testing.T) {
+	var hedged atomic.Bool
+	fast := newJSONServer(http.StatusOK, `{"cep":"01310-100","localidade":"São Paulo","uf":"SP"}`)
+	defer fast.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hedged.Store(true)
+	}))
+	defer backup.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI},
+		fast.URL, backup.URL, "", "",
+	)
+	cfg.CEP.Mode = config.CEPModeRace
+	cfg.CEP.HedgeDelay = time.Second
+	service := mustNewCEPService(t, cfg)
+
+	location, err := service.GetLocation(context.Background(), "01310100")
+	assert.NoError(t, err)
+	assert.Equal(t, config.CEPProviderViaCEP, location.Provider)
+	assert.False(t, hedged.Load())
+}
+
+func TestCEPService_RaceMode_AllProvidersFail(t *testing.T) {
+	notFound := newJSONServer(http.StatusOK, `{"erro": true}`)
+	defer notFound.Close()
+	unavailable := newJSONServer(http.StatusServiceUnavailable, `unavailable`)
+	defer unavailable.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI},
+		notFound.URL, unavailable.URL, "", "",
+	)
+	cfg.CEP.Mode = config.CEPModeRace
+	cfg.CEP.HedgeDelay = time.Second
+	service := mustNewCEPService(t, cfg)
+
+	_, err := service.GetLocation(context.Background(), "01310100")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status 503")
+
+	stats := service.(ProviderStatsReporter).ProviderStats()
+	assert.Equal(t, int64(1), stats[config.CEPProviderViaCEP].Losses)
+	assert.Equal(t, int64(1), stats[config.CEPProviderBrasilAPI].Losses)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+)
+
+// embeddedCEPDataset é a amostra usada quando nenhum arquivo é configurado
+//
+//go:embed data/ceps.csv
+var embeddedCEPDataset string
+
+// offlineCEPProvider responde a partir de um conjunto de dados local indexado em memória
+type offlineCEPProvider struct {
+	index map[string]models.CEPResponse
+}
+
+// newOfflineCEPProvider carrega o conjunto de dados configurado ou, sem caminho, a amostra embutida
+func newOfflineCEPProvider(dataset config.CEPDataset) (*offlineCEPProvider, error) {
+	if dataset.Path == "" {
+		index, err := LoadCEPDataset(strings.NewReader(embeddedCEPDataset), config.CEPDatasetCSV)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar base de CEPs embutida: %w", err)
+		}
+		return &offlineCEPProvider{index: index}, nil
+	}
+
+	file, err := os.Open(dataset.Path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir base de CEPs: %w", err)
+	}
+	defer file.Close()
+
+	format := dataset.Format
+	if format == "" {
+		format = datasetFormatFromPath(dataset.Path)
+	}
+
+	index, err := LoadCEPDataset(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar base de CEPs %s: %w", dataset.Path, err)
+	}
+
+	return &offlineCEPProvider{index: index}, nil
+}
+
+func (p *offlineCEPProvider) Name() string { return config.CEPProviderOffline }
+
+// Lookup consulta o CEP no índice em memória
+func (p *offlineCEPProvider) Lookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	location, ok := p.index[cep]
+	if !ok {
+		return nil, ErrCEPNotFound
+	}
+	return &location, nil
+}
+
+// datasetFormatFromPath deduz o formato pela extensão do arquivo
+func datasetFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return config.CEPDatasetNDJSON
+	default:
+		return config.CEPDatasetCSV
+	}
+}
+
+// LoadCEPDataset lê um export CSV ou NDJSON de CEPs e o indexa pelo CEP sem formatação.
+// O CSV deve ter cabeçalho com as colunas cep, logradouro, bairro, localidade, uf e ibge
+// (em qualquer ordem); no NDJSON, cada linha é um objeto com os mesmos campos da ViaCEP.
+func LoadCEPDataset(r io.Reader, format string) (map[string]models.CEPResponse, error) {
+	switch format {
+	case config.CEPDatasetCSV:
+		return loadCEPCSV(r)
+	case config.CEPDatasetNDJSON:
+		return loadCEPNDJSON(r)
+	default:
+		return nil, fmt.Errorf("formato de base de CEPs desconhecido: %s", format)
+	}
+}
+
+func loadCEPCSV(r io.Reader) (map[string]models.CEPResponse, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["cep"]; !ok {
+		return nil, errors.New("coluna cep ausente no cabeçalho")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	index := make(map[string]models.CEPResponse)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+
+		location := models.CEPResponse{
+			CEP:        field(record, "cep"),
+			Logradouro: field(record, "logradouro"),
+			Bairro:     field(record, "bairro"),
+			Localidade: field(record, "localidade"),
+			UF:         field(record, "uf"),
+			IBGE:       field(record, "ibge"),
+		}
+		if err := addToIndex(index, location); err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+	}
+
+	return index, nil
+}
+
+func loadCEPNDJSON(r io.Reader) (map[string]models.CEPResponse, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	index := make(map[string]models.CEPResponse)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		var location models.CEPResponse
+		if err := json.Unmarshal([]byte(raw), &location); err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+		if err := addToIndex(index, location); err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler base de CEPs: %w", err)
+	}
+
+	return index, nil
+}
+
+// addToIndex valida o CEP do registro e o adiciona ao índice
+func addToIndex(index map[string]models.CEPResponse, location models.CEPResponse) error {
+	if !validateCEP(location.CEP) {
+		return fmt.Errorf("CEP inválido: %q", location.CEP)
+	}
+
+	key := formatCEP(location.CEP)
+	location.CEP = key[:5] + "-" + key[5:]
+	index[key] = location
+	return nil
+}
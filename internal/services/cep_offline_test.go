@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cep-temperatura/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCEPDataset_CSV(t *testing.T) {
+	data := "uf,cep,localidade,ibge,bairro,logradouro\n" +
+		"SP,01310-100,São Paulo,3550308,Bela Vista,Avenida Paulista\n" +
+		"RJ,20040002,Rio de Janeiro,3304557,Centro,Avenida Rio Branco\n"
+
+	index, err := LoadCEPDataset(strings.NewReader(data), config.CEPDatasetCSV)
+	assert.NoError(t, err)
+	assert.Len(t, index, 2)
+	assert.Equal(t, "São Paulo", index["01310100"].Localidade)
+	assert.Equal(t, "01310-100", index["01310100"].CEP)
+	assert.Equal(t, "3304557", index["20040002"].IBGE)
+}
+
+func TestLoadCEPDataset_NDJSON(t *testing.T) {
+	data := `{"cep":"01310-100","logradouro":"Avenida Paulista","localidade":"São Paulo","uf":"SP","ibge":"3550308"}` + "\n\n" +
+		`{"cep":"70150900","localidade":"Brasília","uf":"DF","ibge":"5300108"}` + "\n"
+
+	index, err := LoadCEPDataset(strings.NewReader(data), config.CEPDatasetNDJSON)
+	assert.NoError(t, err)
+	assert.Len(t, index, 2)
+	assert.Equal(t, "Brasília", index["70150900"].Localidade)
+}
+
+func TestLoadCEPDataset_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		format   string
+		expected string
+	}{
+		{
+			name:     "CSV sem coluna cep",
+			data:     "localidade,uf\nSão Paulo,SP\n",
+			format:   config.CEPDatasetCSV,
+			expected: "coluna cep ausente",
+		},
+		{
+			name:     "CSV com CEP inválido",
+			data:     "cep,localidade\n123,São Paulo\n",
+			format:   config.CEPDatasetCSV,
+			expected: "linha 2",
+		},
+		{
+			name:     "NDJSON malformado",
+			data:     `{"cep":"01310100"}` + "\n{not json}\n",
+			format:   config.CEPDatasetNDJSON,
+			expected: "linha 2",
+		},
+		{
+			name:     "formato desconhecido",
+			data:     "",
+			format:   "xml",
+			expected: "formato de base de CEPs desconhecido",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCEPDataset(strings.NewReader(tt.data), tt.format)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestCEPService_OfflineProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ceps.ndjson")
+	data := `{"cep":"01310100","localidade":"São Paulo","uf":"SP","ibge":"3550308"}` + "\n"
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	cfg := newProvidersConfig([]string{config.CEPProviderOffline}, "", "", "", "")
+	cfg.CEP.Dataset.Path = path
+	service := mustNewCEPService(t, cfg)
+
+	location, err := service.GetLocation(context.Background(), "01310-100")
+	assert.NoError(t, err)
+	assert.Equal(t, "São Paulo", location.Localidade)
+	assert.Equal(t, config.CEPProviderOffline, location.Provider)
+
+	_, err = service.GetLocation(context.Background(), "99999999")
+	assert.ErrorIs(t, err, ErrCEPNotFound)
+}
+
+func TestCEPService_OfflineFallback(t *testing.T) {
+	viaCEP := newJSONServer(http.StatusServiceUnavailable, `unavailable`)
+	defer viaCEP.Close()
+
+	cfg := newProvidersConfig([]string{config.CEPProviderViaCEP}, viaCEP.URL, "", "", "")
+	cfg.CEP.Dataset.Fallback = true
+	service := mustNewCEPService(t, cfg)
+
+	// Sem caminho configurado, a amostra embutida é usada
+	location, err := service.GetLocation(context.Background(), "01310100")
+	assert.NoError(t, err)
+	assert.Equal(t, "São Paulo", location.Localidade)
+	assert.Equal(t, config.CEPProviderOffline, location.Provider)
+}
+
+func TestNewCEPService_InvalidDatasetPath(t *testing.T) {
+	cfg := newProvidersConfig([]string{config.CEPProviderOffline}, "", "", "", "")
+	cfg.CEP.Dataset.Path = filepath.Join(t.TempDir(), "inexistente.csv")
+
+	_, err := NewCEPService(cfg)
+	assert.ErrorContains(t, err, "erro ao abrir base de CEPs")
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+)
+
+// CEPProvider representa uma fonte de consulta de CEP
+type CEPProvider interface {
+	Name() string
+	Lookup(ctx context.Context, cep string) (*models.CEPResponse, error)
+}
+
+// newCEPProviders monta a cadeia de provedores na ordem configurada. Com a base offline
+// habilitada como fallback, ela entra no fim da cadeia caso ainda não esteja na lista.
+func newCEPProviders(cfg *config.Config, client *http.Client) ([]CEPProvider, error) {
+	names := cfg.CEP.Providers
+	if cfg.CEP.Dataset.Fallback && !slices.Contains(names, config.CEPProviderOffline) {
+		names = append(slices.Clone(names), config.CEPProviderOffline)
+	}
+
+	providers := make([]CEPProvider, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case config.CEPProviderViaCEP:
+			providers = append(providers, &viaCEPProvider{baseURL: cfg.CEP.ViaCEPURL, client: client})
+		case config.CEPProviderBrasilAPI:
+			providers = append(providers, &brasilAPIProvider{baseURL: cfg.CEP.BrasilAPIURL, client: client})
+		case config.CEPProviderOpenCEP:
+			providers = append(providers, &openCEPProvider{baseURL: cfg.CEP.OpenCEPURL, client: client})
+		case config.CEPProviderAwesomeAPI:
+			providers = append(providers, &awesomeAPIProvider{baseURL: cfg.CEP.AwesomeAPIURL, client: client})
+		case config.CEPProviderOffline:
+			provider, err := newOfflineCEPProvider(cfg.CEP.Dataset)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+		}
+	}
+	return providers, nil
+}
+
+type viaCEPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (p *viaCEPProvider) Name() string { return config.CEPProviderViaCEP }
+
+// Lookup consulta o CEP na ViaCEP
+func (p *viaCEPProvider) Lookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	var cepResponse models.CEPResponse
+	url := fmt.Sprintf("%s/%s/json/", p.baseURL, cep)
+	if err := getJSON(ctx, p.client, url, &cepResponse); err != nil {
+		return nil, err
+	}
+
+	if cepResponse.Erro {
+		return nil, ErrCEPNotFound
+	}
+
+	return &cepResponse, nil
+}
+
+type brasilAPIProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (p *brasilAPIProvider) Name() string { return config.CEPProviderBrasilAPI }
+
+// Lookup consulta o CEP na BrasilAPI
+func (p *brasilAPIProvider) Lookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	var apiResponse models.BrasilAPIResponse
+	url := fmt.Sprintf("%s/%s", p.baseURL, cep)
+	if err := getJSON(ctx, p.client, url, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	return &models.CEPResponse{
+		CEP:        apiResponse.CEP,
+		Logradouro: apiResponse.Street,
+		Bairro:     apiResponse.Neighborhood,
+		Localidade: apiResponse.City,
+		UF:         apiResponse.State,
+	}, nil
+}
+
+type openCEPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (p *openCEPProvider) Name() string { return config.CEPProviderOpenCEP }
+
+// Lookup consulta o CEP na OpenCEP
+func (p *openCEPProvider) Lookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	var apiResponse models.OpenCEPResponse
+	url := fmt.Sprintf("%s/%s", p.baseURL, cep)
+	if err := getJSON(ctx, p.client, url, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	return &models.CEPResponse{
+		CEP:         apiResponse.CEP,
+		Logradouro:  apiResponse.Logradouro,
+		Complemento: apiResponse.Complemento,
+		Bairro:      apiResponse.Bairro,
+		Localidade:  apiResponse.Localidade,
+		UF:          apiResponse.UF,
+		IBGE:        apiResponse.IBGE,
+	}, nil
+}
+
+type awesomeAPIProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (p *awesomeAPIProvider) Name() string { return config.CEPProviderAwesomeAPI }
+
+// Lookup consulta o CEP na AwesomeAPI
+func (p *awesomeAPIProvider) Lookup(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	var apiResponse models.AwesomeAPIResponse
+	url := fmt.Sprintf("%s/%s", p.baseURL, cep)
+	if err := getJSON(ctx, p.client, url, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	return &models.CEPResponse{
+		CEP:        apiResponse.CEP,
+		Logradouro: apiResponse.Address,
+		Bairro:     apiResponse.District,
+		Localidade: apiResponse.City,
+		UF:         apiResponse.State,
+		IBGE:       apiResponse.CityIBGE,
+		DDD:        apiResponse.DDD,
+	}, nil
+}
+
+// getJSON executa um GET e decodifica o corpo JSON da resposta
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	status, body, err := fetch(ctx, client, url, "CEP")
+	if err != nil {
+		return err
+	}
+
+	if status == http.StatusNotFound {
+		return ErrCEPNotFound
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("erro ao consultar CEP: status %d: %w", status, classifyStatus(status))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta: %w: %w", ErrBadUpstreamPayload, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"cep-temperatura/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newProvidersConfig monta uma configuração apontando todos os provedores para servidores locais
+func newProvidersConfig(providers []string, viaCEP, brasilAPI, openCEP, awesomeAPI string) *config.Config {
+	return &config.Config{
+		CEP: config.CEPConfig{
+			Providers:     providers,
+			ViaCEPURL:     viaCEP,
+			BrasilAPIURL:  brasilAPI,
+			OpenCEPURL:    openCEP,
+			AwesomeAPIURL: awesomeAPI,
+		},
+	}
+}
+
+func mustNewCEPService(t *testing.T, cfg *config.Config) CEPService {
+	t.Helper()
+	service, err := NewCEPService(cfg)
+	if err != nil {
+		t.Fatalf("erro ao criar serviço de CEP: %v", err)
+	}
+	return service
+}
+
+func newJSONServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestCEPProviders_Mapping(t *testing.T) {
+	viaCEP := newJSONServer(http.StatusOK, `{"cep":"01310-100","logradouro":"Avenida Paulista","bairro":"Bela Vista","localidade":"São Paulo","uf":"SP","ibge":"3550308"}`)
+	defer viaCEP.Close()
+	brasilAPI := newJSONServer(http.StatusOK, `{"cep":"01310100","state":"SP","city":"São Paulo","neighborhood":"Bela Vista","street":"Avenida Paulista","service":"correios"}`)
+	defer brasilAPI.Close()
+	openCEP := newJSONServer(http.StatusOK, `{"cep":"01310-100","logradouro":"Avenida Paulista","bairro":"Bela Vista","localidade":"São Paulo","uf":"SP","ibge":"3550308"}`)
+	defer openCEP.Close()
+	awesomeAPI := newJSONServer(http.StatusOK, `{"cep":"01310100","address":"Avenida Paulista","state":"SP","district":"Bela Vista","city":"São Paulo","city_ibge":"3550308","ddd":"11"}`)
+	defer awesomeAPI.Close()
+
+	cfg := newProvidersConfig(nil, viaCEP.URL, brasilAPI.URL, openCEP.URL, awesomeAPI.URL)
+
+	tests := []string{
+		config.CEPProviderViaCEP,
+		config.CEPProviderBrasilAPI,
+		config.CEPProviderOpenCEP,
+		config.CEPProviderAwesomeAPI,
+	}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg.CEP.Providers = []string{name}
+			service := mustNewCEPService(t, cfg)
+
+			location, err := service.GetLocation(context.Background(), "01310-100")
+			assert.NoError(t, err)
+			assert.Equal(t, "São Paulo", location.Localidade)
+			assert.Equal(t, "SP", location.UF)
+			assert.Equal(t, "Avenida Paulista", location.Logradouro)
+			assert.Equal(t, name, location.Provider)
+		})
+	}
+}
+
+func TestCEPService_GetLocation_Fallback(t *testing.T) {
+	viaCEP := newJSONServer(http.StatusInternalServerError, `internal error`)
+	defer viaCEP.Close()
+	brasilAPI := newJSONServer(http.StatusNotFound, `{"message":"CEP não encontrado"}`)
+	defer brasilAPI.Close()
+	openCEP := newJSONServer(http.StatusOK, `{"cep":"01310-100","localidade":"São Paulo","uf":"SP"}`)
+	defer openCEP.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI, config.CEPProviderOpenCEP},
+		viaCEP.URL, brasilAPI.URL, openCEP.URL, "",
+	)
+	service := mustNewCEPService(t, cfg)
+
+	location, err := service.GetLocation(context.Background(), "01310100")
+	assert.NoError(t, err)
+	assert.Equal(t, "São Paulo", location.Localidade)
+	assert.Equal(t, config.CEPProviderOpenCEP, location.Provider)
+}
+
+func TestCEPService_GetLocation_NotFound(t *testing.T) {
+	viaCEP := newJSONServer(http.StatusOK, `{"erro": true}`)
+	defer viaCEP.Close()
+	brasilAPI := newJSONServer(http.StatusNotFound, `{"message":"CEP não encontrado"}`)
+	defer brasilAPI.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI},
+		viaCEP.URL, brasilAPI.URL, "", "",
+	)
+	service := mustNewCEPService(t, cfg)
+
+	_, err := service.GetLocation(context.Background(), "99999999")
+	assert.ErrorIs(t, err, ErrCEPNotFound)
+}
+
+func TestCEPService_GetLocation_InvalidCEP(t *testing.T) {
+	service := mustNewCEPService(t, newProvidersConfig([]string{config.CEPProviderViaCEP}, "", "", "", ""))
+
+	_, err := service.GetLocation(context.Background(), "123")
+	assert.ErrorIs(t, err, ErrInvalidCEP)
+}
+
+func TestCEPService_GetLocation_BadPayload(t *testing.T) {
+	viaCEP := newJSONServer(http.StatusOK, `<html>maintenance</html>`)
+	defer viaCEP.Close()
+
+	service := mustNewCEPService(t, newProvidersConfig([]string{config.CEPProviderViaCEP}, viaCEP.URL, "", "", ""))
+
+	_, err := service.GetLocation(context.Background(), "01310100")
+	assert.ErrorIs(t, err, ErrBadUpstreamPayload)
+}
+
+func TestCEPService_GetLocation_UpstreamFailure(t *testing.T) {
+	viaCEP := newJSONServer(http.StatusOK, `{"erro": true}`)
+	defer viaCEP.Close()
+	brasilAPI := newJSONServer(http.StatusBadGateway, `bad gateway`)
+	defer brasilAPI.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI},
+		viaCEP.URL, brasilAPI.URL, "", "",
+	)
+	service := mustNewCEPService(t, cfg)
+
+	_, err := service.GetLocation(context.Background(), "01310100")
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	assert.Contains(t, err.Error(), "status 502")
+}
+
+func TestCEPService_GetLocation_ContextCancelled(t *testing.T) {
+	var calls atomic.Int32
+	viaCEP := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer viaCEP.Close()
+
+	cfg := newProvidersConfig(
+		[]string{config.CEPProviderViaCEP, config.CEPProviderBrasilAPI},
+		viaCEP.URL, viaCEP.URL, "", "",
+	)
+	service := mustNewCEPService(t, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := service.GetLocation(ctx, "01310100")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(0), calls.Load())
+}
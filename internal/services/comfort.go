@@ -0,0 +1,218 @@
+package services
+
+import (
+	"math"
+
+	"cep-temperatura/internal/models"
+)
+
+// Motivos de um índice não calculado
+const (
+	reasonMissingHumidity = "humidity not reported by weather provider"
+	reasonMissingWind     = "wind speed not reported by weather provider"
+)
+
+// comfortIndices calcula os índices de conforto térmico a partir da temperatura em Celsius,
+// da umidade relativa (%) e da velocidade do vento (km/h) informadas pelo provedor
+func comfortIndices(celsius float64, humidity, windKph *float64) models.ComfortIndices {
+	indices := models.ComfortIndices{
+		HeatIndex: invalidIndex(reasonMissingHumidity),
+		DewPoint:  invalidIndex(reasonMissingHumidity),
+		Humidex:   invalidIndex(reasonMissingHumidity),
+		WetBulb:   invalidIndex(reasonMissingHumidity),
+		WindChill: invalidIndex(reasonMissingWind),
+	}
+
+	if humidity != nil {
+		indices.HeatIndex = heatIndex(celsius, *humidity)
+		indices.DewPoint = dewPoint(celsius, *humidity)
+		indices.Humidex = humidex(celsius, *humidity)
+		indices.WetBulb = wetBulb(celsius, *humidity)
+	}
+	if windKph != nil {
+		indices.WindChill = windChill(celsius, *windKph)
+	}
+
+	return indices
+}
+
+func invalidIndex(reason string) models.ComfortIndex {
+	return models.ComfortIndex{Reason: reason}
+}
+
+// heatIndex calcula o índice de calor do NWS: a fórmula simplificada de Steadman e, quando
+// ela passa de 80 °F, a regressão de Rothfusz com os ajustes para umidade baixa e alta.
+// Válido a partir de 80 °F (26,7 °C), com umidade entre 0 e 100%.
+func heatIndex(celsius, humidity float64) models.ComfortIndex {
+	fahrenheit := ConvertTemperature(celsius, UnitCelsius, UnitFahrenheit)
+	if fahrenheit < 80 {
+		return invalidIndex("heat index applies from 26.7 °C (80 °F)")
+	}
+	if humidity < 0 || humidity > 100 {
+		return invalidIndex("relative humidity out of range")
+	}
+
+	t, rh := fahrenheit, humidity
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh -
+			0.00683783*t*t - 0.05481717*rh*rh + 0.00122874*t*t*rh +
+			0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+		switch {
+		case rh < 13 && t >= 80 && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case rh > 85 && t >= 80 && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+
+	// Faixas de risco do NWS, em Fahrenheit
+	var risk string
+	switch {
+	case hi >= 125:
+		risk = models.RiskExtremeDanger
+	case hi >= 103:
+		risk = models.RiskDanger
+	case hi >= 90:
+		risk = models.RiskExtremeCaution
+	case hi >= 80:
+		risk = models.RiskCaution
+	default:
+		risk = models.RiskNone
+	}
+
+	return models.ComfortIndex{Value: ConvertTemperature(hi, UnitFahrenheit, UnitCelsius), Valid: true, Risk: risk}
+}
+
+// windChill calcula a sensação térmica pelo vento na fórmula conjunta do NWS e do serviço
+// meteorológico canadense. Válido com temperatura até 10 °C e vento a partir de 4,8 km/h.
+func windChill(celsius, windKph float64) models.ComfortIndex {
+	if celsius > 10 {
+		return invalidIndex("wind chill applies up to 10 °C")
+	}
+	if windKph < 4.8 {
+		return invalidIndex("wind chill requires wind of at least 4.8 km/h")
+	}
+
+	v := math.Pow(windKph, 0.16)
+	wc := 13.12 + 0.6215*celsius - 11.37*v + 0.3965*celsius*v
+
+	// Faixas de risco de congelamento da pele do serviço meteorológico canadense
+	var risk string
+	switch {
+	case wc <= -48:
+		risk = models.RiskExtremeDanger
+	case wc <= -40:
+		risk = models.RiskDanger
+	case wc <= -28:
+		risk = models.RiskExtremeCaution
+	case wc <= -10:
+		risk = models.RiskCaution
+	default:
+		risk = models.RiskNone
+	}
+
+	return models.ComfortIndex{Value: wc, Valid: true, Risk: risk}
+}
+
+// Coeficientes de Magnus (Sonntag, 1990), válidos de -45 °C a 60 °C sobre água
+const (
+	magnusA = 17.62
+	magnusB = 243.12
+)
+
+// dewPoint calcula o ponto de orvalho pela fórmula de Magnus. Válido de -45 °C a 60 °C,
+// com umidade acima de 0% e até 100%.
+func dewPoint(celsius, humidity float64) models.ComfortIndex {
+	value, ok := magnusDewPoint(celsius, humidity)
+	if !ok {
+		return invalidIndex("dew point applies from -45 °C to 60 °C with humidity above 0%")
+	}
+
+	// Quanto mais alto o ponto de orvalho, menos o suor evapora
+	var risk string
+	switch {
+	case value >= 26:
+		risk = models.RiskExtremeDanger
+	case value >= 24:
+		risk = models.RiskDanger
+	case value >= 21:
+		risk = models.RiskExtremeCaution
+	case value >= 18:
+		risk = models.RiskCaution
+	default:
+		risk = models.RiskNone
+	}
+
+	return models.ComfortIndex{Value: value, Valid: true, Risk: risk}
+}
+
+func magnusDewPoint(celsius, humidity float64) (float64, bool) {
+	if celsius < -45 || celsius > 60 || humidity <= 0 || humidity > 100 {
+		return 0, false
+	}
+	gamma := math.Log(humidity/100) + magnusA*celsius/(magnusB+celsius)
+	return magnusB * gamma / (magnusA - gamma), true
+}
+
+// humidex calcula o índice canadense de calor úmido a partir do ponto de orvalho.
+// Válido a partir de 20 °C, faixa em que o serviço canadense o divulga.
+func humidex(celsius, humidity float64) models.ComfortIndex {
+	if celsius < 20 {
+		return invalidIndex("humidex applies from 20 °C")
+	}
+	dew, ok := magnusDewPoint(celsius, humidity)
+	if !ok {
+		return invalidIndex("humidex requires humidity above 0% and temperature up to 60 °C")
+	}
+
+	vaporPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dew)))
+	value := celsius + 0.5555*(vaporPressure-10)
+
+	// Faixas de desconforto do serviço meteorológico canadense
+	var risk string
+	switch {
+	case value >= 54:
+		risk = models.RiskExtremeDanger
+	case value >= 46:
+		risk = models.RiskDanger
+	case value >= 40:
+		risk = models.RiskExtremeCaution
+	case value >= 30:
+		risk = models.RiskCaution
+	default:
+		risk = models.RiskNone
+	}
+
+	return models.ComfortIndex{Value: value, Valid: true, Risk: risk}
+}
+
+// wetBulb estima a temperatura de bulbo úmido pela fórmula empírica de Stull (2011), com erro
+// de até 1 °C. Válido de -20 °C a 50 °C, com umidade entre 5% e 99%, ao nível do mar.
+func wetBulb(celsius, humidity float64) models.ComfortIndex {
+	if celsius < -20 || celsius > 50 || humidity < 5 || humidity > 99 {
+		return invalidIndex("wet bulb estimate applies from -20 °C to 50 °C with humidity between 5% and 99%")
+	}
+
+	t, rh := celsius, humidity
+	value := t*math.Atan(0.151977*math.Sqrt(rh+8.313659)) + math.Atan(t+rh) - math.Atan(rh-1.676331) +
+		0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) - 4.686035
+
+	// Acima de 35 °C de bulbo úmido o corpo não consegue perder calor; os limites de trabalho
+	// pesado ficam bem abaixo disso
+	var risk string
+	switch {
+	case value >= 32:
+		risk = models.RiskExtremeDanger
+	case value >= 30:
+		risk = models.RiskDanger
+	case value >= 28:
+		risk = models.RiskExtremeCaution
+	case value >= 26:
+		risk = models.RiskCaution
+	default:
+		risk = models.RiskNone
+	}
+
+	return models.ComfortIndex{Value: value, Valid: true, Risk: risk}
+}
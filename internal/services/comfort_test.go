@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComfortIndices_ReferenceValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    models.ComfortIndex
+		expected float64
+		risk     string
+	}{
+		// Tabela do NWS: 90 °F e 70% resultam em 106 °F; 89,6 °F resulta em cerca de 104,7 °F
+		{"índice de calor", heatIndex(32, 70), 40.41, models.RiskDanger},
+		{"índice de calor com umidade baixa", heatIndex(40, 10), 36.71, models.RiskExtremeCaution},
+		// Tabela canadense: -10 °C com vento de 30 km/h resulta em -20
+		{"sensação pelo vento", windChill(-10, 30), -19.52, models.RiskCaution},
+		{"sensação pelo vento extrema", windChill(-30, 40), -47.5, models.RiskDanger},
+		{"ponto de orvalho", dewPoint(25, 60), 16.69, models.RiskNone},
+		{"ponto de orvalho abafado", dewPoint(30, 80), 26.17, models.RiskExtremeDanger},
+		{"humidex", humidex(30, 60), 38.76, models.RiskCaution},
+		{"humidex perigoso", humidex(35, 70), 51.81, models.RiskDanger},
+		// Exemplo de Stull (2011): 20 °C e 50% resultam em 13,7 °C
+		{"bulbo úmido", wetBulb(20, 50), 13.7, models.RiskNone},
+		{"bulbo úmido perigoso", wetBulb(35, 80), 31.93, models.RiskDanger},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.index.Valid)
+			assert.InDelta(t, tt.expected, tt.index.Value, 0.01)
+			assert.Equal(t, tt.risk, tt.index.Risk)
+		})
+	}
+}
+
+func TestComfortIndices_ValidityRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		index models.ComfortIndex
+	}{
+		{"índice de calor abaixo de 26,7 °C", heatIndex(26, 80)},
+		{"sensação pelo vento acima de 10 °C", windChill(12, 30)},
+		{"sensação pelo vento com vento fraco", windChill(0, 3)},
+		{"ponto de orvalho sem umidade", dewPoint(25, 0)},
+		{"humidex abaixo de 20 °C", humidex(15, 80)},
+		{"bulbo úmido com umidade acima de 99%", wetBulb(25, 100)},
+		{"bulbo úmido acima de 50 °C", wetBulb(55, 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, tt.index.Valid)
+			assert.Empty(t, tt.index.Risk)
+			assert.NotEmpty(t, tt.index.Reason)
+		})
+	}
+}
+
+func TestComfortIndices_MissingInputs(t *testing.T) {
+	indices := comfortIndices(30, nil, nil)
+
+	for _, index := range []models.ComfortIndex{indices.HeatIndex, indices.DewPoint, indices.Humidex, indices.WetBulb} {
+		assert.False(t, index.Valid)
+		assert.Equal(t, reasonMissingHumidity, index.Reason)
+	}
+	assert.False(t, indices.WindChill.Valid)
+	assert.Equal(t, reasonMissingWind, indices.WindChill.Reason)
+}
+
+func TestTemperatureService_Comfort(t *testing.T) {
+	service := NewTemperatureService(WithRounding(Rounding{Mode: config.RoundingHalfEven, Decimals: 1}))
+
+	humidity, wind := 60.0, 20.0
+	indices := service.Comfort(25, &humidity, &wind)
+
+	assert.Equal(t, 16.7, indices.DewPoint.Value)
+	assert.Equal(t, 30.1, indices.Humidex.Value)
+	assert.False(t, indices.HeatIndex.Valid)
+	assert.False(t, indices.WindChill.Valid)
+	assert.True(t, indices.WetBulb.Valid)
+}
@@ -0,0 +1,63 @@
+package services
+
+import "math"
+
+// compassPoints são os 16 pontos da rosa dos ventos, a partir do norte no sentido horário
+var compassPoints = [...]string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// compassDirection converte a direção do vento em graus no ponto da rosa dos ventos,
+// no mesmo formato de wind_dir da WeatherAPI
+func compassDirection(degree *float64) string {
+	if degree == nil {
+		return ""
+	}
+	index := int(math.Round(math.Mod(*degree, 360)/22.5)) % len(compassPoints)
+	if index < 0 {
+		index += len(compassPoints)
+	}
+	return compassPoints[index]
+}
+
+// metersPerSecondToKph converte a velocidade do vento de m/s para km/h
+func metersPerSecondToKph(speed *float64) *float64 {
+	if speed == nil {
+		return nil
+	}
+	kph := *speed * 3.6
+	return &kph
+}
+
+// wmoDescriptions descreve os códigos de tempo da OMM (WMO 4677) usados pela Open-Meteo
+var wmoDescriptions = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	56: "Light freezing drizzle",
+	57: "Dense freezing drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Heavy freezing rain",
+	71: "Slight snow fall",
+	73: "Moderate snow fall",
+	75: "Heavy snow fall",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
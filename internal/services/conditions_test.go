@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompassDirection(t *testing.T) {
+	tests := map[float64]string{
+		0:     "N",
+		11:    "N",
+		12:    "NNE",
+		90:    "E",
+		140:   "SE",
+		225:   "SW",
+		349:   "N",
+		360:   "N",
+		-22.5: "NNW",
+	}
+
+	for degree, expected := range tests {
+		assert.Equal(t, expected, compassDirection(&degree), "graus: %v", degree)
+	}
+	assert.Empty(t, compassDirection(nil))
+}
+
+func TestWeatherAPIProvider_Current_Conditions(t *testing.T) {
+	server := newJSONServer(http.StatusOK, `{
+		"location": {"name": "São Paulo", "region": "Sao Paulo", "country": "Brazil"},
+		"current": {"temp_c": 28.5, "last_updated": "2025-01-10 15:00", "last_updated_epoch": 1736532000,
+			"condition": {"text": "Partly cloudy", "code": 1003},
+			"wind_kph": 11.2, "wind_degree": 140, "wind_dir": "SE",
+			"pressure_mb": 1015.0, "humidity": 65, "feelslike_c": 30.1, "uv": 7.0}
+	}`)
+	defer server.Close()
+
+	result, err := newTestWeatherAPIProvider(server.URL).Current(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"})
+	assert.NoError(t, err)
+
+	code := 1003
+	assert.Equal(t, models.Conditions{
+		Text:        "Partly cloudy",
+		Code:        &code,
+		Humidity:    floatPtr(65),
+		WindKph:     floatPtr(11.2),
+		WindDegree:  floatPtr(140),
+		WindDir:     "SE",
+		PressureMb:  floatPtr(1015),
+		FeelsLikeC:  floatPtr(30.1),
+		UV:          floatPtr(7),
+		LastUpdated: "2025-01-10 15:00",
+		ObservedAt:  time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC),
+	}, result.Conditions)
+}
+
+func TestOpenMeteoProvider_Current_Conditions(t *testing.T) {
+	server := newJSONServer(http.StatusOK, `{"utc_offset_seconds": -10800, "current": {"time": "2025-01-10T15:00", "temperature_2m": 28.5,
+		"relative_humidity_2m": 65, "apparent_temperature": 30.1, "weather_code": 2,
+		"wind_speed_10m": 11.2, "wind_direction_10m": 140, "pressure_msl": 1015.0, "uv_index": 7.0}}`)
+	defer server.Close()
+
+	provider := &openMeteoProvider{baseURL: server.URL, geocodingURL: server.URL, client: &http.Client{}}
+
+	result, err := provider.Current(context.Background(), LocationQuery{Coordinates: &models.Coordinates{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Partly cloudy", result.Conditions.Text)
+	assert.Equal(t, 2, *result.Conditions.Code)
+	assert.Equal(t, "SE", result.Conditions.WindDir)
+	assert.Equal(t, "2025-01-10 15:00", result.Conditions.LastUpdated)
+	assert.Equal(t, time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC), result.Conditions.ObservedAt)
+}
+
+func TestOpenWeatherMapProvider_Current_Conditions(t *testing.T) {
+	server := newJSONServer(http.StatusOK, `{"name": "São Paulo", "dt": 1736521200, "timezone": -10800,
+		"weather": [{"id": 802, "description": "scattered clouds"}],
+		"main": {"temp": 28.5, "feels_like": 30.1, "pressure": 1015, "humidity": 65},
+		"wind": {"speed": 5, "deg": 140},
+		"sys": {"country": "BR"}}`)
+	defer server.Close()
+
+	provider := &openWeatherMapProvider{baseURL: server.URL, apiKey: "test", client: &http.Client{}}
+
+	result, err := provider.Current(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"})
+	assert.NoError(t, err)
+	assert.Equal(t, "scattered clouds", result.Conditions.Text)
+	assert.Equal(t, 802, *result.Conditions.Code)
+	assert.Equal(t, 18.0, *result.Conditions.WindKph)
+	assert.Equal(t, "SE", result.Conditions.WindDir)
+	assert.Nil(t, result.Conditions.UV)
+	assert.Equal(t, "2025-01-10 12:00", result.Conditions.LastUpdated)
+	assert.Equal(t, time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC), result.Conditions.ObservedAt)
+}
+
+func floatPtr(value float64) *float64 { return &value }
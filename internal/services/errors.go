@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Erros retornados por CEPService e WeatherService. Use errors.Is para identificá-los,
+// pois normalmente chegam embrulhados com o contexto da falha.
+var (
+	ErrInvalidCEP              = errors.New("invalid zipcode")
+	ErrCEPNotFound             = errors.New("can not find zipcode")
+	ErrUpstreamUnavailable     = errors.New("upstream unavailable")
+	ErrUpstreamTimeout         = errors.New("upstream timeout")
+	ErrBadUpstreamPayload      = errors.New("bad upstream payload")
+	ErrWeatherLocationNotFound = errors.New("weather location not found")
+	ErrWeatherLocationMismatch = errors.New("weather location mismatch")
+	ErrQuotaExceeded           = errors.New("upstream quota exceeded")
+	ErrForecastUnsupported     = errors.New("forecast not supported")
+	ErrHistoryUnsupported      = errors.New("history not supported")
+)
+
+// ErrorInfo descreve um erro dos serviços para os clientes: o código estável de
+// docs/errors.md, o status HTTP, o título dos erros da RFC 7807 e a mensagem
+type ErrorInfo struct {
+	Target  error
+	Status  int
+	Code    string
+	Title   string
+	Message string
+}
+
+// Classes dos erros, com os nomes dos códigos gRPC, para os transportes sem status HTTP
+const (
+	ClassInvalidArgument  = "INVALID_ARGUMENT"
+	ClassNotFound         = "NOT_FOUND"
+	ClassUnimplemented    = "UNIMPLEMENTED"
+	ClassUnavailable      = "UNAVAILABLE"
+	ClassDeadlineExceeded = "DEADLINE_EXCEEDED"
+	ClassInternal         = "INTERNAL"
+)
+
+// ErrorCatalog é a única tabela dos erros dos serviços expostos aos clientes. A API HTTP, o
+// gRPC, o GraphQL e o WebSocket derivam dela o código, a mensagem e o status ou a classe.
+var ErrorCatalog = []ErrorInfo{
+	{ErrInvalidCEP, http.StatusUnprocessableEntity, "invalid_zipcode", "Invalid zipcode", "invalid zipcode"},
+	{ErrUnknownUnit, http.StatusBadRequest, "invalid_unit", "Invalid unit", "invalid unit"},
+	{ErrBelowAbsoluteZero, http.StatusUnprocessableEntity, "below_absolute_zero", "Temperature below absolute zero", "temperature below absolute zero"},
+	{ErrCEPNotFound, http.StatusNotFound, "zipcode_not_found", "Zipcode not found", "can not find zipcode"},
+	{ErrWeatherLocationNotFound, http.StatusBadGateway, "weather_location_not_found", "Weather location not found", "can not resolve weather location"},
+	{ErrWeatherLocationMismatch, http.StatusBadGateway, "weather_location_mismatch", "Weather location mismatch", "weather location mismatch"},
+	{ErrBadUpstreamPayload, http.StatusBadGateway, "bad_upstream_response", "Invalid upstream response", "invalid upstream response"},
+	{ErrForecastUnsupported, http.StatusNotImplemented, "forecast_unsupported", "Forecast not supported", "forecast not supported by weather provider"},
+	{ErrHistoryUnsupported, http.StatusNotImplemented, "history_unsupported", "History not supported", "history not supported by weather provider"},
+	{ErrQuotaExceeded, http.StatusServiceUnavailable, "upstream_quota_exceeded", "Upstream quota exceeded", "upstream quota exceeded"},
+	{ErrUpstreamTimeout, http.StatusGatewayTimeout, "upstream_timeout", "Upstream timeout", "upstream timeout"},
+	{ErrUpstreamUnavailable, http.StatusServiceUnavailable, "upstream_unavailable", "Upstream unavailable", "upstream unavailable"},
+}
+
+// InternalError descreve os erros fora do catálogo
+var InternalError = ErrorInfo{
+	Status:  http.StatusInternalServerError,
+	Code:    "internal_error",
+	Title:   "Internal server error",
+	Message: "internal server error",
+}
+
+// DescribeError encontra a descrição do erro em ErrorCatalog. O prazo esgotado do contexto
+// conta como timeout do serviço externo; os demais erros fora do catálogo são InternalError.
+func DescribeError(err error) ErrorInfo {
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = ErrUpstreamTimeout
+	}
+	for _, info := range ErrorCatalog {
+		if errors.Is(err, info.Target) {
+			return info
+		}
+	}
+	return InternalError
+}
+
+// Class deriva do status HTTP a classe do erro, para que todos os transportes classifiquem
+// cada erro da mesma forma
+func (i ErrorInfo) Class() string {
+	switch {
+	case i.Status == http.StatusBadRequest || i.Status == http.StatusUnprocessableEntity:
+		return ClassInvalidArgument
+	case i.Status == http.StatusNotFound:
+		return ClassNotFound
+	case i.Status == http.StatusNotImplemented:
+		return ClassUnimplemented
+	case i.Status == http.StatusGatewayTimeout:
+		return ClassDeadlineExceeded
+	case i.Status == http.StatusBadGateway || i.Status == http.StatusServiceUnavailable:
+		return ClassUnavailable
+	default:
+		return ClassInternal
+	}
+}
+
+// classifyTransportError identifica se uma falha de rede foi timeout ou indisponibilidade
+func classifyTransportError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrUpstreamTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrUpstreamTimeout
+	}
+
+	return ErrUpstreamUnavailable
+}
+
+// contextError embrulha o erro de um contexto encerrado mantendo a causa original,
+// para que o chamador distinga prazo esgotado de cliente desconectado
+func contextError(err error) error {
+	return fmt.Errorf("requisição interrompida: %w: %w", classifyTransportError(err), err)
+}
+
+// classifyStatus converte um status HTTP inesperado de um serviço externo em erro tipado
+func classifyStatus(status int) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case status == http.StatusGatewayTimeout || status == http.StatusRequestTimeout:
+		return ErrUpstreamTimeout
+	default:
+		return ErrUpstreamUnavailable
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// fetch executa um GET no serviço externo e retorna o status e o corpo da resposta.
+// Falhas de rede já chegam classificadas como timeout ou indisponibilidade.
+func fetch(ctx context.Context, client *http.Client, url, service string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("erro ao consultar %s: %w: %w", service, classifyTransportError(err), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("erro ao ler resposta: %w: %w", classifyTransportError(err), err)
+	}
+
+	return resp.StatusCode, body, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"cep-temperatura/internal/models"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stateNames mapeia a sigla da UF para o nome do estado, como retornado pelas APIs de clima
+var stateNames = map[string]string{
+	"AC": "Acre",
+	"AL": "Alagoas",
+	"AP": "Amapá",
+	"AM": "Amazonas",
+	"BA": "Bahia",
+	"CE": "Ceará",
+	"DF": "Distrito Federal",
+	"ES": "Espírito Santo",
+	"GO": "Goiás",
+	"MA": "Maranhão",
+	"MT": "Mato Grosso",
+	"MS": "Mato Grosso do Sul",
+	"MG": "Minas Gerais",
+	"PA": "Pará",
+	"PB": "Paraíba",
+	"PR": "Paraná",
+	"PE": "Pernambuco",
+	"PI": "Piauí",
+	"RJ": "Rio de Janeiro",
+	"RN": "Rio Grande do Norte",
+	"RS": "Rio Grande do Sul",
+	"RO": "Rondônia",
+	"RR": "Roraima",
+	"SC": "Santa Catarina",
+	"SP": "São Paulo",
+	"SE": "Sergipe",
+	"TO": "Tocantins",
+}
+
+// stateName retorna o nome do estado para a UF, ou a própria UF se ela for desconhecida
+func stateName(uf string) string {
+	if name, ok := stateNames[strings.ToUpper(uf)]; ok {
+		return name
+	}
+	return uf
+}
+
+// normalizeName remove acentos, pontuação redundante e diferenças de caixa para comparação
+func normalizeName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, name)
+	if err != nil {
+		result = name
+	}
+	result = strings.ReplaceAll(result, "-", " ")
+	result = strings.ReplaceAll(result, "'", "")
+	return strings.Join(strings.Fields(strings.ToLower(result)), " ")
+}
+
+// sameState compara a região retornada pela API de clima com a UF do CEP,
+// aceitando tanto a sigla quanto o nome do estado
+func sameState(region, uf string) bool {
+	normalized := normalizeName(region)
+	return normalized == normalizeName(uf) || normalized == normalizeName(stateName(uf))
+}
+
+// stateUF retorna a UF correspondente a uma sigla ou nome de estado
+func stateUF(region string) (string, bool) {
+	if _, ok := stateNames[strings.ToUpper(region)]; ok {
+		return strings.ToUpper(region), true
+	}
+	normalized := normalizeName(region)
+	for uf, name := range stateNames {
+		if normalizeName(name) == normalized {
+			return uf, true
+		}
+	}
+	return "", false
+}
+
+// matchLocation verifica se o local resolvido pela API de clima corresponde à cidade e UF do CEP.
+// Provedores que não informam o estado (ex.: OpenWeatherMap) são comparados apenas pela cidade.
+func matchLocation(query models.WeatherQuery, location models.WeatherLocation) bool {
+	switch normalizeName(location.Country) {
+	case "", "brazil", "brasil", "br":
+	default:
+		return false
+	}
+	if location.Region != "" && !sameState(location.Region, query.State) {
+		return false
+	}
+	return normalizeName(location.Name) == normalizeName(query.City)
+}
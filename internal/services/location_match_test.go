@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "acentos", input: "São Paulo", expected: "sao paulo"},
+		{name: "cedilha e caixa", input: "FOZ DO IGUAÇU", expected: "foz do iguacu"},
+		{name: "hífen e espaços", input: "  Embu-Guaçu ", expected: "embu guacu"},
+		{name: "apóstrofo", input: "Santa Bárbara d'Oeste", expected: "santa barbara doeste"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeName(tt.input))
+		})
+	}
+}
+
+func TestMatchLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    models.WeatherQuery
+		location models.WeatherLocation
+		expected bool
+	}{
+		{
+			name:     "mesmo local sem acentos e com nome do estado",
+			query:    models.WeatherQuery{City: "São Paulo", State: "SP"},
+			location: models.WeatherLocation{Name: "Sao Paulo", Region: "Sao Paulo", Country: "Brazil"},
+			expected: true,
+		},
+		{
+			name:     "região informada pela sigla",
+			query:    models.WeatherQuery{City: "Brasília", State: "DF"},
+			location: models.WeatherLocation{Name: "Brasilia", Region: "DF", Country: "Brazil"},
+			expected: true,
+		},
+		{
+			name:     "município homônimo em outro estado",
+			query:    models.WeatherQuery{City: "Bom Jesus", State: "PI"},
+			location: models.WeatherLocation{Name: "Bom Jesus", Region: "Rio Grande do Sul", Country: "Brazil"},
+			expected: false,
+		},
+		{
+			name:     "cidade diferente",
+			query:    models.WeatherQuery{City: "Campinas", State: "SP"},
+			location: models.WeatherLocation{Name: "Valinhos", Region: "Sao Paulo", Country: "Brazil"},
+			expected: false,
+		},
+		{
+			name:     "provedor sem estado compara apenas a cidade",
+			query:    models.WeatherQuery{City: "São Paulo", State: "SP"},
+			location: models.WeatherLocation{Name: "São Paulo", Country: "BR"},
+			expected: true,
+		},
+		{
+			name:     "outro país",
+			query:    models.WeatherQuery{City: "Santa Rosa", State: "RS"},
+			location: models.WeatherLocation{Name: "Santa Rosa", Region: "Rio Grande do Sul", Country: "Argentina"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchLocation(tt.query, tt.location))
+		})
+	}
+}
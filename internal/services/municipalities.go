@@ -0,0 +1,106 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"cep-temperatura/internal/models"
+)
+
+// embeddedCentroids é a tabela de centroides embutida: as capitais, Campinas e pares de
+// municípios homônimos em estados diferentes (Santa Maria, Itabaiana, Santa Luzia, Triunfo,
+// Viçosa, Valença e Palmas). Os demais municípios são consultados pelo nome, a menos que
+// WEATHER_CENTROIDS_PATH aponte para a tabela completa do IBGE.
+//
+//go:embed data/municipios.csv
+var embeddedCentroids string
+
+// MunicipalityCentroids mapeia o código IBGE do município para as coordenadas do seu centroide
+type MunicipalityCentroids map[string]models.Coordinates
+
+// Lookup retorna as coordenadas do município, se ele estiver na tabela
+func (m MunicipalityCentroids) Lookup(ibge string) (models.Coordinates, bool) {
+	if ibge == "" {
+		return models.Coordinates{}, false
+	}
+	coordinates, ok := m[ibge]
+	return coordinates, ok
+}
+
+// loadCentroids carrega a tabela do arquivo informado ou, sem caminho, a tabela embutida
+func loadCentroids(path string) (MunicipalityCentroids, error) {
+	if path == "" {
+		centroids, err := LoadMunicipalityCentroids(strings.NewReader(embeddedCentroids))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar tabela de municípios embutida: %w", err)
+		}
+		return centroids, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir tabela de municípios: %w", err)
+	}
+	defer file.Close()
+
+	centroids, err := LoadMunicipalityCentroids(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar tabela de municípios %s: %w", path, err)
+	}
+	return centroids, nil
+}
+
+// LoadMunicipalityCentroids lê um CSV com cabeçalho contendo as colunas ibge, latitude e longitude
+func LoadMunicipalityCentroids(r io.Reader) (MunicipalityCentroids, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"ibge", "latitude", "longitude"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.New("coluna " + name + " ausente no cabeçalho")
+		}
+	}
+
+	centroids := make(MunicipalityCentroids)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+		if len(record) < len(header) {
+			return nil, fmt.Errorf("linha %d: colunas insuficientes", line)
+		}
+
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(record[columns["latitude"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: latitude inválida: %w", line, err)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(record[columns["longitude"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: longitude inválida: %w", line, err)
+		}
+
+		ibge := strings.TrimSpace(record[columns["ibge"]])
+		centroids[ibge] = models.Coordinates{Latitude: latitude, Longitude: longitude}
+	}
+
+	return centroids, nil
+}
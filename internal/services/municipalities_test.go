@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMunicipalityCentroids(t *testing.T) {
+	data := "nome,uf,ibge,longitude,latitude\n" +
+		"São Paulo,SP,3550308,-46.6333,-23.5505\n"
+
+	centroids, err := LoadMunicipalityCentroids(strings.NewReader(data))
+	assert.NoError(t, err)
+
+	coordinates, ok := centroids.Lookup("3550308")
+	assert.True(t, ok)
+	assert.Equal(t, -23.5505, coordinates.Latitude)
+	assert.Equal(t, -46.6333, coordinates.Longitude)
+
+	_, ok = centroids.Lookup("")
+	assert.False(t, ok)
+}
+
+func TestLoadMunicipalityCentroids_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "sem coluna de latitude",
+			data:     "ibge,longitude\n3550308,-46.6\n",
+			expected: "coluna latitude ausente",
+		},
+		{
+			name:     "latitude inválida",
+			data:     "ibge,latitude,longitude\n3550308,abc,-46.6\n",
+			expected: "linha 2: latitude inválida",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMunicipalityCentroids(strings.NewReader(tt.data))
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestEmbeddedCentroids(t *testing.T) {
+	centroids, err := loadCentroids("")
+	assert.NoError(t, err)
+
+	// Todas as capitais estão na tabela embutida
+	assert.GreaterOrEqual(t, len(centroids), 27)
+	_, ok := centroids.Lookup("5300108")
+	assert.True(t, ok)
+}
+
+func TestEmbeddedCentroids_Homonyms(t *testing.T) {
+	centroids, err := loadCentroids("")
+	require.NoError(t, err)
+
+	// Valença (BA) e Valença (RJ) têm o mesmo nome, mas códigos IBGE e coordenadas distintos
+	bahia, ok := centroids.Lookup("2932903")
+	require.True(t, ok)
+	rio, ok := centroids.Lookup("3306107")
+	require.True(t, ok)
+	assert.InDelta(t, -13.37, bahia.Latitude, 0.1)
+	assert.InDelta(t, -22.25, rio.Latitude, 0.1)
+}
@@ -1,37 +1,95 @@
 package services
 
+import (
+	"fmt"
+
+	"cep-temperatura/internal/models"
+)
+
 // TemperatureService interface para operações de temperatura
 type TemperatureService interface {
 	ConvertTemperatures(celsius float64) (fahrenheit, kelvin float64)
+	Convert(value float64, from, to Unit) float64
+	ConvertDelta(delta float64, from, to Unit) float64
+	Validate(value float64, unit Unit) error
+	Comfort(celsius float64, humidity, windKph *float64) models.ComfortIndices
+}
+
+type temperatureService struct {
+	rounding     Rounding
+	legacyKelvin bool
+}
+
+// TemperatureOption personaliza o serviço de temperatura
+type TemperatureOption func(*temperatureService)
+
+// WithRounding define a política de arredondamento aplicada às temperaturas convertidas
+func WithRounding(rounding Rounding) TemperatureOption {
+	return func(s *temperatureService) {
+		s.rounding = rounding
+	}
 }
 
-type temperatureService struct{}
+// WithLegacyKelvin reproduz o Kelvin das versões anteriores da API (C + 273), para clientes
+// que dependem do valor antigo
+func WithLegacyKelvin(enabled bool) TemperatureOption {
+	return func(s *temperatureService) {
+		s.legacyKelvin = enabled
+	}
+}
 
-// NewTemperatureService cria uma nova instância do serviço de temperatura
-func NewTemperatureService() TemperatureService {
-	return &temperatureService{}
+// NewTemperatureService cria uma nova instância do serviço de temperatura. Sem opções, os
+// valores convertidos não são arredondados e o Kelvin usa o zero absoluto exato.
+func NewTemperatureService(opts ...TemperatureOption) TemperatureService {
+	s := &temperatureService{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ConvertTemperatures converte temperatura de Celsius para Fahrenheit e Kelvin
 func (s *temperatureService) ConvertTemperatures(celsius float64) (fahrenheit, kelvin float64) {
-	return convertTemperatures(celsius)
+	return s.Convert(celsius, UnitCelsius, UnitFahrenheit), s.Convert(celsius, UnitCelsius, UnitKelvin)
+}
+
+// Convert converte uma temperatura absoluta entre duas escalas, aplicando o arredondamento configurado
+func (s *temperatureService) Convert(value float64, from, to Unit) float64 {
+	return s.rounding.Apply(ConvertTemperature(value, s.unit(from), s.unit(to)))
+}
+
+// ConvertDelta converte uma diferença de temperatura entre duas escalas, aplicando o arredondamento configurado
+func (s *temperatureService) ConvertDelta(delta float64, from, to Unit) float64 {
+	return s.rounding.Apply(ConvertDelta(delta, from, to))
 }
 
-// convertTemperatures converte temperatura de Celsius para Fahrenheit e Kelvin
-func convertTemperatures(celsius float64) (fahrenheit, kelvin float64) {
-	fahrenheit = convertCelsiusToFahrenheit(celsius)
-	kelvin = convertCelsiusToKelvin(celsius)
-	return
+// Validate rejeita temperaturas mais frias que o zero absoluto da escala. Na Delisle, cujos
+// valores crescem no sentido do frio, o zero absoluto é o maior valor possível.
+func (s *temperatureService) Validate(value float64, unit Unit) error {
+	scale := s.unit(unit)
+	zero := scale.AbsoluteZero()
+	if (scale.perDegree > 0 && value < zero) || (scale.perDegree < 0 && value > zero) {
+		return fmt.Errorf("%w: %g %s is colder than %g %s", ErrBelowAbsoluteZero, value, unit.Symbol, zero, unit.Symbol)
+	}
+	return nil
 }
 
-// convertCelsiusToFahrenheit converte Celsius para Fahrenheit
-// Fórmula: F = C * 1.8 + 32
-func convertCelsiusToFahrenheit(celsius float64) float64 {
-	return celsius*1.8 + 32
+// Comfort calcula os índices de conforto térmico a partir da temperatura em Celsius, da umidade
+// relativa (%) e do vento (km/h), com os valores em Celsius arredondados como as conversões
+func (s *temperatureService) Comfort(celsius float64, humidity, windKph *float64) models.ComfortIndices {
+	indices := comfortIndices(celsius, humidity, windKph)
+	for _, index := range []*models.ComfortIndex{
+		&indices.HeatIndex, &indices.WindChill, &indices.DewPoint, &indices.Humidex, &indices.WetBulb,
+	} {
+		index.Value = s.rounding.Apply(index.Value)
+	}
+	return indices
 }
 
-// convertCelsiusToKelvin converte Celsius para Kelvin
-// Fórmula: K = C + 273
-func convertCelsiusToKelvin(celsius float64) float64 {
-	return celsius + 273
+// unit troca o Kelvin pelo Kelvin legado quando a compatibilidade está ativa
+func (s *temperatureService) unit(unit Unit) Unit {
+	if s.legacyKelvin && unit == UnitKelvin {
+		return UnitKelvinLegacy
+	}
+	return unit
 }
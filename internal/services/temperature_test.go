@@ -36,7 +36,7 @@ func TestConvertCelsiusToFahrenheit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := convertCelsiusToFahrenheit(tt.celsius)
+			result := ConvertTemperature(tt.celsius, UnitCelsius, UnitFahrenheit)
 			assert.InDelta(t, tt.expected, result, 0.1)
 		})
 	}
@@ -51,28 +51,28 @@ func TestConvertCelsiusToKelvin(t *testing.T) {
 		{
 			name:     "0°C para Kelvin",
 			celsius:  0,
-			expected: 273,
+			expected: 273.15,
 		},
 		{
 			name:     "28.5°C para Kelvin",
 			celsius:  28.5,
-			expected: 301.5,
+			expected: 301.65,
 		},
 		{
 			name:     "100°C para Kelvin",
 			celsius:  100,
-			expected: 373,
+			expected: 373.15,
 		},
 		{
-			name:     "-273°C para Kelvin",
-			celsius:  -273,
+			name:     "-273.15°C para Kelvin",
+			celsius:  -273.15,
 			expected: 0,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := convertCelsiusToKelvin(tt.celsius)
+			result := ConvertTemperature(tt.celsius, UnitCelsius, UnitKelvin)
 			assert.InDelta(t, tt.expected, result, 0.1)
 		})
 	}
@@ -95,16 +95,51 @@ func TestConvertTemperatures(t *testing.T) {
 				kelvin     float64
 			}{
 				fahrenheit: 83.3,
-				kelvin:     301.5,
+				kelvin:     301.65,
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			fahrenheit, kelvin := convertTemperatures(tt.celsius)
+			fahrenheit := ConvertTemperature(tt.celsius, UnitCelsius, UnitFahrenheit)
+			kelvin := ConvertTemperature(tt.celsius, UnitCelsius, UnitKelvin)
 			assert.InDelta(t, tt.expected.fahrenheit, fahrenheit, 0.1)
 			assert.InDelta(t, tt.expected.kelvin, kelvin, 0.1)
 		})
 	}
 }
+
+func TestTemperatureService_Validate(t *testing.T) {
+	service := NewTemperatureService()
+
+	tests := []struct {
+		name  string
+		value float64
+		unit  Unit
+		valid bool
+	}{
+		{"zero absoluto em Kelvin", 0, UnitKelvin, true},
+		{"abaixo de zero Kelvin", -0.01, UnitKelvin, false},
+		{"zero absoluto em Celsius", -273.15, UnitCelsius, true},
+		{"abaixo do zero absoluto em Celsius", -273.16, UnitCelsius, false},
+		{"abaixo do zero absoluto em Fahrenheit", -460, UnitFahrenheit, false},
+		{"temperatura comum em Fahrenheit", -40, UnitFahrenheit, true},
+		// Na Delisle, os valores crescem no sentido do frio: 150 De é 0 °C e o zero absoluto, 559,725 De
+		{"temperatura comum em Delisle", 150, UnitDelisle, true},
+		{"perto do zero absoluto em Delisle", 559.7, UnitDelisle, true},
+		{"abaixo do zero absoluto em Delisle", 600, UnitDelisle, false},
+		{"temperatura alta em Delisle", -100, UnitDelisle, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.Validate(tt.value, tt.unit)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrBelowAbsoluteZero)
+			}
+		})
+	}
+}
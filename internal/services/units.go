@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"cep-temperatura/internal/config"
+)
+
+// Erros de validação de temperaturas
+var (
+	// ErrUnknownUnit indica uma escala de temperatura não suportada
+	ErrUnknownUnit = errors.New("unknown temperature unit")
+	// ErrBelowAbsoluteZero indica uma temperatura fisicamente impossível
+	ErrBelowAbsoluteZero = errors.New("temperature below absolute zero")
+)
+
+// Unit descreve uma escala de temperatura pela sua relação linear com Celsius:
+// valor = C*perDegree + zero. A diferença de temperatura converte apenas pelo fator perDegree.
+type Unit struct {
+	Symbol    string
+	Name      string
+	perDegree float64
+	zero      float64
+}
+
+// Escalas suportadas. O Kelvin usa o zero absoluto exato (-273,15 °C); UnitKelvinLegacy
+// reproduz o deslocamento de 273 usado pelas versões anteriores da API.
+var (
+	UnitCelsius      = Unit{Symbol: "C", Name: "Celsius", perDegree: 1, zero: 0}
+	UnitFahrenheit   = Unit{Symbol: "F", Name: "Fahrenheit", perDegree: 1.8, zero: 32}
+	UnitKelvin       = Unit{Symbol: "K", Name: "Kelvin", perDegree: 1, zero: 273.15}
+	UnitRankine      = Unit{Symbol: "R", Name: "Rankine", perDegree: 1.8, zero: 491.67}
+	UnitReaumur      = Unit{Symbol: "Re", Name: "Réaumur", perDegree: 0.8, zero: 0}
+	UnitDelisle      = Unit{Symbol: "De", Name: "Delisle", perDegree: -1.5, zero: 150}
+	UnitNewton       = Unit{Symbol: "N", Name: "Newton", perDegree: 0.33, zero: 0}
+	UnitRomer        = Unit{Symbol: "Ro", Name: "Rømer", perDegree: 0.525, zero: 7.5}
+	UnitKelvinLegacy = Unit{Symbol: "K", Name: "Kelvin", perDegree: 1, zero: 273}
+)
+
+// Units lista as escalas suportadas na ordem de apresentação
+var Units = []Unit{
+	UnitCelsius, UnitFahrenheit, UnitKelvin, UnitRankine,
+	UnitReaumur, UnitDelisle, UnitNewton, UnitRomer,
+}
+
+// unitAliases aceita grafias alternativas dos símbolos, sem distinção de maiúsculas
+var unitAliases = map[string]Unit{
+	"rø": UnitRomer,
+	"ré": UnitReaumur,
+}
+
+// ParseUnit identifica uma escala pelo símbolo, sem distinção de maiúsculas (C, F, K, R, Re, De, N, Ro)
+func ParseUnit(symbol string) (Unit, error) {
+	symbol = strings.ToLower(strings.TrimSpace(symbol))
+	for _, unit := range Units {
+		if strings.ToLower(unit.Symbol) == symbol {
+			return unit, nil
+		}
+	}
+	if unit, ok := unitAliases[symbol]; ok {
+		return unit, nil
+	}
+	return Unit{}, fmt.Errorf("%w: %q", ErrUnknownUnit, symbol)
+}
+
+// ParseUnits identifica uma lista de escalas separadas por vírgula, ignorando repetições
+func ParseUnits(list string) ([]Unit, error) {
+	var units []Unit
+	seen := map[string]bool{}
+	for _, symbol := range strings.Split(list, ",") {
+		if strings.TrimSpace(symbol) == "" {
+			continue
+		}
+		unit, err := ParseUnit(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[unit.Symbol] {
+			seen[unit.Symbol] = true
+			units = append(units, unit)
+		}
+	}
+	return units, nil
+}
+
+// toCelsius converte uma temperatura absoluta da escala para Celsius
+func (u Unit) toCelsius(value float64) float64 {
+	return (value - u.zero) / u.perDegree
+}
+
+// fromCelsius converte uma temperatura absoluta em Celsius para a escala
+func (u Unit) fromCelsius(celsius float64) float64 {
+	return celsius*u.perDegree + u.zero
+}
+
+// AbsoluteZero retorna o zero absoluto expresso na escala
+func (u Unit) AbsoluteZero() float64 {
+	return u.fromCelsius(UnitKelvin.toCelsius(0))
+}
+
+// ConvertTemperature converte uma temperatura absoluta entre duas escalas
+func ConvertTemperature(value float64, from, to Unit) float64 {
+	if from == to {
+		return value
+	}
+	return to.fromCelsius(from.toCelsius(value))
+}
+
+// ConvertDelta converte uma diferença de temperatura entre duas escalas. Ao contrário da
+// temperatura absoluta, a diferença ignora o deslocamento do zero de cada escala.
+func ConvertDelta(delta float64, from, to Unit) float64 {
+	if from.perDegree == to.perDegree {
+		return delta
+	}
+	return delta / from.perDegree * to.perDegree
+}
+
+// Rounding define a política de arredondamento das temperaturas convertidas
+type Rounding struct {
+	Mode     string
+	Decimals int
+}
+
+// roundingDigits são os dígitos significativos considerados no arredondamento: descartam o
+// ruído binário das conversões (0.29*100 = 28.999999999999996) sem perder a precisão do valor
+const roundingDigits = 15
+
+// Apply arredonda o valor conforme a política. O modo half_even arredonda o empate para o
+// dígito par (arredondamento bancário); truncate descarta as casas excedentes. O
+// arredondamento é feito sobre a representação decimal do valor, e não sobre o valor
+// multiplicado por 10^Decimals, que perderia precisão com muitas casas.
+func (r Rounding) Apply(value float64) float64 {
+	switch r.Mode {
+	case config.RoundingHalfEven, config.RoundingHalfUp, config.RoundingTruncate:
+	default:
+		return value
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+
+	snapped, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', roundingDigits, 64), 64)
+	text := strconv.FormatFloat(math.Abs(snapped), 'f', -1, 64)
+	integer, fraction, _ := strings.Cut(text, ".")
+	if len(fraction) <= r.Decimals {
+		if snapped == 0 {
+			return 0
+		}
+		return snapped
+	}
+
+	digits := []byte(integer + fraction[:r.Decimals])
+	rest := fraction[r.Decimals:]
+	if r.roundsUp(digits[len(digits)-1], rest) {
+		digits = incrementDecimal(digits)
+	}
+
+	cut := len(digits) - r.Decimals
+	rounded, _ := strconv.ParseFloat(string(digits[:cut])+"."+string(digits[cut:]), 64)
+	// Evita o "-0" em JSON ao arredondar valores negativos muito pequenos
+	if rounded == 0 {
+		return 0
+	}
+	if snapped < 0 {
+		return -rounded
+	}
+	return rounded
+}
+
+// roundsUp decide se o módulo do valor sobe para o próximo dígito, dado o último dígito
+// mantido e os dígitos descartados
+func (r Rounding) roundsUp(last byte, rest string) bool {
+	switch r.Mode {
+	case config.RoundingHalfUp:
+		return rest[0] >= '5'
+	case config.RoundingHalfEven:
+		if rest[0] != '5' {
+			return rest[0] > '5'
+		}
+		if strings.TrimRight(rest[1:], "0") != "" {
+			return true
+		}
+		return (last-'0')%2 == 1
+	default:
+		return false
+	}
+}
+
+// incrementDecimal soma um à última casa de um número decimal escrito em dígitos
+func incrementDecimal(digits []byte) []byte {
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '9' {
+			digits[i]++
+			return digits
+		}
+		digits[i] = '0'
+	}
+	return append([]byte{'1'}, digits...)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	"cep-temperatura/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertTemperature_ReferencePoints(t *testing.T) {
+	// Pontos de ebulição e de congelamento da água em cada escala
+	tests := []struct {
+		unit    Unit
+		boiling float64
+		melting float64
+	}{
+		{UnitCelsius, 100, 0},
+		{UnitFahrenheit, 212, 32},
+		{UnitKelvin, 373.15, 273.15},
+		{UnitRankine, 671.67, 491.67},
+		{UnitReaumur, 80, 0},
+		{UnitDelisle, 0, 150},
+		{UnitNewton, 33, 0},
+		{UnitRomer, 60, 7.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.unit.Name, func(t *testing.T) {
+			assert.InDelta(t, tt.boiling, ConvertTemperature(100, UnitCelsius, tt.unit), 1e-9)
+			assert.InDelta(t, tt.melting, ConvertTemperature(0, UnitCelsius, tt.unit), 1e-9)
+			assert.InDelta(t, 100, ConvertTemperature(tt.boiling, tt.unit, UnitCelsius), 1e-9)
+		})
+	}
+}
+
+func TestConvertTemperature_AllPairs(t *testing.T) {
+	for _, from := range Units {
+		for _, to := range Units {
+			value := ConvertTemperature(36.6, UnitCelsius, from)
+			expected := ConvertTemperature(36.6, UnitCelsius, to)
+			assert.InDelta(t, expected, ConvertTemperature(value, from, to), 1e-9, "%s -> %s", from.Symbol, to.Symbol)
+		}
+	}
+}
+
+func TestUnit_AbsoluteZero(t *testing.T) {
+	assert.InDelta(t, -273.15, UnitCelsius.AbsoluteZero(), 1e-9)
+	assert.InDelta(t, -459.67, UnitFahrenheit.AbsoluteZero(), 1e-9)
+	assert.InDelta(t, 0, UnitKelvin.AbsoluteZero(), 1e-9)
+	assert.InDelta(t, 0, UnitRankine.AbsoluteZero(), 1e-9)
+	assert.InDelta(t, 559.725, UnitDelisle.AbsoluteZero(), 1e-9)
+}
+
+func TestConvertDelta(t *testing.T) {
+	// Uma variação de 10 °C não depende do zero de cada escala
+	assert.InDelta(t, 18, ConvertDelta(10, UnitCelsius, UnitFahrenheit), 1e-9)
+	assert.InDelta(t, 10, ConvertDelta(10, UnitCelsius, UnitKelvin), 1e-9)
+	assert.InDelta(t, 18, ConvertDelta(10, UnitKelvin, UnitRankine), 1e-9)
+	assert.InDelta(t, 8, ConvertDelta(10, UnitCelsius, UnitReaumur), 1e-9)
+	assert.InDelta(t, -15, ConvertDelta(10, UnitCelsius, UnitDelisle), 1e-9)
+	assert.InDelta(t, 10, ConvertDelta(18, UnitFahrenheit, UnitCelsius), 1e-9)
+}
+
+func TestRounding_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rounding Rounding
+		value    float64
+		expected float64
+	}{
+		{"sem arredondamento", Rounding{Mode: config.RoundingNone, Decimals: 2}, 83.30000000000001, 83.30000000000001},
+		{"half-even empata para o par abaixo", Rounding{Mode: config.RoundingHalfEven, Decimals: 1}, 0.25, 0.2},
+		{"half-even empata para o par acima", Rounding{Mode: config.RoundingHalfEven, Decimals: 1}, 0.35, 0.4},
+		{"half-even remove o ruído binário", Rounding{Mode: config.RoundingHalfEven, Decimals: 2}, 83.30000000000001, 83.3},
+		{"half-up", Rounding{Mode: config.RoundingHalfUp, Decimals: 1}, 0.25, 0.3},
+		{"half-up com representação inexata", Rounding{Mode: config.RoundingHalfUp, Decimals: 2}, 1.005, 1.01},
+		{"truncate", Rounding{Mode: config.RoundingTruncate, Decimals: 1}, 294.59, 294.5},
+		{"truncate com representação inexata", Rounding{Mode: config.RoundingTruncate, Decimals: 2}, 0.29, 0.29},
+		{"truncate negativo", Rounding{Mode: config.RoundingTruncate, Decimals: 0}, -3.9, -3},
+		{"zero casas", Rounding{Mode: config.RoundingHalfEven, Decimals: 0}, 294.55, 295},
+		{"dez casas half-even", Rounding{Mode: config.RoundingHalfEven, Decimals: 10}, 1234.56789012345, 1234.5678901234},
+		{"dez casas half-up", Rounding{Mode: config.RoundingHalfUp, Decimals: 10}, 1234.56789012345, 1234.5678901235},
+		{"dez casas truncate", Rounding{Mode: config.RoundingTruncate, Decimals: 10}, -298.27345678901299, -298.273456789},
+		{"vai um em todas as casas", Rounding{Mode: config.RoundingHalfUp, Decimals: 2}, 99.995, 100},
+		{"negativo muito pequeno vira zero", Rounding{Mode: config.RoundingHalfEven, Decimals: 2}, -0.001, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rounding.Apply(tt.value))
+		})
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	units, err := ParseUnits("C, f,K,R,re,DE,n,Rø,c")
+	assert.NoError(t, err)
+	assert.Equal(t, []Unit{UnitCelsius, UnitFahrenheit, UnitKelvin, UnitRankine, UnitReaumur, UnitDelisle, UnitNewton, UnitRomer}, units)
+
+	units, err = ParseUnits("")
+	assert.NoError(t, err)
+	assert.Empty(t, units)
+
+	_, err = ParseUnits("C,X")
+	assert.ErrorIs(t, err, ErrUnknownUnit)
+}
+
+func TestTemperatureService_Options(t *testing.T) {
+	t.Run("Kelvin legado", func(t *testing.T) {
+		service := NewTemperatureService(WithLegacyKelvin(true))
+		fahrenheit, kelvin := service.ConvertTemperatures(28.5)
+		assert.InDelta(t, 83.3, fahrenheit, 1e-9)
+		assert.Equal(t, 301.5, kelvin)
+		assert.Equal(t, 28.5, service.Convert(301.5, UnitKelvin, UnitCelsius))
+	})
+
+	t.Run("arredondamento configurado", func(t *testing.T) {
+		service := NewTemperatureService(WithRounding(Rounding{Mode: config.RoundingHalfEven, Decimals: 2}))
+		fahrenheit, kelvin := service.ConvertTemperatures(21.4)
+		assert.Equal(t, 70.52, fahrenheit)
+		assert.Equal(t, 294.55, kelvin)
+		assert.Equal(t, 18.0, service.ConvertDelta(10, UnitCelsius, UnitFahrenheit))
+	})
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cep-temperatura/internal/models"
+)
+
+// Intervalos padrão das consultas periódicas de clima
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultPollTimeout  = 10 * time.Second
+)
+
+// WeatherUpdate é o resultado de uma consulta periódica de clima: o clima ou o erro da consulta
+type WeatherUpdate struct {
+	Weather *models.WeatherResult
+	Err     error
+}
+
+// WeatherWatcher compartilha entre os assinantes uma única consulta periódica de clima por
+// município. A consulta começa com o primeiro assinante e para quando o último sai.
+type WeatherWatcher struct {
+	weatherService WeatherService
+	interval       time.Duration
+	timeout        time.Duration
+
+	mu      sync.Mutex
+	pollers map[string]*weatherPoller
+}
+
+// weatherPoller consulta o clima de um município e repassa cada resultado aos assinantes
+type weatherPoller struct {
+	cancel      context.CancelFunc
+	subscribers map[chan WeatherUpdate]struct{}
+	last        *WeatherUpdate
+}
+
+// WatcherOption personaliza o WeatherWatcher
+type WatcherOption func(*WeatherWatcher)
+
+// WithPollInterval define o intervalo entre as consultas de cada município
+func WithPollInterval(interval time.Duration) WatcherOption {
+	return func(w *WeatherWatcher) {
+		w.interval = interval
+	}
+}
+
+// WithPollTimeout limita cada consulta de clima; zero deixa a consulta sem prazo próprio
+func WithPollTimeout(timeout time.Duration) WatcherOption {
+	return func(w *WeatherWatcher) {
+		w.timeout = timeout
+	}
+}
+
+// NewWeatherWatcher cria o agendador de consultas periódicas de clima
+func NewWeatherWatcher(weatherService WeatherService, opts ...WatcherOption) *WeatherWatcher {
+	w := &WeatherWatcher{
+		weatherService: weatherService,
+		interval:       defaultPollInterval,
+		timeout:        defaultPollTimeout,
+		pollers:        map[string]*weatherPoller{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Subscribe assina o clima do município do CEP. O canal recebe o resultado de cada consulta;
+// um assinante lento recebe apenas o mais recente. Quem chega depois da primeira consulta
+// recebe de imediato o último resultado. A função devolvida cancela a assinatura e fecha o canal.
+func (w *WeatherWatcher) Subscribe(location *models.CEPResponse) (<-chan WeatherUpdate, func()) {
+	key := MunicipalityKey(location)
+	updates := make(chan WeatherUpdate, 1)
+
+	w.mu.Lock()
+	poller := w.pollers[key]
+	if poller == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		poller = &weatherPoller{cancel: cancel, subscribers: map[chan WeatherUpdate]struct{}{}}
+		w.pollers[key] = poller
+		go w.poll(ctx, poller, WeatherQueryFor(location))
+	}
+	poller.subscribers[updates] = struct{}{}
+	if poller.last != nil {
+		updates <- *poller.last
+	}
+	w.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			delete(poller.subscribers, updates)
+			close(updates)
+			if len(poller.subscribers) == 0 {
+				poller.cancel()
+				delete(w.pollers, key)
+			}
+		})
+	}
+	return updates, unsubscribe
+}
+
+// Pollers informa quantos municípios estão sendo consultados
+func (w *WeatherWatcher) Pollers() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pollers)
+}
+
+// poll consulta o clima de imediato e depois a cada intervalo, até o último assinante sair
+func (w *WeatherWatcher) poll(ctx context.Context, poller *weatherPoller, query models.WeatherQuery) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		pollCtx, cancel := w.pollContext(ctx)
+		weather, err := w.weatherService.GetTemperature(pollCtx, query)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		w.publish(poller, WeatherUpdate{Weather: weather, Err: err})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollContext aplica o prazo de cada consulta, quando houver
+func (w *WeatherWatcher) pollContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if w.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, w.timeout)
+}
+
+// publish entrega o resultado a cada assinante, descartando o anterior ainda não lido
+func (w *WeatherWatcher) publish(poller *weatherPoller, update WeatherUpdate) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	poller.last = &update
+	for subscriber := range poller.subscribers {
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- update
+	}
+}
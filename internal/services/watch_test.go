@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func receive(t *testing.T, updates <-chan WeatherUpdate) WeatherUpdate {
+	t.Helper()
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("nenhuma atualização recebida")
+		return WeatherUpdate{}
+	}
+}
+
+func TestWeatherWatcher_SharedPoller(t *testing.T) {
+	weatherService := new(mockWeatherService)
+	saoPaulo := &models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	rio := &models.CEPResponse{Localidade: "Rio de Janeiro", UF: "RJ", IBGE: "3304557"}
+	weatherService.On("GetTemperature", mock.Anything, WeatherQueryFor(saoPaulo)).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+	weatherService.On("GetTemperature", mock.Anything, WeatherQueryFor(rio)).Return(&models.WeatherResult{TempC: 30}, nil).Once()
+
+	// Com o intervalo longo, só a consulta inicial de cada município acontece
+	watcher := NewWeatherWatcher(weatherService, WithPollInterval(time.Hour))
+
+	first, unsubscribeFirst := watcher.Subscribe(saoPaulo)
+	assert.Equal(t, 25.0, receive(t, first).Weather.TempC)
+
+	// Outro CEP do mesmo município reaproveita a consulta e recebe o último resultado na hora
+	second, unsubscribeSecond := watcher.Subscribe(&models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308", CEP: "01001-000"})
+	assert.Equal(t, 25.0, receive(t, second).Weather.TempC)
+	assert.Equal(t, 1, watcher.Pollers())
+
+	other, unsubscribeOther := watcher.Subscribe(rio)
+	assert.Equal(t, 30.0, receive(t, other).Weather.TempC)
+	assert.Equal(t, 2, watcher.Pollers())
+
+	unsubscribeFirst()
+	assert.Equal(t, 2, watcher.Pollers())
+	unsubscribeSecond()
+	unsubscribeSecond()
+	assert.Equal(t, 1, watcher.Pollers())
+	unsubscribeOther()
+	assert.Equal(t, 0, watcher.Pollers())
+
+	_, open := <-first
+	assert.False(t, open)
+	weatherService.AssertExpectations(t)
+}
+
+func TestWeatherWatcher_Polls(t *testing.T) {
+	weatherService := new(mockWeatherService)
+	location := &models.CEPResponse{Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(nil, ErrUpstreamTimeout).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 26}, nil)
+
+	watcher := NewWeatherWatcher(weatherService, WithPollInterval(10*time.Millisecond))
+	updates, unsubscribe := watcher.Subscribe(location)
+
+	assert.Equal(t, 25.0, receive(t, updates).Weather.TempC)
+	assert.ErrorIs(t, receive(t, updates).Err, ErrUpstreamTimeout)
+	assert.Equal(t, 26.0, receive(t, updates).Weather.TempC)
+
+	unsubscribe()
+	assert.Equal(t, 0, watcher.Pollers())
+}
+
+func TestWeatherWatcher_SlowSubscriberGetsLatest(t *testing.T) {
+	watcher := NewWeatherWatcher(new(mockWeatherService))
+	updates := make(chan WeatherUpdate, 1)
+	poller := &weatherPoller{subscribers: map[chan WeatherUpdate]struct{}{updates: {}}}
+
+	watcher.publish(poller, WeatherUpdate{Weather: &models.WeatherResult{TempC: 25}})
+	watcher.publish(poller, WeatherUpdate{Weather: &models.WeatherResult{TempC: 26}})
+
+	assert.Equal(t, 26.0, receive(t, updates).Weather.TempC)
+	assert.Empty(t, updates)
+}
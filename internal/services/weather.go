@@ -1,11 +1,11 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
+	"time"
 
 	"cep-temperatura/internal/config"
 	"cep-temperatura/internal/models"
@@ -13,50 +13,235 @@ import (
 
 // WeatherService interface para operações de clima
 type WeatherService interface {
-	GetTemperature(city, state string) (float64, error)
+	GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error)
+	GetForecast(ctx context.Context, query models.WeatherQuery, options ForecastOptions) (*models.ForecastResult, error)
+	GetHistory(ctx context.Context, query models.WeatherQuery, options HistoryOptions) (*models.HistoryResult, error)
 }
 
+// WeatherProvider representa uma API de clima capaz de informar as condições atuais de um local
+type WeatherProvider interface {
+	Name() string
+	Current(ctx context.Context, location LocationQuery) (*models.WeatherResult, error)
+}
+
+// ForecastProvider é implementado pelos provedores de clima que oferecem previsão
+type ForecastProvider interface {
+	Forecast(ctx context.Context, location LocationQuery, options ForecastOptions) (*models.ForecastResult, error)
+}
+
+// ForecastOptions define o alcance de uma consulta de previsão
+type ForecastOptions struct {
+	Days   int
+	Hourly bool
+}
+
+// HistoryProvider é implementado pelos provedores de clima que oferecem dados históricos
+type HistoryProvider interface {
+	History(ctx context.Context, location LocationQuery, options HistoryOptions) (*models.HistoryResult, error)
+}
+
+// HistoryOptions define o intervalo de datas, inclusivo, de uma consulta de histórico
+type HistoryOptions struct {
+	From time.Time
+	To   time.Time
+}
+
+// LocationQuery é uma das formas de identificar o local na consulta a um provedor de clima:
+// pelas coordenadas do município, quando conhecidas, ou pelo nome da cidade e do estado
+type LocationQuery struct {
+	Strategy    string
+	City        string
+	Region      string
+	Coordinates *models.Coordinates
+}
+
+// Estratégias de consulta de local, na ordem de preferência
+const (
+	strategyCoordinates = "coordinates"
+	strategyCityUF      = "city_uf"
+	strategyCityState   = "city_state"
+)
+
 type weatherService struct {
-	baseURL string
-	apiKey  string
-	client  *http.Client
+	provider       WeatherProvider
+	centroids      MunicipalityCentroids
+	mismatchPolicy string
 }
 
-// NewWeatherService cria uma nova instância do serviço de clima
-func NewWeatherService(cfg *config.Config) WeatherService {
+// NewWeatherService cria uma nova instância do serviço de clima com o provedor configurado
+func NewWeatherService(cfg *config.Config) (WeatherService, error) {
+	centroids, err := loadCentroids(cfg.Weather.CentroidsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := newWeatherProvider(cfg, &http.Client{Timeout: cfg.Server.RequestTimeout})
+	if err != nil {
+		return nil, err
+	}
+
 	return &weatherService{
-		baseURL: cfg.Weather.BaseURL,
-		apiKey:  cfg.Weather.APIKey,
-		client:  &http.Client{},
+		provider:       provider,
+		centroids:      centroids,
+		mismatchPolicy: cfg.Weather.MismatchPolicy,
+	}, nil
+}
+
+// newWeatherProvider cria o adaptador do provedor de clima configurado
+func newWeatherProvider(cfg *config.Config, client *http.Client) (WeatherProvider, error) {
+	switch cfg.Weather.Provider {
+	case config.WeatherProviderWeatherAPI, "":
+		return &weatherAPIProvider{baseURL: cfg.Weather.BaseURL, apiKey: cfg.Weather.APIKey, client: client}, nil
+	case config.WeatherProviderOpenMeteo:
+		return &openMeteoProvider{
+			baseURL:      cfg.Weather.OpenMeteoURL,
+			geocodingURL: cfg.Weather.OpenMeteoGeocodingURL,
+			archiveURL:   cfg.Weather.OpenMeteoArchiveURL,
+			client:       client,
+		}, nil
+	case config.WeatherProviderOpenWeatherMap:
+		return &openWeatherMapProvider{
+			baseURL: cfg.Weather.OpenWeatherMapURL,
+			apiKey:  cfg.Weather.OpenWeatherMapAPIKey,
+			client:  client,
+		}, nil
+	case config.WeatherProviderINMET:
+		return newINMETProvider(cfg.Weather.INMETURL, client), nil
+	default:
+		return nil, fmt.Errorf("provedor de clima desconhecido: %s", cfg.Weather.Provider)
 	}
 }
 
-// GetTemperature busca a temperatura atual de uma cidade
-func (s *weatherService) GetTemperature(city, state string) (float64, error) {
-	// Construir query para a API
-	query := fmt.Sprintf("%s, %s, Brazil", city, state)
-	encodedQuery := url.QueryEscape(query)
-	apiURL := fmt.Sprintf("%s/current.json?key=%s&q=%s", s.baseURL, s.apiKey, encodedQuery)
+// GetTemperature busca a temperatura atual de uma cidade e confere se o local resolvido pelo
+// provedor de clima corresponde ao do CEP
+func (s *weatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	return resolveLocation(s, query,
+		func(location LocationQuery) (*models.WeatherResult, error) {
+			return s.provider.Current(ctx, location)
+		},
+		func(result *models.WeatherResult) (*models.WeatherLocation, *models.LocationMatch) {
+			return &result.Location, &result.Match
+		},
+	)
+}
 
-	resp, err := s.client.Get(apiURL)
-	if err != nil {
-		return 0, fmt.Errorf("erro ao consultar clima: %w", err)
+// GetForecast busca a previsão de uma cidade com a mesma conferência de local de GetTemperature
+func (s *weatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options ForecastOptions) (*models.ForecastResult, error) {
+	forecaster, ok := s.provider.(ForecastProvider)
+	if !ok {
+		return nil, fmt.Errorf("provedor %s: %w", s.provider.Name(), ErrForecastUnsupported)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("erro ao ler resposta: %w", err)
+	return resolveLocation(s, query,
+		func(location LocationQuery) (*models.ForecastResult, error) {
+			return forecaster.Forecast(ctx, location, options)
+		},
+		func(result *models.ForecastResult) (*models.WeatherLocation, *models.LocationMatch) {
+			return &result.Location, &result.Match
+		},
+	)
+}
+
+// GetHistory busca as temperaturas diárias observadas em uma cidade num intervalo de datas
+func (s *weatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options HistoryOptions) (*models.HistoryResult, error) {
+	historian, ok := s.provider.(HistoryProvider)
+	if !ok {
+		return nil, fmt.Errorf("provedor %s: %w", s.provider.Name(), ErrHistoryUnsupported)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("erro ao consultar clima: status %d", resp.StatusCode)
+	return resolveLocation(s, query,
+		func(location LocationQuery) (*models.HistoryResult, error) {
+			return historian.History(ctx, location, options)
+		},
+		func(result *models.HistoryResult) (*models.WeatherLocation, *models.LocationMatch) {
+			return &result.Location, &result.Match
+		},
+	)
+}
+
+// resolveLocation consulta o provedor seguindo as estratégias de local e confere se o local
+// resolvido corresponde ao do CEP. Em caso de divergência, a política configurada decide entre
+// apenas sinalizar, tentar as estratégias alternativas ou rejeitar o resultado.
+func resolveLocation[T any](
+	s *weatherService,
+	query models.WeatherQuery,
+	call func(LocationQuery) (*T, error),
+	located func(*T) (*models.WeatherLocation, *models.LocationMatch),
+) (*T, error) {
+	strategies := s.locationStrategies(query)
+
+	var flagged *T
+	var flaggedLocation *models.WeatherLocation
+	var flaggedMatch *models.LocationMatch
+	for attempt, strategy := range strategies {
+		result, err := call(strategy)
+		if err != nil {
+			// Falha numa tentativa alternativa: fica valendo o resultado já obtido
+			if flagged != nil {
+				break
+			}
+			if s.mismatchPolicy == config.MismatchPolicyRetry && errors.Is(err, ErrWeatherLocationNotFound) && attempt < len(strategies)-1 {
+				continue
+			}
+			return nil, err
+		}
+
+		location, match := located(result)
+		*match = models.LocationMatch{Strategy: strategy.Strategy, Attempts: attempt + 1}
+		// Sem o nome do local resolvido (ex.: consulta por coordenadas na Open-Meteo) não há o que comparar
+		if s.mismatchPolicy == config.MismatchPolicyIgnore || location.Name == "" {
+			match.Status = models.LocationUnchecked
+			return result, nil
+		}
+		if matchLocation(query, *location) {
+			match.Status = models.LocationMatched
+			return result, nil
+		}
+
+		match.Status = models.LocationMismatch
+		if flagged == nil {
+			flagged, flaggedLocation, flaggedMatch = result, location, match
+		}
+		flaggedMatch.Attempts = attempt + 1
+		if s.mismatchPolicy != config.MismatchPolicyRetry {
+			break
+		}
 	}
 
-	var weatherResponse models.WeatherResponse
-	if err := json.Unmarshal(body, &weatherResponse); err != nil {
-		return 0, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	if s.mismatchPolicy == config.MismatchPolicyReject {
+		return nil, fmt.Errorf("clima resolvido para %s, %s (esperado %s, %s): %w",
+			flaggedLocation.Name, flaggedLocation.Region, query.City, query.State, ErrWeatherLocationMismatch)
+	}
+
+	return flagged, nil
+}
+
+// locationStrategies lista as formas de consultar o local: coordenadas do município quando o
+// código IBGE é conhecido e, em seguida, o nome da cidade com a UF e com o nome do estado
+func (s *weatherService) locationStrategies(query models.WeatherQuery) []LocationQuery {
+	strategies := make([]LocationQuery, 0, 3)
+	if coordinates, ok := s.centroids.Lookup(query.IBGE); ok {
+		strategies = append(strategies, LocationQuery{
+			Strategy:    strategyCoordinates,
+			City:        query.City,
+			Region:      query.State,
+			Coordinates: &coordinates,
+		})
+	}
+
+	strategies = append(strategies, LocationQuery{
+		Strategy: strategyCityUF,
+		City:     query.City,
+		Region:   query.State,
+	})
+
+	if name := stateName(query.State); name != query.State {
+		strategies = append(strategies, LocationQuery{
+			Strategy: strategyCityState,
+			City:     query.City,
+			Region:   name,
+		})
 	}
 
-	return weatherResponse.Current.TempC, nil
+	return strategies
 }
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherAPIProvider_Forecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/forecast.json", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("days"))
+		w.Write([]byte(`{
+			"location": {"name": "São Paulo", "region": "Sao Paulo", "country": "Brazil"},
+			"forecast": {"forecastday": [
+				{"date": "2025-01-10", "day": {"maxtemp_c": 28.1, "mintemp_c": 18.4, "avgtemp_c": 22.9},
+				 "hour": [{"time": "2025-01-10 00:00", "temp_c": 19.5}, {"time": "2025-01-10 01:00", "temp_c": 19.1}]},
+				{"date": "2025-01-11", "day": {"maxtemp_c": 30.0, "mintemp_c": 19.0, "avgtemp_c": 24.2}, "hour": []}
+			]}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := newTestWeatherAPIProvider(server.URL)
+
+	t.Run("sem horas", func(t *testing.T) {
+		result, err := provider.Forecast(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"}, ForecastOptions{Days: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, "São Paulo", result.Location.Name)
+		assert.Equal(t, []models.ForecastDay{
+			{Date: "2025-01-10", MinC: 18.4, MaxC: 28.1, AvgC: 22.9},
+			{Date: "2025-01-11", MinC: 19.0, MaxC: 30.0, AvgC: 24.2},
+		}, result.Days)
+	})
+
+	t.Run("com horas", func(t *testing.T) {
+		result, err := provider.Forecast(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"}, ForecastOptions{Days: 2, Hourly: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []models.ForecastHour{
+			{Time: "2025-01-10 00:00", TempC: 19.5},
+			{Time: "2025-01-10 01:00", TempC: 19.1},
+		}, result.Days[0].Hours)
+	})
+}
+
+func TestOpenMeteoProvider_Forecast(t *testing.T) {
+	var forecastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forecastQuery = r.URL.RawQuery
+		w.Write([]byte(`{
+			"daily": {"time": ["2025-01-10", "2025-01-11"], "temperature_2m_max": [28.1, 30.0], "temperature_2m_min": [18.4, 19.0], "temperature_2m_mean": [22.9, 24.2]},
+			"hourly": {"time": ["2025-01-10T00:00", "2025-01-11T00:00"], "temperature_2m": [19.5, 20.1]}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := &openMeteoProvider{baseURL: server.URL, geocodingURL: server.URL, client: &http.Client{}}
+	location := LocationQuery{Coordinates: &models.Coordinates{Latitude: -23.5505, Longitude: -46.6333}}
+
+	result, err := provider.Forecast(context.Background(), location, ForecastOptions{Days: 2, Hourly: true})
+	assert.NoError(t, err)
+	assert.Contains(t, forecastQuery, "forecast_days=2")
+	assert.Contains(t, forecastQuery, "hourly=temperature_2m")
+	assert.Equal(t, []models.ForecastDay{
+		{Date: "2025-01-10", MinC: 18.4, MaxC: 28.1, AvgC: 22.9, Hours: []models.ForecastHour{{Time: "2025-01-10T00:00", TempC: 19.5}}},
+		{Date: "2025-01-11", MinC: 19.0, MaxC: 30.0, AvgC: 24.2, Hours: []models.ForecastHour{{Time: "2025-01-11T00:00", TempC: 20.1}}},
+	}, result.Days)
+
+	_, err = provider.Forecast(context.Background(), location, ForecastOptions{Days: 2})
+	assert.NoError(t, err)
+	assert.NotContains(t, forecastQuery, "hourly")
+}
+
+func TestOpenMeteoProvider_Forecast_InconsistentSeries(t *testing.T) {
+	server := newJSONServer(http.StatusOK, `{"daily": {"time": ["2025-01-10", "2025-01-11"], "temperature_2m_max": [28.1], "temperature_2m_min": [18.4], "temperature_2m_mean": [22.9]}}`)
+	defer server.Close()
+
+	provider := &openMeteoProvider{baseURL: server.URL, geocodingURL: server.URL, client: &http.Client{}}
+
+	_, err := provider.Forecast(context.Background(), LocationQuery{Coordinates: &models.Coordinates{}}, ForecastOptions{Days: 2})
+	assert.ErrorIs(t, err, ErrBadUpstreamPayload)
+}
+
+func TestOpenWeatherMapProvider_Forecast(t *testing.T) {
+	// Previsões de 3 horas em UTC; no fuso de Brasília (-3h) a das 03:00 UTC do dia 11 ainda é dia 10
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/forecast", r.URL.Path)
+		assert.Equal(t, "8", r.URL.Query().Get("cnt"))
+		w.Write([]byte(`{
+			"list": [
+				{"dt": 1736510400, "main": {"temp": 24.0}},
+				{"dt": 1736521200, "main": {"temp": 20.0}},
+				{"dt": 1736532000, "main": {"temp": 18.0}},
+				{"dt": 1736564400, "main": {"temp": 27.0}}
+			],
+			"city": {"name": "São Paulo", "country": "BR", "timezone": -10800}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := &openWeatherMapProvider{baseURL: server.URL, apiKey: "test", client: &http.Client{}}
+
+	result, err := provider.Forecast(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"}, ForecastOptions{Days: 1, Hourly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, models.WeatherLocation{Name: "São Paulo", Country: "BR"}, result.Location)
+	assert.Len(t, result.Days, 1)
+
+	day := result.Days[0]
+	assert.Equal(t, "2025-01-10", day.Date)
+	assert.Equal(t, 18.0, day.MinC)
+	assert.Equal(t, 24.0, day.MaxC)
+	assert.InDelta(t, 20.67, day.AvgC, 0.01)
+	assert.Equal(t, []models.ForecastHour{
+		{Time: "2025-01-10 09:00", TempC: 24.0},
+		{Time: "2025-01-10 12:00", TempC: 20.0},
+		{Time: "2025-01-10 15:00", TempC: 18.0},
+	}, day.Hours)
+}
+
+func TestWeatherService_GetForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "Bom Jesus, PI, Brazil":
+			w.Write([]byte(`{"location": {"name": "Bom Jesus", "region": "Rio Grande do Sul", "country": "Brazil"}, "forecast": {"forecastday": []}}`))
+		default:
+			w.Write([]byte(`{"location": {"name": "Bom Jesus", "region": "Piaui", "country": "Brazil"}, "forecast": {"forecastday": [{"date": "2025-01-10", "day": {"maxtemp_c": 36.0}}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	service := &weatherService{
+		provider:       newTestWeatherAPIProvider(server.URL),
+		mismatchPolicy: config.MismatchPolicyRetry,
+	}
+
+	result, err := service.GetForecast(context.Background(), models.WeatherQuery{City: "Bom Jesus", State: "PI"}, ForecastOptions{Days: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, models.LocationMatch{Status: models.LocationMatched, Strategy: strategyCityState, Attempts: 2}, result.Match)
+	assert.Len(t, result.Days, 1)
+}
+
+func TestWeatherService_GetForecast_Unsupported(t *testing.T) {
+	service := &weatherService{provider: newINMETProvider("http://127.0.0.1:0", &http.Client{})}
+
+	_, err := service.GetForecast(context.Background(), models.WeatherQuery{City: "São Paulo", State: "SP"}, ForecastOptions{Days: 1})
+	assert.ErrorIs(t, err, ErrForecastUnsupported)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func historyOptions(from, to string) HistoryOptions {
+	fromDate, _ := time.Parse(time.DateOnly, from)
+	toDate, _ := time.Parse(time.DateOnly, to)
+	return HistoryOptions{From: fromDate, To: toDate}
+}
+
+func TestWeatherAPIProvider_History(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/history.json", r.URL.Path)
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("dt"))
+		assert.Equal(t, "2024-01-02", r.URL.Query().Get("end_dt"))
+		w.Write([]byte(`{
+			"location": {"name": "São Paulo", "region": "Sao Paulo", "country": "Brazil"},
+			"forecast": {"forecastday": [
+				{"date": "2024-01-01", "day": {"maxtemp_c": 28.1, "mintemp_c": 18.4, "avgtemp_c": 22.9}},
+				{"date": "2024-01-02", "day": {"maxtemp_c": 30.0, "mintemp_c": 19.0, "avgtemp_c": 24.2}}
+			]}
+		}`))
+	}))
+	defer server.Close()
+
+	result, err := newTestWeatherAPIProvider(server.URL).History(context.Background(),
+		LocationQuery{City: "São Paulo", Region: "SP"}, historyOptions("2024-01-01", "2024-01-02"))
+	assert.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.Location.Name)
+	assert.Equal(t, []models.HistoryDay{
+		{Date: "2024-01-01", MinC: 18.4, MaxC: 28.1, AvgC: 22.9},
+		{Date: "2024-01-02", MinC: 19.0, MaxC: 30.0, AvgC: 24.2},
+	}, result.Days)
+}
+
+func TestOpenMeteoProvider_History(t *testing.T) {
+	var archiveQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/archive", r.URL.Path)
+		archiveQuery = r.URL.RawQuery
+		w.Write([]byte(`{"daily": {
+			"time": ["2024-01-01", "2024-01-02"],
+			"temperature_2m_max": [28.1, null],
+			"temperature_2m_min": [18.4, null],
+			"temperature_2m_mean": [22.9, null]
+		}}`))
+	}))
+	defer server.Close()
+
+	provider := &openMeteoProvider{baseURL: server.URL, geocodingURL: server.URL, archiveURL: server.URL, client: &http.Client{}}
+
+	result, err := provider.History(context.Background(),
+		LocationQuery{Coordinates: &models.Coordinates{Latitude: -23.5505, Longitude: -46.6333}}, historyOptions("2024-01-01", "2024-01-02"))
+	assert.NoError(t, err)
+	assert.Contains(t, archiveQuery, "start_date=2024-01-01&end_date=2024-01-02")
+	// O dia ainda sem dados consolidados fica de fora
+	assert.Equal(t, []models.HistoryDay{{Date: "2024-01-01", MinC: 18.4, MaxC: 28.1, AvgC: 22.9}}, result.Days)
+}
+
+func TestINMETProvider_History(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/estacoes/T":
+			w.Write([]byte(`[{"CD_ESTACAO": "A701", "DC_NOME": "SAO PAULO - MIRANTE", "SG_ESTADO": "SP", "VL_LATITUDE": "-23.49", "VL_LONGITUDE": "-46.62", "CD_SITUACAO": "Operante"}]`))
+		case "/estacao/2024-01-01/2024-01-02/A701":
+			w.Write([]byte(`[
+				{"DT_MEDICAO": "2024-01-01", "HR_MEDICAO": "0000", "TEM_INS": "20.0", "TEM_MIN": "19.5", "TEM_MAX": "20.4"},
+				{"DT_MEDICAO": "2024-01-01", "HR_MEDICAO": "1200", "TEM_INS": "26.0", "TEM_MIN": null, "TEM_MAX": null},
+				{"DT_MEDICAO": "2024-01-01", "HR_MEDICAO": "1800", "TEM_INS": null},
+				{"DT_MEDICAO": "2024-01-02", "HR_MEDICAO": "0000", "TEM_INS": "21.0", "TEM_MIN": "20.8", "TEM_MAX": "21.3"}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := newINMETProvider(server.URL, &http.Client{})
+
+	result, err := provider.History(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"}, historyOptions("2024-01-01", "2024-01-02"))
+	assert.NoError(t, err)
+	assert.Equal(t, models.WeatherLocation{Name: "SAO PAULO", Region: "SP", Country: "Brazil"}, result.Location)
+	assert.Equal(t, []models.HistoryDay{
+		{Date: "2024-01-01", MinC: 19.5, MaxC: 26.0, AvgC: 23.0},
+		{Date: "2024-01-02", MinC: 20.8, MaxC: 21.3, AvgC: 21.0},
+	}, result.Days)
+}
+
+func TestWeatherService_GetHistory_Unsupported(t *testing.T) {
+	service := &weatherService{provider: &openWeatherMapProvider{baseURL: "http://127.0.0.1:0", client: &http.Client{}}}
+
+	_, err := service.GetHistory(context.Background(), models.WeatherQuery{City: "São Paulo", State: "SP"}, historyOptions("2024-01-01", "2024-01-02"))
+	assert.ErrorIs(t, err, ErrHistoryUnsupported)
+}
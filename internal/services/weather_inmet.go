@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+)
+
+// inmetStationsTTL define por quanto tempo a lista de estações fica em cache
+const inmetStationsTTL = 24 * time.Hour
+
+// inmetMaxStationKm limita a distância entre o município e a estação mais próxima; além
+// dela, a medição já não representa o clima do município
+const inmetMaxStationKm = 150.0
+
+// inmetProvider consulta as estações automáticas do INMET (apitempo.inmet.gov.br).
+// O clima vem da estação mais próxima das coordenadas do município ou, na consulta
+// por nome, de uma estação da mesma UF cujo nome corresponda ao da cidade.
+type inmetProvider struct {
+	baseURL string
+	client  *http.Client
+	now     func() time.Time
+
+	mu          sync.Mutex
+	stations    []inmetStation
+	stationsAge time.Time
+	// refreshing é fechado ao fim da carga da lista em andamento
+	refreshing chan struct{}
+}
+
+type inmetStation struct {
+	code        string
+	city        string
+	uf          string
+	coordinates models.Coordinates
+}
+
+func newINMETProvider(baseURL string, client *http.Client) *inmetProvider {
+	return &inmetProvider{baseURL: baseURL, client: client, now: time.Now}
+}
+
+func (p *inmetProvider) Name() string { return config.WeatherProviderINMET }
+
+// Current retorna a medição mais recente da estação escolhida para o local
+func (p *inmetProvider) Current(ctx context.Context, location LocationQuery) (*models.WeatherResult, error) {
+	station, err := p.station(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	// As medições são publicadas em UTC; a janela de dois dias cobre a virada do dia
+	today := p.now().UTC()
+	apiURL := fmt.Sprintf("%s/estacao/%s/%s/%s", p.baseURL,
+		today.AddDate(0, 0, -1).Format(time.DateOnly), today.Format(time.DateOnly), station.code)
+
+	var observations []models.INMETObservation
+	if err := p.getJSON(ctx, apiURL, &observations); err != nil {
+		return nil, err
+	}
+
+	for i := len(observations) - 1; i >= 0; i-- {
+		observation := observations[i]
+		if observation.Temperature == nil {
+			continue
+		}
+		temperature, err := strconv.ParseFloat(*observation.Temperature, 64)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao decodificar temperatura do INMET: %w: %w", ErrBadUpstreamPayload, err)
+		}
+		return &models.WeatherResult{
+			TempC:      temperature,
+			Conditions: inmetConditions(observation),
+			Location:   station.location(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("estação %s sem medições recentes: %w", station.code, ErrUpstreamUnavailable)
+}
+
+// History agrega as medições horárias da estação em mínima, máxima e média de cada dia.
+// Os dias seguem a data das medições, publicadas em UTC.
+func (p *inmetProvider) History(ctx context.Context, location LocationQuery, options HistoryOptions) (*models.HistoryResult, error) {
+	station, err := p.station(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s/estacao/%s/%s/%s", p.baseURL,
+		options.From.Format(time.DateOnly), options.To.Format(time.DateOnly), station.code)
+
+	var observations []models.INMETObservation
+	if err := p.getJSON(ctx, apiURL, &observations); err != nil {
+		return nil, err
+	}
+
+	result := &models.HistoryResult{Location: station.location()}
+	var sum float64
+	var count int
+	for _, observation := range observations {
+		if observation.Temperature == nil {
+			continue
+		}
+		temperature, err := strconv.ParseFloat(*observation.Temperature, 64)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao decodificar temperatura do INMET: %w: %w", ErrBadUpstreamPayload, err)
+		}
+		// As extremas da hora, quando informadas, refinam a mínima e a máxima do dia
+		low, high := temperature, temperature
+		if value, ok := parseINMETValue(observation.MinTemp); ok {
+			low = value
+		}
+		if value, ok := parseINMETValue(observation.MaxTemp); ok {
+			high = value
+		}
+
+		last := len(result.Days) - 1
+		if last < 0 || result.Days[last].Date != observation.Date {
+			result.Days = append(result.Days, models.HistoryDay{Date: observation.Date, MinC: low, MaxC: high})
+			last++
+			sum, count = 0, 0
+		}
+
+		day := &result.Days[last]
+		day.MinC = math.Min(day.MinC, low)
+		day.MaxC = math.Max(day.MaxC, high)
+		sum += temperature
+		count++
+		day.AvgC = sum / float64(count)
+	}
+
+	return result, nil
+}
+
+// station escolhe a estação do INMET para o local consultado
+func (p *inmetProvider) station(ctx context.Context, location LocationQuery) (inmetStation, error) {
+	stations, err := p.loadStations(ctx)
+	if err != nil {
+		return inmetStation{}, err
+	}
+
+	station, ok := selectINMETStation(stations, location)
+	if !ok {
+		return inmetStation{}, fmt.Errorf("nenhuma estação do INMET para %s, %s: %w", location.City, location.Region, ErrWeatherLocationNotFound)
+	}
+	return station, nil
+}
+
+// location descreve a estação como o local resolvido da consulta
+func (s inmetStation) location() models.WeatherLocation {
+	return models.WeatherLocation{
+		Name:    s.city,
+		Region:  s.uf,
+		Country: "Brazil",
+	}
+}
+
+// inmetConditions extrai as demais grandezas da medição. As estações não informam a condição
+// do tempo nem o índice UV; o vento vem em m/s e o horário da medição em UTC.
+func inmetConditions(observation models.INMETObservation) models.Conditions {
+	conditions := models.Conditions{
+		Humidity:   optionalINMETValue(observation.Humidity),
+		PressureMb: optionalINMETValue(observation.Pressure),
+		WindKph:    metersPerSecondToKph(optionalINMETValue(observation.WindSpeed)),
+		WindDegree: optionalINMETValue(observation.WindDir),
+	}
+	conditions.WindDir = compassDirection(conditions.WindDegree)
+	if measured, err := time.Parse("2006-01-02 1504", observation.Date+" "+observation.Hour); err == nil {
+		conditions.LastUpdated = measured.Format("2006-01-02 15:04") + " UTC"
+		conditions.ObservedAt = measured
+	}
+	return conditions
+}
+
+// optionalINMETValue devolve o campo numérico opcional de uma medição, ou nil quando ausente
+func optionalINMETValue(value *string) *float64 {
+	parsed, ok := parseINMETValue(value)
+	if !ok {
+		return nil
+	}
+	return &parsed
+}
+
+// parseINMETValue interpreta um campo numérico opcional de uma medição
+func parseINMETValue(value *string) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	parsed, err := strconv.ParseFloat(*value, 64)
+	return parsed, err == nil
+}
+
+// loadStations carrega a lista de estações automáticas, mantendo-a em cache. A consulta ao
+// INMET é feita fora do lock e uma única vez por renovação: as requisições concorrentes
+// usam a lista vencida, quando houver, ou aguardam a carga em andamento.
+func (p *inmetProvider) loadStations(ctx context.Context) ([]inmetStation, error) {
+	for {
+		p.mu.Lock()
+		if p.stations != nil && p.now().Sub(p.stationsAge) < inmetStationsTTL {
+			stations := p.stations
+			p.mu.Unlock()
+			return stations, nil
+		}
+		if p.refreshing == nil {
+			break
+		}
+		if p.stations != nil {
+			stations := p.stations
+			p.mu.Unlock()
+			return stations, nil
+		}
+		refreshing := p.refreshing
+		p.mu.Unlock()
+
+		// Se a carga em andamento falhar, a próxima volta tenta de novo
+		select {
+		case <-refreshing:
+		case <-ctx.Done():
+			return nil, contextError(ctx.Err())
+		}
+	}
+
+	refreshing := make(chan struct{})
+	p.refreshing = refreshing
+	p.mu.Unlock()
+
+	stations, err := p.fetchStations(ctx)
+
+	p.mu.Lock()
+	p.refreshing = nil
+	if err == nil {
+		p.stations = stations
+		p.stationsAge = p.now()
+	}
+	p.mu.Unlock()
+	close(refreshing)
+	return stations, err
+}
+
+// fetchStations consulta a lista de estações automáticas operantes
+func (p *inmetProvider) fetchStations(ctx context.Context) ([]inmetStation, error) {
+	var response []models.INMETStation
+	if err := p.getJSON(ctx, p.baseURL+"/estacoes/T", &response); err != nil {
+		return nil, err
+	}
+
+	stations := make([]inmetStation, 0, len(response))
+	for _, station := range response {
+		if station.Status != "" && station.Status != "Operante" {
+			continue
+		}
+		latitude, errLat := strconv.ParseFloat(station.Latitude, 64)
+		longitude, errLon := strconv.ParseFloat(station.Longitude, 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+		stations = append(stations, inmetStation{
+			code:        station.Code,
+			city:        inmetStationCity(station.Name),
+			uf:          station.UF,
+			coordinates: models.Coordinates{Latitude: latitude, Longitude: longitude},
+		})
+	}
+	return stations, nil
+}
+
+// getJSON consulta a API do INMET e decodifica a resposta
+func (p *inmetProvider) getJSON(ctx context.Context, apiURL string, v interface{}) error {
+	status, body, err := fetch(ctx, p.client, apiURL, "clima")
+	if err != nil {
+		return err
+	}
+
+	// O INMET responde 204 quando não há dados para o período
+	if status == http.StatusNoContent {
+		return fmt.Errorf("INMET sem dados para o período: %w", ErrUpstreamUnavailable)
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("erro ao consultar clima: status %d: %w", status, classifyStatus(status))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta: %w: %w", ErrBadUpstreamPayload, err)
+	}
+
+	return nil
+}
+
+// inmetStationCity extrai o nome da cidade do nome da estação ("SAO PAULO - MIRANTE" -> "SAO PAULO")
+func inmetStationCity(name string) string {
+	city, _, _ := strings.Cut(name, " - ")
+	return strings.TrimSpace(city)
+}
+
+// selectINMETStation escolhe a estação mais próxima das coordenadas, a até inmetMaxStationKm,
+// ou, sem elas, uma estação da mesma UF com o nome da cidade
+func selectINMETStation(stations []inmetStation, location LocationQuery) (inmetStation, bool) {
+	if location.Coordinates != nil {
+		var nearest inmetStation
+		best := math.Inf(1)
+		for _, station := range stations {
+			if distance := haversineKm(*location.Coordinates, station.coordinates); distance < best {
+				best = distance
+				nearest = station
+			}
+		}
+		return nearest, best <= inmetMaxStationKm
+	}
+
+	uf, _ := stateUF(location.Region)
+	city := normalizeName(location.City)
+	for _, station := range stations {
+		if station.uf == uf && normalizeName(station.city) == city {
+			return station, true
+		}
+	}
+	return inmetStation{}, false
+}
+
+// haversineKm calcula a distância em quilômetros entre dois pontos
+func haversineKm(a, b models.Coordinates) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRad(b.Latitude - a.Latitude)
+	dLon := toRad(b.Longitude - a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Latitude))*math.Cos(toRad(b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+)
+
+// openMeteoProvider consulta a Open-Meteo (open-meteo.com), que não exige chave de API.
+// A API trabalha apenas com coordenadas; consultas por nome passam antes pela geocodificação.
+type openMeteoProvider struct {
+	baseURL      string
+	geocodingURL string
+	archiveURL   string
+	client       *http.Client
+}
+
+func (p *openMeteoProvider) Name() string { return config.WeatherProviderOpenMeteo }
+
+// openMeteoCurrentVariables são as variáveis pedidas na consulta de condições atuais
+const openMeteoCurrentVariables = "temperature_2m,relative_humidity_2m,apparent_temperature,weather_code," +
+	"wind_speed_10m,wind_direction_10m,pressure_msl,uv_index"
+
+// Current consulta as condições atuais nas coordenadas do local, no fuso horário do local
+func (p *openMeteoProvider) Current(ctx context.Context, location LocationQuery) (*models.WeatherResult, error) {
+	coordinates, resolved, err := p.resolve(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s/forecast?latitude=%.4f&longitude=%.4f&current=%s&timezone=auto",
+		p.baseURL, coordinates.Latitude, coordinates.Longitude, openMeteoCurrentVariables)
+
+	var response models.OpenMeteoCurrentResponse
+	if err := p.getJSON(ctx, apiURL, &response); err != nil {
+		return nil, err
+	}
+
+	current := response.Current
+	conditions := models.Conditions{
+		Code:        current.WeatherCode,
+		Humidity:    current.RelativeHumidity2m,
+		WindKph:     current.WindSpeed10m,
+		WindDegree:  current.WindDirection10m,
+		WindDir:     compassDirection(current.WindDirection10m),
+		PressureMb:  current.PressureMSL,
+		FeelsLikeC:  current.ApparentTemperature,
+		UV:          current.UVIndex,
+		LastUpdated: strings.Replace(current.Time, "T", " ", 1),
+	}
+	if current.WeatherCode != nil {
+		conditions.Text = wmoDescriptions[*current.WeatherCode]
+	}
+	// O horário vem no fuso do local, sem o deslocamento, informado à parte
+	zone := time.FixedZone("", response.UTCOffsetSeconds)
+	if observed, err := time.ParseInLocation("2006-01-02T15:04", current.Time, zone); err == nil {
+		conditions.ObservedAt = observed.UTC()
+	}
+
+	return &models.WeatherResult{
+		TempC:      current.Temperature2m,
+		Conditions: conditions,
+		Location:   resolved,
+	}, nil
+}
+
+// Forecast consulta a previsão diária, e a horária quando solicitada, no fuso horário do local.
+// A Open-Meteo oferece até 16 dias de previsão.
+func (p *openMeteoProvider) Forecast(ctx context.Context, location LocationQuery, options ForecastOptions) (*models.ForecastResult, error) {
+	coordinates, resolved, err := p.resolve(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s/forecast?latitude=%.4f&longitude=%.4f&daily=temperature_2m_max,temperature_2m_min,temperature_2m_mean&timezone=auto&forecast_days=%d",
+		p.baseURL, coordinates.Latitude, coordinates.Longitude, options.Days)
+	if options.Hourly {
+		apiURL += "&hourly=temperature_2m"
+	}
+
+	var response models.OpenMeteoForecastResponse
+	if err := p.getJSON(ctx, apiURL, &response); err != nil {
+		return nil, err
+	}
+
+	daily := response.Daily
+	if len(daily.Max) != len(daily.Time) || len(daily.Min) != len(daily.Time) || len(daily.Mean) != len(daily.Time) ||
+		len(response.Hourly.Temperature2m) != len(response.Hourly.Time) {
+		return nil, fmt.Errorf("erro ao decodificar resposta: séries de tamanhos diferentes: %w", ErrBadUpstreamPayload)
+	}
+
+	result := &models.ForecastResult{
+		Days:     make([]models.ForecastDay, len(daily.Time)),
+		Location: resolved,
+	}
+	index := make(map[string]int, len(daily.Time))
+	for i, date := range daily.Time {
+		result.Days[i] = models.ForecastDay{Date: date, MinC: daily.Min[i], MaxC: daily.Max[i], AvgC: daily.Mean[i]}
+		index[date] = i
+	}
+
+	// Os horários chegam no formato 2006-01-02T15:04; os dez primeiros caracteres são a data
+	for i, hour := range response.Hourly.Time {
+		if len(hour) < len("2006-01-02") {
+			continue
+		}
+		if day, ok := index[hour[:len("2006-01-02")]]; ok {
+			result.Days[day].Hours = append(result.Days[day].Hours, models.ForecastHour{Time: hour, TempC: response.Hourly.Temperature2m[i]})
+		}
+	}
+
+	return result, nil
+}
+
+// History consulta as temperaturas diárias na API de histórico (reanálise ERA5). Os dias mais
+// recentes ainda sem dados consolidados chegam nulos e ficam fora do resultado.
+func (p *openMeteoProvider) History(ctx context.Context, location LocationQuery, options HistoryOptions) (*models.HistoryResult, error) {
+	coordinates, resolved, err := p.resolve(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s/archive?latitude=%.4f&longitude=%.4f&start_date=%s&end_date=%s&daily=temperature_2m_max,temperature_2m_min,temperature_2m_mean&timezone=auto",
+		p.archiveURL, coordinates.Latitude, coordinates.Longitude, options.From.Format(time.DateOnly), options.To.Format(time.DateOnly))
+
+	var response models.OpenMeteoArchiveResponse
+	if err := p.getJSON(ctx, apiURL, &response); err != nil {
+		return nil, err
+	}
+
+	daily := response.Daily
+	if len(daily.Max) != len(daily.Time) || len(daily.Min) != len(daily.Time) || len(daily.Mean) != len(daily.Time) {
+		return nil, fmt.Errorf("erro ao decodificar resposta: séries de tamanhos diferentes: %w", ErrBadUpstreamPayload)
+	}
+
+	result := &models.HistoryResult{
+		Days:     make([]models.HistoryDay, 0, len(daily.Time)),
+		Location: resolved,
+	}
+	for i, date := range daily.Time {
+		if daily.Min[i] == nil || daily.Max[i] == nil || daily.Mean[i] == nil {
+			continue
+		}
+		result.Days = append(result.Days, models.HistoryDay{Date: date, MinC: *daily.Min[i], MaxC: *daily.Max[i], AvgC: *daily.Mean[i]})
+	}
+
+	return result, nil
+}
+
+// resolve obtém as coordenadas do local. Para consultas por coordenadas não há nome resolvido;
+// para consultas por nome, o resultado da geocodificação no mesmo estado é o preferido.
+func (p *openMeteoProvider) resolve(ctx context.Context, location LocationQuery) (models.Coordinates, models.WeatherLocation, error) {
+	if location.Coordinates != nil {
+		return *location.Coordinates, models.WeatherLocation{}, nil
+	}
+
+	apiURL := fmt.Sprintf("%s/search?name=%s&count=10&language=pt&format=json&countryCode=BR",
+		p.geocodingURL, url.QueryEscape(location.City))
+
+	var response models.OpenMeteoGeocodingResponse
+	if err := p.getJSON(ctx, apiURL, &response); err != nil {
+		return models.Coordinates{}, models.WeatherLocation{}, err
+	}
+
+	if len(response.Results) == 0 {
+		return models.Coordinates{}, models.WeatherLocation{}, fmt.Errorf("erro ao geocodificar %s: %w", location.City, ErrWeatherLocationNotFound)
+	}
+
+	best := response.Results[0]
+	for _, result := range response.Results {
+		if sameState(result.Admin1, location.Region) {
+			best = result
+			break
+		}
+	}
+
+	return models.Coordinates{Latitude: best.Latitude, Longitude: best.Longitude},
+		models.WeatherLocation{Name: best.Name, Region: best.Admin1, Country: best.Country},
+		nil
+}
+
+// getJSON consulta a Open-Meteo e decodifica a resposta, interpretando o corpo de erro da API
+func (p *openMeteoProvider) getJSON(ctx context.Context, apiURL string, v interface{}) error {
+	status, body, err := fetch(ctx, p.client, apiURL, "clima")
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		var apiError models.OpenMeteoError
+		if json.Unmarshal(body, &apiError) == nil && apiError.Reason != "" {
+			return fmt.Errorf("erro ao consultar clima: status %d: %s: %w", status, apiError.Reason, classifyStatus(status))
+		}
+		return fmt.Errorf("erro ao consultar clima: status %d: %w", status, classifyStatus(status))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta: %w: %w", ErrBadUpstreamPayload, err)
+	}
+
+	return nil
+}
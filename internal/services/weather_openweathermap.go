@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+)
+
+// openWeatherMapProvider consulta a OpenWeatherMap (openweathermap.org)
+type openWeatherMapProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func (p *openWeatherMapProvider) Name() string { return config.WeatherProviderOpenWeatherMap }
+
+// Current consulta o clima atual no endpoint weather, em unidades métricas
+func (p *openWeatherMapProvider) Current(ctx context.Context, location LocationQuery) (*models.WeatherResult, error) {
+	var response models.OpenWeatherMapResponse
+	if err := p.getJSON(ctx, "/weather", p.params(location), &response); err != nil {
+		return nil, err
+	}
+
+	// A OpenWeatherMap informa o vento em m/s e não oferece índice UV no endpoint weather
+	conditions := models.Conditions{
+		Humidity:   response.Main.Humidity,
+		WindKph:    metersPerSecondToKph(response.Wind.Speed),
+		WindDegree: response.Wind.Deg,
+		WindDir:    compassDirection(response.Wind.Deg),
+		PressureMb: response.Main.Pressure,
+		FeelsLikeC: response.Main.FeelsLike,
+	}
+	if len(response.Weather) > 0 {
+		conditions.Text = response.Weather[0].Description
+		conditions.Code = &response.Weather[0].ID
+	}
+	if response.Dt > 0 {
+		conditions.LastUpdated = time.Unix(response.Dt, 0).In(time.FixedZone("", response.Timezone)).Format("2006-01-02 15:04")
+		conditions.ObservedAt = time.Unix(response.Dt, 0).UTC()
+	}
+
+	// A OpenWeatherMap não informa o estado do local resolvido
+	return &models.WeatherResult{
+		TempC:      response.Main.Temp,
+		Conditions: conditions,
+		Location: models.WeatherLocation{
+			Name:    response.Name,
+			Country: response.Sys.Country,
+		},
+	}, nil
+}
+
+// Previsões de 3 horas em um dia e limite de previsões por consulta da OpenWeatherMap
+const (
+	openWeatherMapForecastSteps    = 8
+	openWeatherMapForecastMaxSteps = 40
+)
+
+// Forecast consulta a previsão em intervalos de 3 horas do endpoint forecast e agrega os valores
+// por dia no fuso horário do local. O plano gratuito cobre 5 dias; o primeiro e o último dia
+// costumam ser parciais.
+func (p *openWeatherMapProvider) Forecast(ctx context.Context, location LocationQuery, options ForecastOptions) (*models.ForecastResult, error) {
+	params := p.params(location)
+	params.Set("cnt", strconv.Itoa(min(options.Days*openWeatherMapForecastSteps, openWeatherMapForecastMaxSteps)))
+
+	var response models.OpenWeatherMapForecastResponse
+	if err := p.getJSON(ctx, "/forecast", params, &response); err != nil {
+		return nil, err
+	}
+
+	zone := time.FixedZone("", response.City.Timezone)
+	result := &models.ForecastResult{
+		Location: models.WeatherLocation{
+			Name:    response.City.Name,
+			Country: response.City.Country,
+		},
+	}
+
+	var sum float64
+	var count int
+	for _, entry := range response.List {
+		moment := time.Unix(entry.Dt, 0).In(zone)
+		date := moment.Format("2006-01-02")
+
+		last := len(result.Days) - 1
+		if last < 0 || result.Days[last].Date != date {
+			if len(result.Days) == options.Days {
+				break
+			}
+			result.Days = append(result.Days, models.ForecastDay{Date: date, MinC: entry.Main.Temp, MaxC: entry.Main.Temp})
+			last++
+			sum, count = 0, 0
+		}
+
+		day := &result.Days[last]
+		day.MinC = math.Min(day.MinC, entry.Main.Temp)
+		day.MaxC = math.Max(day.MaxC, entry.Main.Temp)
+		sum += entry.Main.Temp
+		count++
+		day.AvgC = sum / float64(count)
+		if options.Hourly {
+			day.Hours = append(day.Hours, models.ForecastHour{Time: moment.Format("2006-01-02 15:04"), TempC: entry.Main.Temp})
+		}
+	}
+
+	return result, nil
+}
+
+// params monta os parâmetros comuns às consultas, em unidades métricas
+func (p *openWeatherMapProvider) params(location LocationQuery) url.Values {
+	params := url.Values{}
+	params.Set("appid", p.apiKey)
+	params.Set("units", "metric")
+	if location.Coordinates != nil {
+		params.Set("lat", fmt.Sprintf("%.4f", location.Coordinates.Latitude))
+		params.Set("lon", fmt.Sprintf("%.4f", location.Coordinates.Longitude))
+	} else {
+		// A OpenWeatherMap só aceita o estado na busca textual para cidades dos EUA
+		params.Set("q", location.City+",BR")
+	}
+	return params
+}
+
+// getJSON consulta um endpoint da OpenWeatherMap e decodifica a resposta
+func (p *openWeatherMapProvider) getJSON(ctx context.Context, endpoint string, params url.Values, v interface{}) error {
+	status, body, err := fetch(ctx, p.client, p.baseURL+endpoint+"?"+params.Encode(), "clima")
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("erro ao consultar clima: status %d: %w", status, classifyOpenWeatherMapError(status))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta: %w: %w", ErrBadUpstreamPayload, err)
+	}
+
+	return nil
+}
+
+// classifyOpenWeatherMapError interpreta os status de erro da OpenWeatherMap
+func classifyOpenWeatherMapError(status int) error {
+	if status == http.StatusNotFound {
+		return ErrWeatherLocationNotFound
+	}
+	return classifyStatus(status)
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenMeteoProvider_Current(t *testing.T) {
+	var forecastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			assert.Equal(t, "BR", r.URL.Query().Get("countryCode"))
+			w.Write([]byte(`{"results": [
+				{"name": "Bom Jesus", "latitude": -28.67, "longitude": -50.43, "country": "Brasil", "admin1": "Rio Grande do Sul"},
+				{"name": "Bom Jesus", "latitude": -9.07, "longitude": -44.36, "country": "Brasil", "admin1": "Piauí"}
+			]}`))
+		case "/forecast":
+			forecastQuery = r.URL.RawQuery
+			w.Write([]byte(`{"latitude": -9.07, "longitude": -44.36, "current": {"time": "2025-01-10T15:00", "temperature_2m": 33.2}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := &openMeteoProvider{baseURL: server.URL, geocodingURL: server.URL, client: &http.Client{}}
+
+	t.Run("por nome usa o resultado da geocodificação no mesmo estado", func(t *testing.T) {
+		result, err := provider.Current(context.Background(), LocationQuery{City: "Bom Jesus", Region: "PI"})
+		assert.NoError(t, err)
+		assert.Equal(t, 33.2, result.TempC)
+		assert.Equal(t, models.WeatherLocation{Name: "Bom Jesus", Region: "Piauí", Country: "Brasil"}, result.Location)
+		assert.Contains(t, forecastQuery, "latitude=-9.0700&longitude=-44.3600")
+	})
+
+	t.Run("por coordenadas dispensa a geocodificação", func(t *testing.T) {
+		result, err := provider.Current(context.Background(), LocationQuery{
+			City:        "São Paulo",
+			Region:      "SP",
+			Coordinates: &models.Coordinates{Latitude: -23.5505, Longitude: -46.6333},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 33.2, result.TempC)
+		assert.Empty(t, result.Location.Name)
+		assert.Contains(t, forecastQuery, "latitude=-23.5505&longitude=-46.6333")
+	})
+}
+
+func TestOpenMeteoProvider_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			w.Write([]byte(`{"generationtime_ms": 0.5}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": true, "reason": "Latitude must be in range of -90 to 90°."}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := &openMeteoProvider{baseURL: server.URL, geocodingURL: server.URL, client: &http.Client{}}
+
+	_, err := provider.Current(context.Background(), LocationQuery{City: "Inexistente", Region: "XX"})
+	assert.ErrorIs(t, err, ErrWeatherLocationNotFound)
+
+	_, err = provider.Current(context.Background(), LocationQuery{Coordinates: &models.Coordinates{Latitude: 200}})
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	assert.ErrorContains(t, err, "Latitude must be in range")
+}
+
+func TestOpenWeatherMapProvider_Current(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "test_api_key", query.Get("appid"))
+		assert.Equal(t, "metric", query.Get("units"))
+
+		switch {
+		case query.Get("q") == "São Paulo,BR" || query.Get("lat") == "-23.5505":
+			w.Write([]byte(`{"name": "São Paulo", "main": {"temp": 24.1}, "sys": {"country": "BR"}, "cod": 200}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"cod": "404", "message": "city not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := &openWeatherMapProvider{baseURL: server.URL, apiKey: "test_api_key", client: &http.Client{}}
+
+	result, err := provider.Current(context.Background(), LocationQuery{City: "São Paulo", Region: "SP"})
+	assert.NoError(t, err)
+	assert.Equal(t, 24.1, result.TempC)
+	assert.Equal(t, models.WeatherLocation{Name: "São Paulo", Country: "BR"}, result.Location)
+
+	result, err = provider.Current(context.Background(), LocationQuery{Coordinates: &models.Coordinates{Latitude: -23.5505, Longitude: -46.6333}})
+	assert.NoError(t, err)
+	assert.Equal(t, 24.1, result.TempC)
+
+	_, err = provider.Current(context.Background(), LocationQuery{City: "Inexistente", Region: "XX"})
+	assert.ErrorIs(t, err, ErrWeatherLocationNotFound)
+}
+
+func TestINMETProvider_Current(t *testing.T) {
+	var stationRequests, dataPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/estacoes/T":
+			stationRequests += "x"
+			w.Write([]byte(`[
+				{"CD_ESTACAO": "A701", "DC_NOME": "SAO PAULO - MIRANTE", "SG_ESTADO": "SP", "VL_LATITUDE": "-23.49638888", "VL_LONGITUDE": "-46.61999999", "CD_SITUACAO": "Operante"},
+				{"CD_ESTACAO": "A652", "DC_NOME": "RIO DE JANEIRO - FORTE DE COPACABANA", "SG_ESTADO": "RJ", "VL_LATITUDE": "-22.98833333", "VL_LONGITUDE": "-43.19055555", "CD_SITUACAO": "Operante"},
+				{"CD_ESTACAO": "A999", "DC_NOME": "SAO PAULO - DESATIVADA", "SG_ESTADO": "SP", "VL_LATITUDE": "-23.55", "VL_LONGITUDE": "-46.63", "CD_SITUACAO": "Pane"}
+			]`))
+		default:
+			dataPath = r.URL.Path
+			w.Write([]byte(`[
+				{"DT_MEDICAO": "2025-01-10", "HR_MEDICAO": "1400", "TEM_INS": "27.3", "DC_NOME": "SAO PAULO - MIRANTE", "UF": "SP"},
+				{"DT_MEDICAO": "2025-01-10", "HR_MEDICAO": "1500", "TEM_INS": "28.1", "DC_NOME": "SAO PAULO - MIRANTE", "UF": "SP"},
+				{"DT_MEDICAO": "2025-01-10", "HR_MEDICAO": "1600", "TEM_INS": null, "DC_NOME": "SAO PAULO - MIRANTE", "UF": "SP"}
+			]`))
+		}
+	}))
+	defer server.Close()
+
+	provider := newINMETProvider(server.URL, &http.Client{})
+	provider.now = func() time.Time { return time.Date(2025, 1, 10, 16, 30, 0, 0, time.UTC) }
+
+	t.Run("por coordenadas usa a estação operante mais próxima", func(t *testing.T) {
+		result, err := provider.Current(context.Background(), LocationQuery{
+			Coordinates: &models.Coordinates{Latitude: -23.5505, Longitude: -46.6333},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 28.1, result.TempC)
+		assert.Equal(t, models.WeatherLocation{Name: "SAO PAULO", Region: "SP", Country: "Brazil"}, result.Location)
+		assert.Equal(t, "/estacao/2025-01-09/2025-01-10/A701", dataPath)
+	})
+
+	t.Run("por nome usa a estação da cidade na mesma UF", func(t *testing.T) {
+		_, err := provider.Current(context.Background(), LocationQuery{City: "Rio de Janeiro", Region: "Rio de Janeiro"})
+		assert.NoError(t, err)
+		assert.Equal(t, "/estacao/2025-01-09/2025-01-10/A652", dataPath)
+	})
+
+	t.Run("cidade sem estação", func(t *testing.T) {
+		_, err := provider.Current(context.Background(), LocationQuery{City: "Campinas", Region: "SP"})
+		assert.ErrorIs(t, err, ErrWeatherLocationNotFound)
+	})
+
+	t.Run("coordenadas longe de qualquer estação", func(t *testing.T) {
+		// Manaus fica a mais de 2.500 km das estações da lista
+		_, err := provider.Current(context.Background(), LocationQuery{
+			Coordinates: &models.Coordinates{Latitude: -3.119, Longitude: -60.0217},
+		})
+		assert.ErrorIs(t, err, ErrWeatherLocationNotFound)
+	})
+
+	// A lista de estações é carregada uma única vez
+	assert.Equal(t, "x", stationRequests)
+}
+
+func TestINMETProvider_ConcurrentStationsLoad(t *testing.T) {
+	var stationRequests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stationRequests.Add(1)
+		<-release
+		w.Write([]byte(`[{"CD_ESTACAO": "A701", "DC_NOME": "SAO PAULO - MIRANTE", "SG_ESTADO": "SP", "VL_LATITUDE": "-23.49638888", "VL_LONGITUDE": "-46.61999999", "CD_SITUACAO": "Operante"}]`))
+	}))
+	defer server.Close()
+
+	provider := newINMETProvider(server.URL, &http.Client{})
+
+	// Com a lista ainda vazia, as requisições concorrentes aguardam uma única carga
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = provider.loadStations(context.Background())
+		}()
+	}
+
+	// Uma requisição sem prazo para esperar desiste sem aguardar a carga
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	for stationRequests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	_, err := provider.loadStations(ctx)
+	assert.ErrorIs(t, err, ErrUpstreamTimeout)
+
+	close(release)
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), stationRequests.Load())
+}
+
+func TestNewWeatherService_Providers(t *testing.T) {
+	tests := []struct {
+		provider string
+		expected string
+	}{
+		{provider: config.WeatherProviderWeatherAPI, expected: config.WeatherProviderWeatherAPI},
+		{provider: config.WeatherProviderOpenMeteo, expected: config.WeatherProviderOpenMeteo},
+		{provider: config.WeatherProviderOpenWeatherMap, expected: config.WeatherProviderOpenWeatherMap},
+		{provider: config.WeatherProviderINMET, expected: config.WeatherProviderINMET},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			service, err := NewWeatherService(&config.Config{Weather: config.WeatherConfig{Provider: tt.provider}})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, service.(*weatherService).provider.Name())
+		})
+	}
+
+	_, err := NewWeatherService(&config.Config{Weather: config.WeatherConfig{Provider: "climatempo"}})
+	assert.ErrorContains(t, err, "provedor de clima desconhecido")
+}
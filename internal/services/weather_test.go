@@ -1,11 +1,14 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"cep-temperatura/internal/config"
 	"cep-temperatura/internal/models"
 
 	"github.com/stretchr/testify/assert"
@@ -25,9 +28,7 @@ func TestWeatherService_GetTemperature(t *testing.T) {
 				Region:  "São Paulo",
 				Country: "Brazil",
 			},
-			Current: struct {
-				TempC float64 `json:"temp_c"`
-			}{
+			Current: models.WeatherAPICurrent{
 				TempC: 28.5,
 			},
 		}
@@ -40,18 +41,17 @@ func TestWeatherService_GetTemperature(t *testing.T) {
 
 	// Criar serviço com URL do mock
 	weatherService := &weatherService{
-		baseURL: server.URL,
-		apiKey:  "test_api_key",
-		client:  &http.Client{},
+		provider: newTestWeatherAPIProvider(server.URL),
 	}
 
 	t.Run("busca temperatura com sucesso", func(t *testing.T) {
-		temp, err := weatherService.GetTemperature("São Paulo", "SP")
+		result, err := weatherService.GetTemperature(context.Background(), models.WeatherQuery{City: "São Paulo", State: "SP"})
 		if err != nil {
 			t.Logf("Erro: %v", err)
 		}
 		assert.NoError(t, err)
-		assert.Equal(t, 28.5, temp)
+		assert.Equal(t, 28.5, result.TempC)
+		assert.Equal(t, models.LocationMatched, result.Match.Status)
 	})
 }
 
@@ -64,14 +64,200 @@ func TestWeatherService_GetTemperature_Error(t *testing.T) {
 	defer server.Close()
 
 	weatherService := &weatherService{
-		baseURL: server.URL,
-		apiKey:  "test_api_key",
-		client:  &http.Client{},
+		provider: newTestWeatherAPIProvider(server.URL),
 	}
 
 	t.Run("erro ao buscar temperatura", func(t *testing.T) {
-		_, err := weatherService.GetTemperature("CidadeInexistente", "XX")
+		_, err := weatherService.GetTemperature(context.Background(), models.WeatherQuery{City: "CidadeInexistente", State: "XX"})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "erro ao consultar clima")
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
 	})
 }
+
+func TestWeatherService_GetTemperature_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{
+			name:     "localização não encontrada",
+			status:   http.StatusBadRequest,
+			body:     `{"error": {"code": 1006, "message": "No matching location found."}}`,
+			expected: ErrWeatherLocationNotFound,
+		},
+		{
+			name:     "cota mensal excedida",
+			status:   http.StatusForbidden,
+			body:     `{"error": {"code": 2007, "message": "API key has exceeded calls per month quota."}}`,
+			expected: ErrQuotaExceeded,
+		},
+		{
+			name:     "erro interno da API",
+			status:   http.StatusInternalServerError,
+			body:     `internal error`,
+			expected: ErrUpstreamUnavailable,
+		},
+		{
+			name:     "payload inválido",
+			status:   http.StatusOK,
+			body:     `{"current": "not an object"}`,
+			expected: ErrBadUpstreamPayload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			weatherService := &weatherService{
+				provider: newTestWeatherAPIProvider(server.URL),
+			}
+
+			_, err := weatherService.GetTemperature(context.Background(), models.WeatherQuery{City: "São Paulo", State: "SP"})
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestWeatherService_GetTemperature_Deadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	weatherService := &weatherService{
+		provider: newTestWeatherAPIProvider(server.URL),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := weatherService.GetTemperature(ctx, models.WeatherQuery{City: "São Paulo", State: "SP"})
+	assert.ErrorIs(t, err, ErrUpstreamTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWeatherService_GetTemperature_QueryStrategy(t *testing.T) {
+	var receivedQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.Query().Get("q")
+		w.Write([]byte(`{"current": {"temp_c": 21.4}}`))
+	}))
+	defer server.Close()
+
+	weatherService := &weatherService{
+		provider: newTestWeatherAPIProvider(server.URL),
+		centroids: MunicipalityCentroids{
+			"3550308": {Latitude: -23.5505, Longitude: -46.6333},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		query    models.WeatherQuery
+		expected string
+	}{
+		{
+			name:     "código IBGE conhecido usa coordenadas",
+			query:    models.WeatherQuery{City: "São Paulo", State: "SP", IBGE: "3550308"},
+			expected: "-23.5505,-46.6333",
+		},
+		{
+			name:     "código IBGE desconhecido usa o nome da cidade",
+			query:    models.WeatherQuery{City: "Bom Jesus", State: "PI", IBGE: "2201903"},
+			expected: "Bom Jesus, PI, Brazil",
+		},
+		{
+			name:     "sem código IBGE usa o nome da cidade",
+			query:    models.WeatherQuery{City: "São Paulo", State: "SP"},
+			expected: "São Paulo, SP, Brazil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := weatherService.GetTemperature(context.Background(), tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, 21.4, result.TempC)
+			assert.Equal(t, tt.expected, receivedQuery)
+		})
+	}
+}
+
+func TestWeatherService_GetTemperature_MismatchPolicy(t *testing.T) {
+	// A busca textual com a UF resolve para o município homônimo do RS;
+	// a busca com o nome do estado resolve corretamente
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "Bom Jesus, PI, Brazil":
+			w.Write([]byte(`{"location": {"name": "Bom Jesus", "region": "Rio Grande do Sul", "country": "Brazil"}, "current": {"temp_c": 12.0}}`))
+		case "Bom Jesus, Piauí, Brazil":
+			w.Write([]byte(`{"location": {"name": "Bom Jesus", "region": "Piaui", "country": "Brazil"}, "current": {"temp_c": 34.0}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 1006, "message": "No matching location found."}}`))
+		}
+	}))
+	defer server.Close()
+
+	query := models.WeatherQuery{City: "Bom Jesus", State: "PI"}
+
+	newService := func(policy string) *weatherService {
+		return &weatherService{
+			provider:       newTestWeatherAPIProvider(server.URL),
+			mismatchPolicy: policy,
+		}
+	}
+
+	t.Run("ignore não confere o local", func(t *testing.T) {
+		result, err := newService(config.MismatchPolicyIgnore).GetTemperature(context.Background(), query)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.0, result.TempC)
+		assert.Equal(t, models.LocationUnchecked, result.Match.Status)
+	})
+
+	t.Run("flag sinaliza a divergência", func(t *testing.T) {
+		result, err := newService(config.MismatchPolicyFlag).GetTemperature(context.Background(), query)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.0, result.TempC)
+		assert.Equal(t, models.LocationMismatch, result.Match.Status)
+		assert.Equal(t, "Rio Grande do Sul", result.Location.Region)
+	})
+
+	t.Run("retry tenta a estratégia alternativa", func(t *testing.T) {
+		result, err := newService(config.MismatchPolicyRetry).GetTemperature(context.Background(), query)
+		assert.NoError(t, err)
+		assert.Equal(t, 34.0, result.TempC)
+		assert.Equal(t, models.LocationMatch{Status: models.LocationMatched, Strategy: strategyCityState, Attempts: 2}, result.Match)
+	})
+
+	t.Run("retry propaga o erro quando nenhuma estratégia resolve o local", func(t *testing.T) {
+		result, err := newService(config.MismatchPolicyRetry).GetTemperature(context.Background(), models.WeatherQuery{City: "Bom Jesus", State: "XX"})
+		assert.ErrorIs(t, err, ErrWeatherLocationNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("reject rejeita o resultado", func(t *testing.T) {
+		_, err := newService(config.MismatchPolicyReject).GetTemperature(context.Background(), query)
+		assert.ErrorIs(t, err, ErrWeatherLocationMismatch)
+	})
+}
+
+func newTestWeatherAPIProvider(baseURL string) *weatherAPIProvider {
+	return &weatherAPIProvider{
+		baseURL: baseURL,
+		apiKey:  "test_api_key",
+		client:  &http.Client{},
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cep-temperatura/internal/config"
+	"cep-temperatura/internal/models"
+)
+
+// weatherAPIProvider consulta a WeatherAPI (weatherapi.com)
+type weatherAPIProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func (p *weatherAPIProvider) Name() string { return config.WeatherProviderWeatherAPI }
+
+// Current consulta o clima atual no endpoint current.json
+func (p *weatherAPIProvider) Current(ctx context.Context, location LocationQuery) (*models.WeatherResult, error) {
+	apiURL := fmt.Sprintf("%s/current.json?key=%s&q=%s", p.baseURL, p.apiKey, url.QueryEscape(weatherAPIQuery(location)))
+
+	var weatherResponse models.WeatherResponse
+	if err := p.getJSON(ctx, apiURL, &weatherResponse); err != nil {
+		return nil, err
+	}
+
+	current := weatherResponse.Current
+	conditions := models.Conditions{
+		Text:        current.Condition.Text,
+		Code:        &current.Condition.Code,
+		Humidity:    current.Humidity,
+		WindKph:     current.WindKph,
+		WindDegree:  current.WindDegree,
+		WindDir:     current.WindDir,
+		PressureMb:  current.PressureMb,
+		FeelsLikeC:  current.FeelsLikeC,
+		UV:          current.UV,
+		LastUpdated: current.LastUpdated,
+	}
+	if current.LastUpdatedEpoch > 0 {
+		conditions.ObservedAt = time.Unix(current.LastUpdatedEpoch, 0).UTC()
+	}
+
+	return &models.WeatherResult{
+		TempC:      current.TempC,
+		Conditions: conditions,
+		Location: models.WeatherLocation{
+			Name:    weatherResponse.Location.Name,
+			Region:  weatherResponse.Location.Region,
+			Country: weatherResponse.Location.Country,
+		},
+	}, nil
+}
+
+// Forecast consulta a previsão no endpoint forecast.json. O número de dias disponíveis
+// depende do plano da chave; a WeatherAPI devolve menos dias quando o pedido excede o limite.
+func (p *weatherAPIProvider) Forecast(ctx context.Context, location LocationQuery, options ForecastOptions) (*models.ForecastResult, error) {
+	apiURL := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=%d&aqi=no&alerts=no",
+		p.baseURL, p.apiKey, url.QueryEscape(weatherAPIQuery(location)), options.Days)
+
+	var response models.WeatherAPIForecastResponse
+	if err := p.getJSON(ctx, apiURL, &response); err != nil {
+		return nil, err
+	}
+
+	result := &models.ForecastResult{
+		Days: make([]models.ForecastDay, 0, len(response.Forecast.ForecastDay)),
+		Location: models.WeatherLocation{
+			Name:    response.Location.Name,
+			Region:  response.Location.Region,
+			Country: response.Location.Country,
+		},
+	}
+	for _, forecastDay := range response.Forecast.ForecastDay {
+		day := models.ForecastDay{
+			Date: forecastDay.Date,
+			MinC: forecastDay.Day.MinTempC,
+			MaxC: forecastDay.Day.MaxTempC,
+			AvgC: forecastDay.Day.AvgTempC,
+		}
+		if options.Hourly {
+			for _, hour := range forecastDay.Hour {
+				day.Hours = append(day.Hours, models.ForecastHour{Time: hour.Time, TempC: hour.TempC})
+			}
+		}
+		result.Days = append(result.Days, day)
+	}
+
+	return result, nil
+}
+
+// History consulta as temperaturas diárias no endpoint history.json. Intervalos com mais de um
+// dia dependem de plano pago, e o plano gratuito cobre apenas os últimos 7 dias.
+func (p *weatherAPIProvider) History(ctx context.Context, location LocationQuery, options HistoryOptions) (*models.HistoryResult, error) {
+	apiURL := fmt.Sprintf("%s/history.json?key=%s&q=%s&dt=%s&end_dt=%s", p.baseURL, p.apiKey,
+		url.QueryEscape(weatherAPIQuery(location)), options.From.Format(time.DateOnly), options.To.Format(time.DateOnly))
+
+	// O histórico vem no mesmo formato da previsão
+	var response models.WeatherAPIForecastResponse
+	if err := p.getJSON(ctx, apiURL, &response); err != nil {
+		return nil, err
+	}
+
+	result := &models.HistoryResult{
+		Days: make([]models.HistoryDay, 0, len(response.Forecast.ForecastDay)),
+		Location: models.WeatherLocation{
+			Name:    response.Location.Name,
+			Region:  response.Location.Region,
+			Country: response.Location.Country,
+		},
+	}
+	for _, day := range response.Forecast.ForecastDay {
+		result.Days = append(result.Days, models.HistoryDay{
+			Date: day.Date,
+			MinC: day.Day.MinTempC,
+			MaxC: day.Day.MaxTempC,
+			AvgC: day.Day.AvgTempC,
+		})
+	}
+
+	return result, nil
+}
+
+// getJSON consulta a WeatherAPI e decodifica a resposta, interpretando o corpo de erro da API
+func (p *weatherAPIProvider) getJSON(ctx context.Context, apiURL string, v interface{}) error {
+	status, body, err := fetch(ctx, p.client, apiURL, "clima")
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("erro ao consultar clima: status %d: %w", status, classifyWeatherAPIError(status, body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta: %w: %w", ErrBadUpstreamPayload, err)
+	}
+
+	return nil
+}
+
+// weatherAPIQuery monta o parâmetro q da WeatherAPI, que aceita coordenadas ou texto livre
+func weatherAPIQuery(location LocationQuery) string {
+	if location.Coordinates != nil {
+		return fmt.Sprintf("%.4f,%.4f", location.Coordinates.Latitude, location.Coordinates.Longitude)
+	}
+	return fmt.Sprintf("%s, %s, Brazil", location.City, location.Region)
+}
+
+// Códigos de erro documentados da WeatherAPI
+const (
+	weatherAPICodeNoLocation    = 1006
+	weatherAPICodeQuotaExceeded = 2007
+)
+
+// classifyWeatherAPIError interpreta o corpo de erro da WeatherAPI
+func classifyWeatherAPIError(status int, body []byte) error {
+	var apiError models.WeatherAPIError
+	if err := json.Unmarshal(body, &apiError); err == nil {
+		switch apiError.Error.Code {
+		case weatherAPICodeNoLocation:
+			return ErrWeatherLocationNotFound
+		case weatherAPICodeQuotaExceeded:
+			return ErrQuotaExceeded
+		}
+	}
+
+	return classifyStatus(status)
+}
@@ -0,0 +1,263 @@
+package wshub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gorilla/websocket"
+)
+
+// Ações aceitas nas mensagens do cliente
+const (
+	actionSubscribe   = "subscribe"
+	actionUnsubscribe = "unsubscribe"
+)
+
+// Tipos das mensagens do servidor
+const (
+	typeSubscribed   = "subscribed"
+	typeUnsubscribed = "unsubscribed"
+	typeTemperature  = "temperature"
+	typeError        = "error"
+)
+
+// clientMessage assina ou cancela um CEP (cep) ou vários (ceps)
+type clientMessage struct {
+	Action string   `json:"action"`
+	CEP    string   `json:"cep"`
+	CEPs   []string `json:"ceps"`
+}
+
+// serverMessage confirma uma assinatura, traz a temperatura de um CEP ou descreve um erro
+type serverMessage struct {
+	Type string `json:"type"`
+	CEP  string `json:"cep,omitempty"`
+	*models.TemperatureResponse
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// connection atende um cliente. O laço de leitura cria e cancela as assinaturas; a consulta
+// do CEP de cada assinatura corre fora dele, para que um provedor lento não atrase as demais
+// mensagens. As mensagens ao cliente passam pelo canal send e são escritas pelo laço de escrita.
+type connection struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	send   chan serverMessage
+
+	// subscriptions guarda cada assinatura pelo CEP normalizado, inclusive as que ainda
+	// aguardam a consulta do CEP
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+}
+
+// subscription é a assinatura de um CEP. Enquanto o CEP é consultado, unsubscribe é nil e
+// cancel interrompe a consulta.
+type subscription struct {
+	cancel      context.CancelFunc
+	unsubscribe func()
+}
+
+// stop interrompe a consulta do CEP ou cancela a assinatura no WeatherWatcher
+func (s *subscription) stop() {
+	s.cancel()
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+}
+
+// readLoop processa as mensagens do cliente até a conexão fechar
+func (c *connection) readLoop() {
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var message clientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.sendError("", errInvalidMessage)
+			continue
+		}
+		c.handle(message)
+	}
+}
+
+// handle aplica a ação da mensagem a cada CEP informado
+func (c *connection) handle(message clientMessage) {
+	ceps := message.CEPs
+	if message.CEP != "" {
+		ceps = append([]string{message.CEP}, ceps...)
+	}
+	if len(ceps) == 0 {
+		c.sendError("", fmt.Errorf("%w: cep is required", errInvalidMessage))
+		return
+	}
+
+	switch message.Action {
+	case actionSubscribe:
+		for _, cep := range ceps {
+			c.subscribe(cep)
+		}
+	case actionUnsubscribe:
+		for _, cep := range ceps {
+			c.unsubscribe(cep)
+		}
+	default:
+		c.sendError("", fmt.Errorf("%w: unknown action %q", errInvalidMessage, message.Action))
+	}
+}
+
+// subscribe passa a acompanhar o CEP. Assinar de novo um CEP acompanhado apenas confirma.
+// A assinatura conta para o limite desde já; o CEP é consultado em segundo plano.
+func (c *connection) subscribe(cep string) {
+	if !c.hub.cepService.ValidateCEP(cep) {
+		c.sendError(cep, services.ErrInvalidCEP)
+		return
+	}
+	key := services.NormalizeCEP(cep)
+
+	c.mu.Lock()
+	if _, ok := c.subscriptions[key]; ok {
+		c.mu.Unlock()
+		c.deliver(serverMessage{Type: typeSubscribed, CEP: cep})
+		return
+	}
+	if len(c.subscriptions) >= c.hub.maxSubscriptions {
+		c.mu.Unlock()
+		c.sendError(cep, errSubscriptionLimit)
+		return
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	sub := &subscription{cancel: cancel}
+	c.subscriptions[key] = sub
+	c.mu.Unlock()
+
+	go c.resolve(ctx, cep, key, sub)
+}
+
+// resolve consulta o CEP da assinatura e a registra no WeatherWatcher. Uma assinatura
+// cancelada durante a consulta termina sem mensagens.
+func (c *connection) resolve(ctx context.Context, cep, key string, sub *subscription) {
+	lookupCtx, cancel := c.hub.lookupContext(ctx)
+	location, err := c.hub.cepService.GetLocation(lookupCtx, cep)
+	cancel()
+
+	c.mu.Lock()
+	if c.subscriptions[key] != sub {
+		c.mu.Unlock()
+		return
+	}
+	if err != nil {
+		delete(c.subscriptions, key)
+		c.mu.Unlock()
+		c.sendError(cep, err)
+		return
+	}
+	updates, unsubscribe := c.hub.watcher.Subscribe(location)
+	sub.unsubscribe = unsubscribe
+	c.mu.Unlock()
+
+	c.deliver(serverMessage{Type: typeSubscribed, CEP: cep})
+	go c.forward(cep, updates)
+}
+
+// unsubscribe deixa de acompanhar o CEP, interrompendo a consulta do CEP se ainda houver
+func (c *connection) unsubscribe(cep string) {
+	key := services.NormalizeCEP(cep)
+	c.mu.Lock()
+	if sub, ok := c.subscriptions[key]; ok {
+		sub.stop()
+		delete(c.subscriptions, key)
+	}
+	c.mu.Unlock()
+	c.deliver(serverMessage{Type: typeUnsubscribed, CEP: cep})
+}
+
+// forward repassa ao cliente a primeira temperatura do CEP e cada mudança, até a assinatura
+// ser cancelada
+func (c *connection) forward(cep string, updates <-chan services.WeatherUpdate) {
+	var last *models.TemperatureResponse
+	for update := range updates {
+		if update.Err != nil {
+			c.sendError(cep, update.Err)
+			continue
+		}
+		temperature := c.hub.convert(update.Weather.TempC)
+		if last != nil && *last == temperature {
+			continue
+		}
+		last = &temperature
+		c.deliver(serverMessage{Type: typeTemperature, CEP: cep, TemperatureResponse: &temperature})
+	}
+}
+
+// sendError envia o erro com o código estável e a mesma mensagem da API HTTP
+func (c *connection) sendError(cep string, err error) {
+	code, message := errorCode(err)
+	c.deliver(serverMessage{Type: typeError, CEP: cep, Code: code, Message: message})
+}
+
+// deliver enfileira a mensagem para o laço de escrita. Um cliente que não acompanha o ritmo
+// das mensagens e enche a fila é desconectado.
+func (c *connection) deliver(message serverMessage) {
+	select {
+	case c.send <- message:
+	case <-c.ctx.Done():
+	default:
+		c.cancel()
+	}
+}
+
+// writeLoop escreve as mensagens e os pings até a conexão encerrar
+func (c *connection) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeWait))
+			return
+		case message := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(message); err != nil {
+				c.cancel()
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.cancel()
+				return
+			}
+		}
+	}
+}
+
+// close cancela as assinaturas da conexão, liberando as consultas que ficaram sem assinantes
+func (c *connection) close() {
+	c.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, sub := range c.subscriptions {
+		sub.stop()
+		delete(c.subscriptions, key)
+	}
+}
@@ -0,0 +1,26 @@
+package wshub
+
+import (
+	"errors"
+
+	"cep-temperatura/internal/services"
+)
+
+// Erros do protocolo de mensagens
+var (
+	errInvalidMessage    = errors.New("invalid message")
+	errSubscriptionLimit = errors.New("subscription limit reached")
+)
+
+// errorCode mapeia os erros para os códigos estáveis de docs/errors.md pelo catálogo dos
+// serviços, com as mesmas mensagens da API HTTP
+func errorCode(err error) (string, string) {
+	switch {
+	case errors.Is(err, errInvalidMessage):
+		return "invalid_message", err.Error()
+	case errors.Is(err, errSubscriptionLimit):
+		return "subscription_limit", "subscription limit reached"
+	}
+	info := services.DescribeError(err)
+	return info.Code, info.Message
+}
@@ -0,0 +1,118 @@
+// Package wshub acompanha a temperatura de vários CEPs por uma única conexão WebSocket, com as
+// consultas de clima compartilhadas entre todas as conexões.
+package wshub
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gorilla/websocket"
+)
+
+// Limites padrão de cada conexão
+const (
+	defaultMaxSubscriptions = 50
+	maxMessageSize          = 4096
+	sendBufferSize          = 64
+	writeWait               = 10 * time.Second
+	pongWait                = 60 * time.Second
+	pingPeriod              = pongWait * 9 / 10
+)
+
+// Hub atende as conexões de /ws. Cada conexão assina e cancela CEPs por mensagens e recebe a
+// temperatura de todos eles; CEPs do mesmo município, em qualquer conexão, compartilham a
+// consulta de clima do WeatherWatcher.
+type Hub struct {
+	cepService         services.CEPService
+	temperatureService services.TemperatureService
+	watcher            *services.WeatherWatcher
+
+	requestTimeout   time.Duration
+	maxSubscriptions int
+	upgrader         websocket.Upgrader
+}
+
+// Option personaliza o Hub
+type Option func(*Hub)
+
+// WithRequestTimeout limita a consulta de CEP de cada assinatura
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(h *Hub) {
+		h.requestTimeout = timeout
+	}
+}
+
+// WithMaxSubscriptions limita quantos CEPs uma conexão acompanha ao mesmo tempo
+func WithMaxSubscriptions(maxSubscriptions int) Option {
+	return func(h *Hub) {
+		h.maxSubscriptions = maxSubscriptions
+	}
+}
+
+// WithWeatherWatcher define o agendador compartilhado das consultas de clima
+func WithWeatherWatcher(watcher *services.WeatherWatcher) Option {
+	return func(h *Hub) {
+		h.watcher = watcher
+	}
+}
+
+// NewHub cria o hub WebSocket. Sem WithWeatherWatcher, o hub cria o próprio agendador.
+func NewHub(
+	cepService services.CEPService,
+	weatherService services.WeatherService,
+	temperatureService services.TemperatureService,
+	opts ...Option,
+) *Hub {
+	h := &Hub{
+		cepService:         cepService,
+		temperatureService: temperatureService,
+		maxSubscriptions:   defaultMaxSubscriptions,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.watcher == nil {
+		h.watcher = services.NewWeatherWatcher(weatherService)
+	}
+	return h
+}
+
+// ServeHTTP abre a conexão WebSocket e a atende até o cliente desconectar
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// O upgrader já respondeu com o erro HTTP
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &connection{
+		hub:           h,
+		conn:          conn,
+		ctx:           ctx,
+		cancel:        cancel,
+		send:          make(chan serverMessage, sendBufferSize),
+		subscriptions: map[string]*subscription{},
+	}
+	go c.writeLoop()
+	c.readLoop()
+	c.close()
+}
+
+// lookupContext aplica o prazo da consulta de CEP, quando houver
+func (h *Hub) lookupContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if h.requestTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, h.requestTimeout)
+}
+
+// convert expressa uma temperatura em Celsius nas três escalas da resposta
+func (h *Hub) convert(celsius float64) models.TemperatureResponse {
+	fahrenheit, kelvin := h.temperatureService.ConvertTemperatures(celsius)
+	return models.TemperatureResponse{TempC: celsius, TempF: fahrenheit, TempK: kelvin}
+}
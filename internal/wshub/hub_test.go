@@ -0,0 +1,300 @@
+package wshub
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cep-temperatura/internal/models"
+	"cep-temperatura/internal/services"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCEPService é um mock do CEPService
+type MockCEPService struct {
+	mock.Mock
+}
+
+func (m *MockCEPService) ValidateCEP(cep string) bool {
+	args := m.Called(cep)
+	return args.Bool(0)
+}
+
+func (m *MockCEPService) GetLocation(ctx context.Context, cep string) (*models.CEPResponse, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CEPResponse), args.Error(1)
+}
+
+// MockWeatherService é um mock do WeatherService
+type MockWeatherService struct {
+	mock.Mock
+}
+
+func (m *MockWeatherService) GetTemperature(ctx context.Context, query models.WeatherQuery) (*models.WeatherResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResult), args.Error(1)
+}
+
+func (m *MockWeatherService) GetForecast(ctx context.Context, query models.WeatherQuery, options services.ForecastOptions) (*models.ForecastResult, error) {
+	return nil, services.ErrForecastUnsupported
+}
+
+func (m *MockWeatherService) GetHistory(ctx context.Context, query models.WeatherQuery, options services.HistoryOptions) (*models.HistoryResult, error) {
+	return nil, services.ErrHistoryUnsupported
+}
+
+var (
+	paulista   = &models.CEPResponse{CEP: "01310-100", Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	se         = &models.CEPResponse{CEP: "01001-000", Localidade: "São Paulo", UF: "SP", IBGE: "3550308"}
+	copacabana = &models.CEPResponse{CEP: "22070-002", Localidade: "Rio de Janeiro", UF: "RJ", IBGE: "3304557"}
+)
+
+func newTestHub(t *testing.T, opts ...Option) (*Hub, *MockWeatherService) {
+	hub, _, weatherService := newTestHubWithCEPs(t, opts...)
+	return hub, weatherService
+}
+
+func newTestHubWithCEPs(t *testing.T, opts ...Option) (*Hub, *MockCEPService, *MockWeatherService) {
+	cepService := new(MockCEPService)
+	weatherService := new(MockWeatherService)
+
+	cepService.On("ValidateCEP", mock.MatchedBy(func(cep string) bool { return cep != "123" })).Return(true)
+	cepService.On("ValidateCEP", "123").Return(false)
+	cepService.On("GetLocation", mock.Anything, mock.MatchedBy(func(cep string) bool { return strings.HasPrefix(cep, "01310") })).Return(paulista, nil)
+	cepService.On("GetLocation", mock.Anything, "01001000").Return(se, nil)
+	cepService.On("GetLocation", mock.Anything, "22070002").Return(copacabana, nil)
+	cepService.On("GetLocation", mock.Anything, "99999999").Return(nil, fmt.Errorf("viacep: %w", services.ErrCEPNotFound))
+	// Provedor lento: a consulta só termina quando o contexto é cancelado
+	cepService.On("GetLocation", mock.Anything, "69900000").Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, services.ErrUpstreamTimeout)
+
+	watcher := services.NewWeatherWatcher(weatherService, services.WithPollInterval(10*time.Millisecond))
+	opts = append([]Option{WithWeatherWatcher(watcher)}, opts...)
+	return NewHub(cepService, weatherService, services.NewTemperatureService(), opts...), cepService, weatherService
+}
+
+// dial conecta um cliente WebSocket ao hub
+func dial(t *testing.T, hub *Hub) *websocket.Conn {
+	server := httptest.NewServer(hub)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// send envia uma mensagem ao hub
+func send(t *testing.T, conn *websocket.Conn, message string) {
+	t.Helper()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(message)))
+}
+
+// receive lê a próxima mensagem do hub
+func receive(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// receiveAll lê as próximas n mensagens, sem depender da ordem entre CEPs diferentes
+func receiveAll(t *testing.T, conn *websocket.Conn, n int) []string {
+	t.Helper()
+	messages := make([]string, n)
+	for i := range messages {
+		messages[i] = receive(t, conn)
+	}
+	return messages
+}
+
+func TestHub_SubscribeMultipleCEPs(t *testing.T) {
+	hub, weatherService := newTestHub(t)
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(paulista)).Return(&models.WeatherResult{TempC: 25}, nil)
+	weatherService.On("GetTemperature", mock.Anything, services.WeatherQueryFor(copacabana)).Return(&models.WeatherResult{TempC: 30}, nil)
+
+	conn := dial(t, hub)
+	send(t, conn, `{"action":"subscribe","ceps":["01310-100","22070002"]}`)
+
+	// As consultas repetidas com a mesma temperatura não geram novas mensagens
+	assert.ElementsMatch(t, []string{
+		`{"type":"subscribed","cep":"01310-100"}`,
+		`{"type":"subscribed","cep":"22070002"}`,
+		`{"type":"temperature","cep":"01310-100","temp_C":25,"temp_F":77,"temp_K":298.15}`,
+		`{"type":"temperature","cep":"22070002","temp_C":30,"temp_F":86,"temp_K":303.15}`,
+	}, receiveAll(t, conn, 4))
+}
+
+func TestHub_Changes(t *testing.T) {
+	hub, weatherService := newTestHub(t)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil).Times(2)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(nil, services.ErrUpstreamTimeout).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 30}, nil)
+
+	conn := dial(t, hub)
+	send(t, conn, `{"action":"subscribe","cep":"01310100"}`)
+
+	assert.Equal(t, `{"type":"subscribed","cep":"01310100"}`, receive(t, conn))
+	assert.Equal(t, `{"type":"temperature","cep":"01310100","temp_C":25,"temp_F":77,"temp_K":298.15}`, receive(t, conn))
+	assert.Equal(t, `{"type":"error","cep":"01310100","code":"upstream_timeout","message":"upstream timeout"}`, receive(t, conn))
+	assert.Equal(t, `{"type":"temperature","cep":"01310100","temp_C":30,"temp_F":86,"temp_K":303.15}`, receive(t, conn))
+}
+
+func TestHub_SharedPoller(t *testing.T) {
+	hub, weatherService := newTestHub(t)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	first := dial(t, hub)
+	second := dial(t, hub)
+	send(t, first, `{"action":"subscribe","cep":"01310100"}`)
+	send(t, second, `{"action":"subscribe","cep":"01001000"}`)
+	receiveAll(t, first, 2)
+	receiveAll(t, second, 2)
+
+	// CEPs do mesmo município, em conexões diferentes, compartilham a consulta
+	assert.Equal(t, 1, hub.watcher.Pollers())
+
+	send(t, first, `{"action":"unsubscribe","cep":"01310100"}`)
+	assert.Equal(t, `{"type":"unsubscribed","cep":"01310100"}`, receive(t, first))
+	assert.Equal(t, 1, hub.watcher.Pollers())
+
+	// A consulta para quando a última conexão que acompanha o município desconecta
+	second.Close()
+	assert.Eventually(t, func() bool { return hub.watcher.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub, weatherService := newTestHub(t)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil).Once()
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 30}, nil)
+
+	conn := dial(t, hub)
+	send(t, conn, `{"action":"subscribe","cep":"01310100"}`)
+	receiveAll(t, conn, 2)
+
+	send(t, conn, `{"action":"unsubscribe","cep":"01310-100"}`)
+	messages := receiveAll(t, conn, 1)
+	if strings.Contains(messages[0], `"temperature"`) {
+		// A mudança para 30 °C pode ter chegado antes do cancelamento
+		messages = receiveAll(t, conn, 1)
+	}
+	assert.Equal(t, `{"type":"unsubscribed","cep":"01310-100"}`, messages[0])
+	assert.Eventually(t, func() bool { return hub.watcher.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestHub_SubscriptionLimit(t *testing.T) {
+	hub, weatherService := newTestHub(t, WithMaxSubscriptions(1))
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	conn := dial(t, hub)
+	send(t, conn, `{"action":"subscribe","cep":"01310100"}`)
+	receiveAll(t, conn, 2)
+
+	send(t, conn, `{"action":"subscribe","cep":"22070002"}`)
+	assert.Equal(t, `{"type":"error","cep":"22070002","code":"subscription_limit","message":"subscription limit reached"}`, receive(t, conn))
+
+	// Assinar de novo um CEP acompanhado não conta para o limite
+	send(t, conn, `{"action":"subscribe","cep":"01310-100"}`)
+	assert.Equal(t, `{"type":"subscribed","cep":"01310-100"}`, receive(t, conn))
+	assert.Equal(t, 1, hub.watcher.Pollers())
+}
+
+func TestHub_SlowCEPLookup(t *testing.T) {
+	hub, weatherService := newTestHub(t)
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	conn := dial(t, hub)
+	send(t, conn, `{"action":"subscribe","cep":"69900000"}`)
+
+	// A consulta lenta não atrasa as mensagens seguintes
+	send(t, conn, `{"action":"subscribe","cep":"01310100"}`)
+	assert.ElementsMatch(t, []string{
+		`{"type":"subscribed","cep":"01310100"}`,
+		`{"type":"temperature","cep":"01310100","temp_C":25,"temp_F":77,"temp_K":298.15}`,
+	}, receiveAll(t, conn, 2))
+
+	// Cancelada durante a consulta, a assinatura termina sem erro
+	send(t, conn, `{"action":"unsubscribe","cep":"69900-000"}`)
+	assert.Equal(t, `{"type":"unsubscribed","cep":"69900-000"}`, receive(t, conn))
+	send(t, conn, `{"action":"unsubscribe","cep":"01310100"}`)
+	assert.Equal(t, `{"type":"unsubscribed","cep":"01310100"}`, receive(t, conn))
+	assert.Eventually(t, func() bool { return hub.watcher.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestHub_NormalizedCEP(t *testing.T) {
+	hub, cepService, weatherService := newTestHubWithCEPs(t, WithMaxSubscriptions(1))
+	weatherService.On("GetTemperature", mock.Anything, mock.Anything).Return(&models.WeatherResult{TempC: 25}, nil)
+
+	conn := dial(t, hub)
+	send(t, conn, `{"action":"subscribe","cep":"01310 100"}`)
+	receiveAll(t, conn, 2)
+
+	// Com espaço, com hífen ou sem separador, é a mesma assinatura
+	send(t, conn, `{"action":"subscribe","cep":"01310-100"}`)
+	assert.Equal(t, `{"type":"subscribed","cep":"01310-100"}`, receive(t, conn))
+	cepService.AssertNumberOfCalls(t, "GetLocation", 1)
+
+	send(t, conn, `{"action":"unsubscribe","cep":"01310100"}`)
+	assert.Equal(t, `{"type":"unsubscribed","cep":"01310100"}`, receive(t, conn))
+	assert.Eventually(t, func() bool { return hub.watcher.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestHub_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "CEP inválido",
+			message:  `{"action":"subscribe","cep":"123"}`,
+			expected: `{"type":"error","cep":"123","code":"invalid_zipcode","message":"invalid zipcode"}`,
+		},
+		{
+			name:     "CEP não encontrado",
+			message:  `{"action":"subscribe","cep":"99999999"}`,
+			expected: `{"type":"error","cep":"99999999","code":"zipcode_not_found","message":"can not find zipcode"}`,
+		},
+		{
+			name:     "JSON inválido",
+			message:  `subscribe 01310100`,
+			expected: `{"type":"error","code":"invalid_message","message":"invalid message"}`,
+		},
+		{
+			name:     "sem CEP",
+			message:  `{"action":"subscribe"}`,
+			expected: `{"type":"error","code":"invalid_message","message":"invalid message: cep is required"}`,
+		},
+		{
+			name:     "ação desconhecida",
+			message:  `{"action":"watch","cep":"01310100"}`,
+			expected: `{"type":"error","code":"invalid_message","message":"invalid message: unknown action \"watch\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub, _ := newTestHub(t)
+			conn := dial(t, hub)
+
+			send(t, conn, tt.message)
+			assert.Equal(t, tt.expected, receive(t, conn))
+			assert.Equal(t, 0, hub.watcher.Pollers())
+		})
+	}
+}